@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/vidyasagar/tsurf/internal/storage"
+)
+
+// runBookmarks handles `tsurf bookmarks import <file>` and
+// `tsurf bookmarks export [file]`.
+func runBookmarks(args []string) error {
+	usage := fmt.Errorf("usage: tsurf bookmarks import <file> | export [file]")
+	if len(args) == 0 {
+		return usage
+	}
+
+	dataDir, err := storage.DataDir()
+	if err != nil {
+		return err
+	}
+	db, err := storage.OpenDB(dataDir)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	bookmarks := storage.NewBookmarkStore(db)
+
+	switch args[0] {
+	case "import":
+		if len(args) != 2 {
+			return usage
+		}
+		data, err := os.ReadFile(args[1])
+		if err != nil {
+			return err
+		}
+		parsed, err := storage.ParseBookmarkFile(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[1], err)
+		}
+		added, updated := bookmarks.Import(parsed)
+		fmt.Printf("Imported %d bookmarks (%d new, %d retagged, %d already present)\n",
+			len(parsed), added, updated, len(parsed)-added-updated)
+		return nil
+
+	case "export":
+		var w io.Writer = os.Stdout
+		if len(args) > 1 {
+			f, err := os.Create(args[1])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		return storage.ExportNetscape(w, bookmarks.List())
+	}
+	return usage
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vidyasagar/tsurf/internal/browser"
+	"github.com/vidyasagar/tsurf/internal/storage"
+)
+
+// dumpOptions are the flags of `tsurf dump`.
+type dumpOptions struct {
+	width   int
+	noColor bool
+	format  string // "text", "markdown" or "json"
+	reader  bool   // extract the article, or keep the whole page
+}
+
+// runDump handles `tsurf dump [flags] <url>`: the page is fetched and
+// rendered as in the browser, and printed to stdout followed by its links.
+func runDump(args []string) error {
+	var opts dumpOptions
+	var reader string
+
+	fs := flag.NewFlagSet("dump", flag.ContinueOnError)
+	fs.IntVar(&opts.width, "width", 80, "wrap text at this many columns")
+	fs.BoolVar(&opts.noColor, "no-color", os.Getenv("NO_COLOR") != "", "print plain text without colors")
+	fs.StringVar(&opts.format, "format", "text", "output format: text, markdown or json")
+	fs.StringVar(&reader, "reader", "on", "reader mode: on extracts the article, off keeps the whole page")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: tsurf dump [flags] <url>\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("dump takes one URL")
+	}
+
+	switch opts.format {
+	case "text", "markdown", "md", "json":
+	default:
+		return fmt.Errorf("unknown format %q (text, markdown or json)", opts.format)
+	}
+	switch reader {
+	case "on":
+		opts.reader = true
+	case "off":
+	default:
+		return fmt.Errorf("--reader must be on or off")
+	}
+
+	// Share the browser's HTTP cache, so --offline works here too.
+	if dataDir, err := storage.DataDir(); err == nil {
+		browser.EnableHTTPCache(filepath.Join(dataDir, "cache"))
+	}
+
+	return dump(os.Stdout, fs.Arg(0), opts)
+}
+
+// dump fetches url and writes it to w in the chosen format.
+func dump(w io.Writer, url string, opts dumpOptions) error {
+	result, err := browser.NewFetcher().FetchWithContext(context.Background(), url)
+	if err != nil {
+		return err
+	}
+
+	extract := browser.Extract
+	if !opts.reader {
+		extract = browser.ExtractFull
+	}
+	article, err := extract(result)
+	if err != nil {
+		return err
+	}
+
+	switch opts.format {
+	case "markdown", "md":
+		md, links, err := browser.Markdown(article)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(w, strings.TrimRight(md, "\n")+"\n")
+		if len(links) > 0 {
+			fmt.Fprint(w, "\n")
+			for _, l := range links {
+				fmt.Fprintf(w, "[%d]: %s\n", l.Index, l.URL)
+			}
+		}
+		return nil
+
+	case "json":
+		page := browser.Render(article, opts.width)
+		return browser.WriteJSON(w, browser.ExportArticle(article, page))
+	}
+
+	page := browser.Render(article, opts.width)
+	content := page.Content
+	if opts.noColor {
+		content = browser.PlainText(content)
+	}
+	fmt.Fprint(w, strings.TrimRight(content, "\n")+"\n")
+	writeReferences(w, page.Links)
+	return nil
+}
+
+// writeReferences prints a numbered list of link targets, as lynx -dump does.
+func writeReferences(w io.Writer, links []browser.Link) {
+	if len(links) == 0 {
+		return
+	}
+	fmt.Fprint(w, "\nReferences\n\n")
+	for _, l := range links {
+		fmt.Fprintf(w, "%4d. %s\n", l.Index, l.URL)
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/vidyasagar/tsurf/internal/app"
+	"github.com/vidyasagar/tsurf/internal/browser"
+	"github.com/vidyasagar/tsurf/internal/storage"
 	"github.com/vidyasagar/tsurf/internal/theme"
 )
 
@@ -18,13 +22,17 @@ func main() {
 	var (
 		themeName   string
 		showVersion bool
+		offline     bool
 	)
 
-	flag.StringVar(&themeName, "theme", "default", "color theme (default, gruvbox, catppuccin, nord, dracula, solarized, tokyonight)")
+	flag.StringVar(&themeName, "theme", "default", "color theme (default, gruvbox, catppuccin, nord, dracula, solarized, tokyonight, or a custom theme)")
 	flag.BoolVar(&showVersion, "version", false, "show version")
+	flag.BoolVar(&offline, "offline", false, "serve pages and feeds from the local cache only")
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "tsurf - a terminal web browser for developers\n\n")
-		fmt.Fprintf(os.Stderr, "Usage: tsurf [flags] [url]\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: tsurf [flags] [url]\n")
+		fmt.Fprintf(os.Stderr, "       tsurf dump [--width n] [--no-color] [--format text|markdown|json] [--reader on|off] <url>\n")
+		fmt.Fprintf(os.Stderr, "       tsurf bookmarks import <file> | export [file]\n\n")
 		fmt.Fprintf(os.Stderr, "Flags:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
@@ -33,6 +41,9 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  tsurf golang.org               # auto-adds https://\n")
 		fmt.Fprintf(os.Stderr, "  tsurf \"how to use goroutines\"   # search DuckDuckGo\n")
 		fmt.Fprintf(os.Stderr, "  tsurf --theme catppuccin        # use catppuccin theme\n")
+		fmt.Fprintf(os.Stderr, "  tsurf --offline                 # browse cached pages without network\n")
+		fmt.Fprintf(os.Stderr, "  tsurf dump go.dev/blog | less -R  # print a page's readable text\n")
+		fmt.Fprintf(os.Stderr, "  tsurf bookmarks import bookmarks.html  # import a browser's bookmarks\n")
 	}
 	flag.Parse()
 
@@ -41,12 +52,34 @@ func main() {
 		os.Exit(0)
 	}
 
-	// Apply theme.
+	if flag.Arg(0) == "bookmarks" {
+		if err := runBookmarks(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Load custom themes, then apply the chosen one.
+	if dir, err := storage.ConfigDir(); err == nil {
+		if err := theme.LoadDir(filepath.Join(dir, "themes")); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
 	if !theme.Set(themeName) {
-		fmt.Fprintf(os.Stderr, "Unknown theme: %s\nAvailable: default, gruvbox, catppuccin, nord, dracula, solarized, tokyonight\n", themeName)
+		fmt.Fprintf(os.Stderr, "Unknown theme: %s\nAvailable: %s\n", themeName, strings.Join(theme.List(), ", "))
 		os.Exit(1)
 	}
 
+	if flag.Arg(0) == "dump" {
+		browser.SharedTransport.SetOffline(offline)
+		if err := runDump(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Get optional URL argument.
 	var startURL string
 	if flag.NArg() > 0 {
@@ -54,6 +87,9 @@ func main() {
 	}
 
 	m := app.New(startURL)
+	if offline {
+		m.SetOffline(true)
+	}
 	p := tea.NewProgram(m,
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
@@ -3,11 +3,13 @@ package app
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	lru "github.com/hashicorp/golang-lru/v2"
@@ -22,13 +24,15 @@ import (
 type Mode int
 
 const (
-	ModeNormal  Mode = iota
-	ModeInsert       // URL bar focused
-	ModeCommand      // command bar active
-	ModeFollow       // link follow mode
-	ModeSearch       // search mode
-	ModeHistory      // history panel active
-	ModeLeader       // leader key palette active
+	ModeNormal    Mode = iota
+	ModeInsert         // URL bar focused
+	ModeCommand        // command bar active
+	ModeFollow         // link follow mode
+	ModeField          // form field selection
+	ModeSearch         // search mode
+	ModeHistory        // history panel active
+	ModeLeader         // leader key palette active
+	ModeBookmarks      // bookmark manager active
 )
 
 // tabState holds per-tab state.
@@ -36,9 +40,56 @@ type tabState struct {
 	viewport   ui.PageViewport
 	history    *browser.History
 	page       *browser.RenderedPage
-	feedLinks  []browser.Link // links from feed/search/storage pages
+	article    *browser.Article // source of page, kept to re-render at a new width
+	pageWidth  int              // width page was rendered at
+	feedLinks  []browser.Link   // links from feed/search/storage pages
+	feedData   *feeds.Data      // items behind a feed page, for :export json
+	listPage   listRenderer     // renders a bookmarks, cookies or other list page again
+	thread     *threadView      // HN comment thread, for folding
 	loading    bool
 	cancelFunc context.CancelFunc
+	cachedAt   time.Time // when the shown content was stored; set only offline
+
+	// Scroll offset to apply once the pending load completes (session restore).
+	pendingScroll int
+
+	// Read-later page being read, the percentage saved for it, and the
+	// scroll offset it was last seen at.
+	readingURL   string
+	readProgress int
+	readOffset   int
+}
+
+// clearPage forgets the rendered page when the tab shows other content.
+func (ts *tabState) clearPage() {
+	ts.page = nil
+	ts.article = nil
+	ts.feedData = nil
+	ts.listPage = nil
+	ts.thread = nil
+	ts.readingURL = ""
+}
+
+// listRenderer renders a list page built from local data at width.
+type listRenderer func(width int) (string, []browser.Link)
+
+// showList shows the list page render draws, keeping render to draw it
+// again after a theme change. Returns the page's links.
+func (ts *tabState) showList(width int, render listRenderer) []browser.Link {
+	ts.clearPage()
+	ts.listPage = render
+	content, links := render(width)
+	ts.feedLinks = links
+	ts.viewport.SetContent(content)
+	return links
+}
+
+// restoreScroll applies and clears any pending scroll offset.
+func (ts *tabState) restoreScroll() {
+	if ts.pendingScroll > 0 {
+		ts.viewport.SetYOffset(ts.pendingScroll)
+		ts.pendingScroll = 0
+	}
 }
 
 // Model is the top-level bubbletea model for tsurf.
@@ -53,52 +104,96 @@ type Model struct {
 	// Per-tab state
 	tabStates map[int]*tabState
 
+	// Split panes
+	panes            [2]int // tab IDs shown in the first and second pane
+	pendingWindowCmd bool   // Ctrl+w pressed while split, awaiting h/j/k/l etc.
+
 	// Shared state
-	fetcher   *browser.Fetcher
-	pageCache *lru.Cache[string, *browser.RenderedPage] // LRU cache for rendered pages
-	keys      KeyMap
-	mode      Mode
-	width     int
-	height    int
-	lastGKey  bool // for "gg" detection
-	ready     bool
-	startURL  string
+	fetcher     *browser.Fetcher
+	pageCache   *lru.Cache[pageKey, *cachedPage] // LRU cache for rendered pages
+	keys        KeyMap
+	mode        Mode
+	width       int
+	height      int
+	pendingKeys []string // keys typed so far of a multi-key binding ("g" of "gg")
+	barsHeight  int      // URL and command bar heights at the last layout
+	ready       bool
+	startURL    string
 
 	// Feeds
 	hnClient     *feeds.HNClient
 	redditClient *feeds.RedditClient
 	rssClient    *feeds.RSSClient
-	githubClient *feeds.GitHubClient
+
+	// Site handlers rendering API-backed sites in place of their HTML.
+	sites *feeds.SiteRegistry
 
 	// Storage
 	db        *storage.DB
 	bookmarks *storage.BookmarkStore
 	readLater *storage.ReadLaterStore
+	sessions  *storage.SessionStore
+	cmdLines  *storage.CommandHistoryStore
+	cookies   *storage.CookieJar
 	config    *storage.Config
 
+	// Cookies listed on the :cookies page, in display order, for :cookies rm.
+	cookieList []storage.Cookie
+
+	// Where follow mode opens the chosen link (f, gf or F).
+	followTarget followTarget
+
+	// Recently closed tabs, most recent last, for undo (u).
+	closedTabs []closedTab
+
+	// Form field being edited in insert mode, if any.
+	editingField *browser.FormField
+
+	// Re-rendering after layout changes: set by layout when a page's width
+	// no longer matches its pane, and the debounce generation.
+	rerenderPending bool
+	rerenderSeq     int
+
+	// Session restored on the first WindowSizeMsg, once viewports have a size.
+	pendingSession *storage.Session
+
 	// History
 	historyPanel ui.HistoryPanel
 	historyStore *storage.HistoryStore
 
+	// Bookmark manager
+	bookmarkPanel ui.BookmarkPanel
+
 	// Leader key
 	leaderPanel ui.LeaderPanel
 }
 
 // pageLoadedMsg is sent when a page finishes loading.
 type pageLoadedMsg struct {
-	tabID int
-	page  *browser.RenderedPage
-	url   string
-	err   error
+	tabID       int
+	page        *browser.RenderedPage
+	article     *browser.Article
+	width       int // width page was rendered at
+	url         string
+	pushHistory bool // add the final URL to the tab history (form submissions)
+	err         error
 }
 
 // feedLoadedMsg is sent when a feed finishes loading.
 type feedLoadedMsg struct {
-	tabID   int
-	content string
-	title   string
-	links   []browser.Link
-	err     error
+	tabID    int
+	content  string
+	title    string
+	links    []browser.Link
+	data     *feeds.Data
+	cachedAt time.Time // when the oldest response was stored; set only offline
+	err      error
+}
+
+// suggestionsMsg carries the URL bar suggestions for what had been typed.
+type suggestionsMsg struct {
+	query       string
+	suggestions []storage.Suggestion
 }
 
 // leaderTimeoutMsg is sent when the leader key palette times out.
@@ -110,7 +205,7 @@ func New(startURL string) Model {
 	initialTab := tb.ActiveTab()
 
 	// Initialize page cache (stores up to 50 rendered pages for instant back/forward).
-	pageCache, _ := lru.New[string, *browser.RenderedPage](50)
+	pageCache, _ := lru.New[pageKey, *cachedPage](50)
 
 	m := Model{
 		tabBar:     tb,
@@ -129,23 +224,62 @@ func New(startURL string) Model {
 		hnClient:     feeds.NewHNClient(),
 		redditClient: feeds.NewRedditClient(),
 		rssClient:    feeds.NewRSSClient(),
-		githubClient: feeds.NewGitHubClient(),
 	}
 
 	// Initialize storage (best-effort, non-fatal on error).
 	dataDir, err := storage.DataDir()
 	if err == nil {
+		browser.EnableHTTPCache(filepath.Join(dataDir, "cache"))
 		db, dbErr := storage.OpenDB(dataDir)
 		if dbErr == nil {
 			m.db = db
 			m.bookmarks = storage.NewBookmarkStore(db)
 			m.readLater = storage.NewReadLaterStore(db)
 			m.historyStore = storage.NewHistoryStore(db)
+			m.sessions = storage.NewSessionStore(db)
+			m.cmdLines = storage.NewCommandHistoryStore(db)
+			m.commandBar.SetHistory(m.cmdLines.List())
 		}
 	}
 	m.config, _ = storage.LoadConfig()
+	if m.db != nil {
+		policy, allow := storage.CookiePolicyFirstParty, []string(nil)
+		if m.config != nil {
+			policy, allow = m.config.CookiePolicy, m.config.CookieAllow
+		}
+		m.cookies = storage.NewCookieJar(m.db, policy, allow)
+		m.fetcher.SetCookieJar(m.cookies)
+	}
+
+	// Reopen the previous session's tabs when launched without a URL.
+	if startURL == "" && m.sessions != nil && m.config != nil && m.config.RestoreSession {
+		if s, err := m.sessions.Load(storage.LastSession); err == nil && len(s.Tabs) > 0 {
+			m.pendingSession = s
+		}
+	}
+	var plugins []feeds.SiteHandler
+	if m.config != nil {
+		var err error
+		plugins, err = loadPlugins(m.config.Plugins)
+		if err != nil {
+			m.statusBar.SetMessage(fmt.Sprintf("Plugins: %s", err))
+		}
+	}
+	m.sites = feeds.DefaultSites(m.hnClient, m.redditClient, feeds.NewGitHubClient(), plugins...)
+	if m.config != nil {
+		keys, err := NewKeyMap(m.config.Keys)
+		m.keys = keys
+		if err != nil {
+			m.statusBar.SetMessage(fmt.Sprintf("Key bindings: %s", strings.ReplaceAll(err.Error(), "\n", "; ")))
+		}
+		if err := m.sites.Configure(m.config.SiteOrder, m.config.SitesDisabled); err != nil {
+			m.statusBar.SetMessage(fmt.Sprintf("Config: %s", err))
+		}
+	}
 	m.historyPanel = ui.NewHistoryPanel()
+	m.bookmarkPanel = ui.NewBookmarkPanel()
 	m.leaderPanel = ui.NewLeaderPanel()
+	m.leaderPanel.SetGroups(m.keys.leaderGroups())
 
 	// Initialize first tab state.
 	m.tabStates[initialTab.ID] = &tabState{
@@ -166,6 +300,28 @@ func (m Model) Init() tea.Cmd {
 
 // Update implements tea.Model.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	mm, ok := model.(Model)
+	if !ok {
+		return model, cmd
+	}
+	// The URL and command bars grow with their popups; keep the pages
+	// between them.
+	if h := mm.urlBar.Height() + mm.commandBar.Height(); h != mm.barsHeight {
+		mm.barsHeight = h
+		mm.layout()
+	}
+	mm.trackReading()
+	// A resize or split change left pages wrapped at the old width.
+	if mm.rerenderPending {
+		mm.rerenderPending = false
+		return mm, tea.Batch(cmd, mm.scheduleRerender())
+	}
+	return mm, cmd
+}
+
+// update handles a message for Update.
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
@@ -174,16 +330,44 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.ready = true
 		m.layout()
+		if m.pendingSession != nil {
+			s := m.pendingSession
+			m.pendingSession = nil
+			cmd := m.restoreSession(s)
+			return m, cmd
+		}
 		return m, nil
 
 	case pageLoadedMsg:
 		return m.handlePageLoaded(msg)
 
+	case rerenderTickMsg:
+		if msg.seq == m.rerenderSeq {
+			return m, m.rerenderTabs(false)
+		}
+		return m, nil
+
+	case pageRenderedMsg:
+		m.handlePageRendered(msg)
+		return m, nil
+
 	case feedLoadedMsg:
 		return m.handleFeedLoaded(msg)
 
+	case hnRepliesMsg:
+		m.handleRepliesLoaded(msg)
+		return m, nil
+
+	case suggestionsMsg:
+		// Results for text that has since been typed over are dropped.
+		if m.urlBar.IsActive() && msg.query == m.urlBar.Typed() {
+			m.urlBar.SetSuggestions(msg.suggestions)
+		}
+		return m, nil
+
 	case leaderTimeoutMsg:
 		if m.mode == ModeLeader {
+			m.pendingKeys = nil
 			m.leaderPanel.Hide()
 			m.mode = ModeNormal
 			m.statusBar.SetMode("NORMAL")
@@ -221,10 +405,10 @@ func (m Model) View() string {
 	// URL bar.
 	sections = append(sections, m.urlBar.View())
 
-	// Viewport (with optional history panel on the left).
+	// Viewport (with optional history or bookmark panel on the left).
 	ts := m.activeTabState()
 	if ts != nil {
-		if m.historyPanel.IsVisible() {
+		if panel := m.sidePanelView(); panel != "" {
 			t := theme.Current
 			dividerStyle := lipgloss.NewStyle().
 				Foreground(t.Border).
@@ -232,12 +416,9 @@ func (m Model) View() string {
 
 			// Calculate divider height.
 			tabBarHeight := 1
-			urlBarHeight := 3
+			urlBarHeight := m.urlBar.Height()
 			statusBarHeight := 1
-			commandBarHeight := 0
-			if m.commandBar.IsActive() {
-				commandBarHeight = 1
-			}
+			commandBarHeight := m.commandBar.Height()
 			dividerHeight := m.height - tabBarHeight - urlBarHeight - statusBarHeight - commandBarHeight
 			if dividerHeight < 1 {
 				dividerHeight = 1
@@ -250,13 +431,13 @@ func (m Model) View() string {
 			divider := dividerStyle.Render(strings.Join(dividerLines, "\n"))
 
 			content := lipgloss.JoinHorizontal(lipgloss.Top,
-				m.historyPanel.View(),
+				panel,
 				divider,
-				ts.viewport.View(),
+				m.pagesView(),
 			)
 			sections = append(sections, content)
 		} else {
-			sections = append(sections, ts.viewport.View())
+			sections = append(sections, m.pagesView())
 		}
 	} else {
 		sections = append(sections, "")
@@ -284,58 +465,80 @@ func (m Model) View() string {
 	return result
 }
 
+// sidePanelView renders the history or bookmark panel, whichever is open,
+// or returns "" if neither is.
+func (m *Model) sidePanelView() string {
+	switch {
+	case m.historyPanel.IsVisible():
+		return m.historyPanel.View()
+	case m.bookmarkPanel.IsVisible():
+		return m.bookmarkPanel.View()
+	}
+	return ""
+}
+
 // layout recalculates dimensions for all components.
 func (m *Model) layout() {
 	m.tabBar.SetWidth(m.width)
 	m.urlBar.SetWidth(m.width)
 	m.statusBar.SetWidth(m.width)
 	m.commandBar.SetWidth(m.width)
-	m.splitPane.SetSize(m.width, m.height)
 
 	// Calculate viewport height.
 	tabBarHeight := 1
-	urlBarHeight := 3 // border adds height
+	urlBarHeight := m.urlBar.Height()
 	statusBarHeight := 1
-	commandBarHeight := 0
-	if m.commandBar.IsActive() {
-		commandBarHeight = 1
-	}
+	commandBarHeight := m.commandBar.Height()
 	viewportHeight := m.height - tabBarHeight - urlBarHeight - statusBarHeight - commandBarHeight
 	if viewportHeight < 1 {
 		viewportHeight = 1
 	}
 
-	// Calculate viewport width (narrower when history panel is shown).
+	// Calculate viewport width (narrower when a side panel is shown).
 	viewportWidth := m.width
-	if m.historyPanel.IsVisible() {
+	if m.historyPanel.IsVisible() || m.bookmarkPanel.IsVisible() {
 		panelWidth := m.width * 30 / 100
 		if panelWidth < 20 {
 			panelWidth = 20
 		}
 		m.historyPanel.SetSize(panelWidth, viewportHeight)
+		m.bookmarkPanel.SetSize(panelWidth, viewportHeight)
 		viewportWidth = m.width - panelWidth - 1 // -1 for divider
 	}
 
-	// Set viewport size for all tabs.
+	// Set viewport size for all tabs, then shrink the ones shown in split panes.
 	for _, ts := range m.tabStates {
 		ts.viewport.SetSize(viewportWidth, viewportHeight)
 	}
+	m.splitPane.SetSize(viewportWidth, viewportHeight)
+	m.layoutPanes()
+
+	for _, ts := range m.tabStates {
+		if ts.article != nil && ts.pageWidth != m.renderWidth(ts) {
+			m.rerenderPending = true
+		}
+		if ts.thread != nil && ts.thread.width != m.renderWidth(ts) {
+			m.rerenderPending = true
+		}
+	}
 }
 
 // handleKeyMsg processes key events based on current mode.
 func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Always allow Ctrl+C to quit.
 	if msg.String() == "ctrl+c" {
-		return m, tea.Quit
+		return m, m.quit()
 	}
 
 	switch m.mode {
 	case ModeInsert:
 		return m.handleInsertMode(msg)
-	case ModeCommand, ModeSearch, ModeFollow:
+	case ModeCommand, ModeSearch, ModeFollow, ModeField:
 		return m.handleCommandMode(msg)
 	case ModeHistory:
 		return m.handleHistoryMode(msg)
+	case ModeBookmarks:
+		return m.handleBookmarkMode(msg)
 	case ModeLeader:
 		return m.handleLeaderMode(msg)
 	default:
@@ -345,18 +548,48 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleNormalMode processes keys in normal (browsing) mode.
 func (m Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.pendingWindowCmd {
+		return m.handleWindowCommand(msg)
+	}
+
+	typed := append(slices.Clone(m.pendingKeys), msg.String())
+	action, pending := m.keys.Normal.Match(typed)
+	if action == "" && !pending && len(typed) > 1 {
+		// Not a known sequence: drop the keys typed before this one.
+		typed = typed[len(typed)-1:]
+		action, pending = m.keys.Normal.Match(typed)
+	}
+	if pending {
+		m.pendingKeys = typed
+		return m, nil
+	}
+	m.pendingKeys = nil
+
+	if action != "" {
+		return m.runAction(action)
+	}
+
+	// Forward to viewport for mouse scroll, etc.
+	if ts := m.activeTabState(); ts != nil {
+		vp, cmd := ts.viewport.Update(msg)
+		ts.viewport = *vp
+		m.syncStatusBar()
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// runAction performs a bound action from normal or leader mode.
+func (m Model) runAction(action Action) (tea.Model, tea.Cmd) {
 	ts := m.activeTabState()
 
-	switch {
-	// Quit.
-	case key.Matches(msg, m.keys.Quit) && msg.String() != "ctrl+c":
-		if msg.String() == "q" {
-			return m, tea.Quit
-		}
+	switch action {
+	case ActionQuit:
+		return m, m.quit()
 
-	// Leader key (Space) — open shortcut palette.
-	case msg.String() == " ":
-		m.lastGKey = false
+	// Leader key — open shortcut palette.
+	case ActionLeader:
 		m.leaderPanel.SetSize(m.width, m.height)
 		m.leaderPanel.Show()
 		m.mode = ModeLeader
@@ -365,255 +598,102 @@ func (m Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return leaderTimeoutMsg{}
 		})
 
-	// gg detection: first "g" sets flag, second "g" goes to top.
-	case msg.String() == "g":
-		if m.lastGKey {
-			m.lastGKey = false
-			if ts != nil {
-				ts.viewport.GotoTop()
-				m.syncStatusBar()
-			}
-			return m, nil
-		}
-		m.lastGKey = true
-		return m, nil
-
-	// gt detection: "t" after "g" switches to next tab.
-	case msg.String() == "t":
-		if m.lastGKey {
-			m.lastGKey = false
-			m.tabBar.NextTab()
-			m.syncTabUI()
-			return m, nil
-		}
-
-	// gT detection: "T" after "g" switches to prev tab.
-	case msg.String() == "T":
-		if m.lastGKey {
-			m.lastGKey = false
-			m.tabBar.PrevTab()
-			m.syncTabUI()
-			return m, nil
-		}
-
-	// Scroll down.
-	case key.Matches(msg, m.keys.ScrollDown):
-		m.lastGKey = false
+	// ── Navigation ──
+	case ActionScrollDown:
 		if ts != nil {
 			ts.viewport.LineDown(1)
 			m.syncStatusBar()
 		}
-		return m, nil
 
-	// Scroll up.
-	case key.Matches(msg, m.keys.ScrollUp):
-		m.lastGKey = false
+	case ActionScrollUp:
 		if ts != nil {
 			ts.viewport.LineUp(1)
 			m.syncStatusBar()
 		}
-		return m, nil
 
-	// Half page down.
-	case key.Matches(msg, m.keys.HalfPageDown):
-		m.lastGKey = false
+	case ActionHalfPageDown:
 		if ts != nil {
 			ts.viewport.HalfPageDown()
 			m.syncStatusBar()
 		}
-		return m, nil
 
-	// Half page up.
-	case key.Matches(msg, m.keys.HalfPageUp):
-		m.lastGKey = false
+	case ActionHalfPageUp:
 		if ts != nil {
 			ts.viewport.HalfPageUp()
 			m.syncStatusBar()
 		}
-		return m, nil
 
-	// Go to bottom.
-	case key.Matches(msg, m.keys.GotoBottom):
-		m.lastGKey = false
+	case ActionGotoTop:
+		if ts != nil {
+			ts.viewport.GotoTop()
+			m.syncStatusBar()
+		}
+
+	case ActionGotoBottom:
 		if ts != nil {
 			ts.viewport.GotoBottom()
 			m.syncStatusBar()
 		}
-		return m, nil
 
-	// Open URL.
-	case key.Matches(msg, m.keys.OpenURL):
-		m.lastGKey = false
+	// ── Browsing ──
+	case ActionOpenURL:
 		m.mode = ModeInsert
 		m.urlBar.Reset()
 		m.statusBar.SetMode("INSERT")
-		cmd := m.urlBar.Focus()
-		return m, cmd
+		return m, m.urlBar.Focus()
 
-	// Back.
-	case key.Matches(msg, m.keys.Back):
-		m.lastGKey = false
+	case ActionBack:
 		if ts != nil {
 			if url, ok := ts.history.Back(); ok {
 				return m, m.loadPage(url, false)
 			}
 		}
-		return m, nil
 
-	// Forward.
-	case key.Matches(msg, m.keys.Forward):
-		m.lastGKey = false
+	case ActionForward:
 		if ts != nil {
 			if url, ok := ts.history.Forward(); ok {
 				return m, m.loadPage(url, false)
 			}
 		}
-		return m, nil
 
-	// Reload.
-	case key.Matches(msg, m.keys.Reload):
-		m.lastGKey = false
+	case ActionReload:
 		if ts != nil {
-			current := ts.history.Current()
-			if current != "" {
+			if current := ts.history.Current(); current != "" {
 				return m, m.loadPage(current, false)
 			}
 		}
-		return m, nil
 
-	// Follow link.
-	case key.Matches(msg, m.keys.FollowLink):
-		m.lastGKey = false
-		m.mode = ModeFollow
-		m.statusBar.SetMode("FOLLOW")
-		cmd := m.commandBar.Open(ui.CommandFollow)
+	case ActionFollowLink:
+		cmd := m.startFollow(followHere)
 		return m, cmd
 
-	// New tab.
-	case key.Matches(msg, m.keys.NewTab):
-		m.lastGKey = false
-		m.tabBar.NewTab()
-		tab := m.tabBar.ActiveTab()
-		m.tabStates[tab.ID] = &tabState{
-			viewport: ui.NewPageViewport(),
-			history:  browser.NewHistory(),
-		}
-		m.layout()
-		m.syncTabUI()
-		return m, nil
-
-	// Close tab.
-	case key.Matches(msg, m.keys.CloseTab):
-		m.lastGKey = false
-		tab := m.tabBar.ActiveTab()
-		if m.tabBar.CloseCurrentTab() {
-			// Cancel any pending load.
-			if ts, ok := m.tabStates[tab.ID]; ok {
-				if ts.cancelFunc != nil {
-					ts.cancelFunc()
-				}
-				delete(m.tabStates, tab.ID)
-			}
-			m.syncTabUI()
-		} else {
-			// Last tab - quit.
-			return m, tea.Quit
-		}
-		return m, nil
-
-	// Next tab.
-	case key.Matches(msg, m.keys.NextTab):
-		m.lastGKey = false
-		m.tabBar.NextTab()
-		m.syncTabUI()
-		return m, nil
-
-	// Prev tab.
-	case key.Matches(msg, m.keys.PrevTab):
-		m.lastGKey = false
-		m.tabBar.PrevTab()
-		m.syncTabUI()
-		return m, nil
-
-	// Command mode.
-	case key.Matches(msg, m.keys.CommandMode):
-		m.lastGKey = false
-		m.mode = ModeCommand
-		m.statusBar.SetMode("COMMAND")
-		cmd := m.commandBar.Open(ui.CommandEx)
+	case ActionFollowNewTab:
+		cmd := m.startFollow(followNewTab)
 		return m, cmd
 
-	// Search mode.
-	case key.Matches(msg, m.keys.SearchMode):
-		m.lastGKey = false
-		m.mode = ModeSearch
-		m.statusBar.SetMode("SEARCH")
-		cmd := m.commandBar.Open(ui.CommandSearch)
+	case ActionFollowBg:
+		cmd := m.startFollow(followBackground)
 		return m, cmd
 
-	// Help.
-	case key.Matches(msg, m.keys.Help):
-		m.lastGKey = false
-		m.showHelp()
-		return m, nil
-
-	// Split vertical.
-	case key.Matches(msg, m.keys.SplitVertical):
-		m.lastGKey = false
-		m.splitPane.Split(ui.SplitVertical)
-		m.layout()
-		return m, nil
-
-	// Split close.
-	case key.Matches(msg, m.keys.SplitClose):
-		m.lastGKey = false
-		m.splitPane.Unsplit()
-		m.layout()
-		return m, nil
+	case ActionEditField:
+		cmd := m.openFieldPrompt()
+		return m, cmd
 
-	// Split toggle.
-	case key.Matches(msg, m.keys.SplitToggle):
-		m.lastGKey = false
-		m.splitPane.Toggle()
-		return m, nil
+	case ActionEditFirstField:
+		cmd := m.editFirstField()
+		return m, cmd
 
-	// Bookmark current page.
-	case key.Matches(msg, m.keys.Bookmark):
-		m.lastGKey = false
-		if m.bookmarks != nil && ts != nil {
-			tab := m.tabBar.ActiveTab()
-			if tab != nil && tab.URL != "" {
-				if m.bookmarks.Add(tab.URL, tab.Title) {
-					m.statusBar.SetMessage(fmt.Sprintf("Bookmarked: %s", tab.Title))
-				} else {
-					m.statusBar.SetMessage("Already bookmarked")
-				}
-			} else {
-				m.statusBar.SetMessage("No page to bookmark")
-			}
+	case ActionBookmark:
+		if ts != nil {
+			m.bookmarkCommand(nil)
 		}
-		return m, nil
 
-	// Read later.
-	case key.Matches(msg, m.keys.ReadLater):
-		m.lastGKey = false
+	case ActionReadLater:
 		if m.readLater != nil && ts != nil {
-			tab := m.tabBar.ActiveTab()
-			if tab != nil && tab.URL != "" {
-				if m.readLater.Add(tab.URL, tab.Title) {
-					m.statusBar.SetMessage(fmt.Sprintf("Added to read later: %s", tab.Title))
-				} else {
-					m.statusBar.SetMessage("Already in read later")
-				}
-			} else {
-				m.statusBar.SetMessage("No page to save")
-			}
+			m.saveReadLater(ts)
 		}
-		return m, nil
 
-	// Toggle history panel.
-	case key.Matches(msg, m.keys.HistoryToggle):
-		m.lastGKey = false
+	case ActionHistory:
 		if m.historyPanel.IsVisible() {
 			m.historyPanel.Hide()
 			m.mode = ModeNormal
@@ -623,28 +703,135 @@ func (m Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				entries := m.historyStore.List()
 				m.historyPanel.SetEntries(entries)
 			}
+			m.bookmarkPanel.Hide()
 			m.historyPanel.Show()
 			m.mode = ModeHistory
 			m.statusBar.SetMode("HISTORY")
 		}
 		m.layout()
-		return m, nil
-	}
 
-	// Reset g key if another key was pressed.
-	m.lastGKey = false
+	case ActionBookmarks:
+		return m.executeCommand("bookmarks")
 
-	// Forward to viewport for mouse scroll, etc.
-	if ts != nil {
-		vp, cmd := ts.viewport.Update(msg)
-		ts.viewport = *vp
-		m.syncStatusBar()
+	case ActionBookmarkManager:
+		if m.bookmarkPanel.IsVisible() {
+			m.closeBookmarkPanel()
+		} else {
+			m.openBookmarkPanel(nil)
+		}
+
+	case ActionReadLaterList:
+		return m.executeCommand("readlater")
+
+	// ── Tabs ──
+	case ActionNewTab:
+		m.tabBar.NewTab()
+		tab := m.tabBar.ActiveTab()
+		m.tabStates[tab.ID] = newTabState()
+		m.layout()
+		m.syncTabUI()
+
+	// Close tab, or start a Ctrl+w window command while split.
+	case ActionWindow:
+		if m.splitPane.IsSplit() {
+			m.pendingWindowCmd = true
+			m.statusBar.SetMessage("^W")
+			return m, nil
+		}
+		if !m.closeActiveTab() {
+			// Last tab - quit.
+			return m, m.quit()
+		}
+
+	case ActionCloseTab:
+		if !m.closeActiveTab() {
+			return m, m.quit()
+		}
+
+	case ActionUndoClose:
+		cmd := m.reopenClosedTab()
+		return m, cmd
+
+	case ActionNextTab:
+		m.tabBar.NextTab()
+		m.syncTabUI()
+
+	case ActionPrevTab:
+		m.tabBar.PrevTab()
+		m.syncTabUI()
+
+	// ── Feeds ──
+	case ActionHackerNews:
+		m.statusBar.SetLoading(true)
+		m.statusBar.SetMessage("Loading Hacker News...")
+		return m, m.fetchHN("top")
+
+	case ActionReddit:
+		return m, m.openCommand("reddit ")
+
+	case ActionWebSearch:
+		return m, m.openCommand("search ")
+
+	case ActionRSS:
+		return m, m.openCommand("rss ")
+
+	// ── Modes ──
+	case ActionCommand:
+		return m, m.openCommand("")
+
+	case ActionCommandHistory:
+		cmd := m.openCommand("")
+		m.commandBar.ShowHistory()
 		return m, cmd
+
+	case ActionSearch:
+		return m, m.openSearch()
+
+	case ActionSearchNext:
+		m.stepSearch(1)
+
+	case ActionSearchPrev:
+		m.stepSearch(-1)
+
+	case ActionHelp:
+		m.showHelp()
+
+	// ── Comment threads ──
+	case ActionFoldToggle, ActionFoldOpen, ActionFoldClose, ActionFoldOpenAll, ActionFoldCloseAll,
+		ActionNextComment, ActionPrevComment:
+		return m, m.threadAction(action)
+
+	// ── Splits and views ──
+	case ActionSplitVertical:
+		m.splitWindow(ui.SplitVertical)
+
+	case ActionSplitHorizontal:
+		m.splitWindow(ui.SplitHorizontal)
+
+	case ActionSplitClose:
+		m.unsplitWindow()
+
+	case ActionSplitToggle:
+		m.focusPane(1 - m.splitPane.Active)
+
+	case ActionThemeCycle:
+		return m.cycleTheme()
 	}
 
 	return m, nil
 }
 
+// openCommand opens the command bar, pre-filled with value.
+func (m *Model) openCommand(value string) tea.Cmd {
+	m.mode = ModeCommand
+	m.statusBar.SetMode("COMMAND")
+	cmd := m.commandBar.Open(ui.CommandEx)
+	if value != "" {
+		m.commandBar.SetValue(value)
+	}
+	return cmd
+}
+
 // handleHistoryMode processes keys when the history panel is active.
 func (m Model) handleHistoryMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
@@ -722,170 +909,25 @@ func (m Model) handleHistoryMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 // handleLeaderMode processes keys when the leader palette is active.
-// Each key maps to a specific action, then returns to normal mode.
+// A complete leader binding runs its action, then returns to normal mode.
 func (m Model) handleLeaderMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	// Always dismiss the palette first.
+	typed := append(slices.Clone(m.pendingKeys), msg.String())
+	action, pending := m.keys.Leader.Match(typed)
+	if pending && msg.String() != "esc" {
+		m.pendingKeys = typed
+		return m, nil
+	}
+	m.pendingKeys = nil
+
+	// Dismiss the palette; unknown keys just close it.
 	m.leaderPanel.Hide()
 	m.mode = ModeNormal
 	m.statusBar.SetMode("NORMAL")
 
-	ts := m.activeTabState()
-
-	switch msg.String() {
-	// ── Navigate ──
-	case "o": // Open URL
-		m.mode = ModeInsert
-		m.urlBar.Reset()
-		m.statusBar.SetMode("INSERT")
-		return m, m.urlBar.Focus()
-
-	case "b": // Back
-		if ts != nil {
-			if url, ok := ts.history.Back(); ok {
-				return m, m.loadPage(url, false)
-			}
-		}
-		return m, nil
-
-	case "f": // Forward
-		if ts != nil {
-			if url, ok := ts.history.Forward(); ok {
-				return m, m.loadPage(url, false)
-			}
-		}
-		return m, nil
-
-	case "l": // Follow link
-		m.mode = ModeFollow
-		m.statusBar.SetMode("FOLLOW")
-		return m, m.commandBar.Open(ui.CommandFollow)
-
-	case "r": // Reload
-		if ts != nil {
-			current := ts.history.Current()
-			if current != "" {
-				return m, m.loadPage(current, false)
-			}
-		}
-		return m, nil
-
-	// ── Tabs ──
-	case "t": // New tab
-		m.tabBar.NewTab()
-		tab := m.tabBar.ActiveTab()
-		m.tabStates[tab.ID] = &tabState{
-			viewport: ui.NewPageViewport(),
-			history:  browser.NewHistory(),
-		}
-		m.layout()
-		m.syncTabUI()
-		return m, nil
-
-	case "w": // Close tab
-		tab := m.tabBar.ActiveTab()
-		if m.tabBar.CloseCurrentTab() {
-			if ts, ok := m.tabStates[tab.ID]; ok {
-				if ts.cancelFunc != nil {
-					ts.cancelFunc()
-				}
-				delete(m.tabStates, tab.ID)
-			}
-			m.syncTabUI()
-		} else {
-			return m, tea.Quit
-		}
-		return m, nil
-
-	case "n": // Next tab
-		m.tabBar.NextTab()
-		m.syncTabUI()
-		return m, nil
-
-	case "p": // Prev tab
-		m.tabBar.PrevTab()
-		m.syncTabUI()
-		return m, nil
-
-	// ── Feeds ──
-	case "h": // Hacker News
-		m.statusBar.SetLoading(true)
-		m.statusBar.SetMessage("Loading Hacker News...")
-		return m, m.fetchHN("top")
-
-	case "e": // Reddit
-		m.mode = ModeCommand
-		m.statusBar.SetMode("COMMAND")
-		cmd := m.commandBar.Open(ui.CommandEx)
-		m.commandBar.SetValue("reddit ")
-		return m, cmd
-
-	case "s": // Search
-		m.mode = ModeCommand
-		m.statusBar.SetMode("COMMAND")
-		cmd := m.commandBar.Open(ui.CommandEx)
-		m.commandBar.SetValue("search ")
-		return m, cmd
-
-	case "a": // RSS feed
-		m.mode = ModeCommand
-		m.statusBar.SetMode("COMMAND")
-		cmd := m.commandBar.Open(ui.CommandEx)
-		m.commandBar.SetValue("rss ")
-		return m, cmd
-
-	// ── Tools ──
-	case "B": // Bookmarks
-		return m.executeCommand("bookmarks")
-
-	case "R": // Read later
-		return m.executeCommand("readlater")
-
-	case "/": // Search page
-		m.mode = ModeSearch
-		m.statusBar.SetMode("SEARCH")
-		return m, m.commandBar.Open(ui.CommandSearch)
-
-	case ":": // Command mode
-		m.mode = ModeCommand
-		m.statusBar.SetMode("COMMAND")
-		return m, m.commandBar.Open(ui.CommandEx)
-
-	// ── Views ──
-	case "H": // History panel
-		if m.historyStore != nil {
-			entries := m.historyStore.List()
-			m.historyPanel.SetEntries(entries)
-		}
-		m.historyPanel.Show()
-		m.mode = ModeHistory
-		m.statusBar.SetMode("HISTORY")
-		m.layout()
-		return m, nil
-
-	case "v": // Split vertical
-		m.splitPane.Split(ui.SplitVertical)
-		m.layout()
-		return m, nil
-
-	case "x": // Close split
-		m.splitPane.Unsplit()
-		m.layout()
-		return m, nil
-
-	case "T": // Theme cycle
-		return m.cycleTheme()
-
-	case "?": // Help
-		m.showHelp()
-		return m, nil
-
-	case "esc", " ":
-		// Already dismissed above.
+	if action == "" || action == ActionLeader {
 		return m, nil
 	}
-
-	// Unknown key — just dismiss.
-	return m, nil
+	return m.runAction(action)
 }
 
 // cycleTheme switches to the next available theme.
@@ -896,22 +938,114 @@ func (m Model) cycleTheme() (tea.Model, tea.Cmd) {
 		if t == current {
 			next := themes[(i+1)%len(themes)]
 			theme.Set(next)
+			cmd := m.rerenderPages()
 			m.statusBar.SetMessage(fmt.Sprintf("Theme: %s", next))
-			return m, nil
+			return m, cmd
 		}
 	}
 	// Fallback: set first theme.
 	if len(themes) > 0 {
 		theme.Set(themes[0])
+		cmd := m.rerenderPages()
 		m.statusBar.SetMessage(fmt.Sprintf("Theme: %s", themes[0]))
+		return m, cmd
 	}
 	return m, nil
 }
 
+// rerenderPages redraws every tab so its content picks up the current theme.
+// Pages are re-rendered from their stored articles and comment threads, and
+// feed and list pages from the items behind them. Content that can't be
+// rebuilt, like a plugin's page, is left as it is. Scroll positions are
+// kept.
+func (m *Model) rerenderPages() tea.Cmd {
+	if m.pageCache != nil {
+		m.pageCache.Purge()
+	}
+
+	for _, ts := range m.tabStates {
+		if ts.article != nil || ts.thread != nil || ts.loading {
+			continue
+		}
+		content, links, ok := m.rebuildPage(ts)
+		if !ok {
+			continue
+		}
+		offset := ts.viewport.YOffset()
+		ts.feedLinks = links
+		ts.viewport.SetContent(content)
+		ts.viewport.SetYOffset(offset)
+	}
+	return m.rerenderTabs(true)
+}
+
+// rebuildPage renders a tab's feed or list page again. Returns false when
+// the tab shows anything else.
+func (m *Model) rebuildPage(ts *tabState) (string, []browser.Link, bool) {
+	width := m.renderWidth(ts)
+	switch {
+	case ts.feedData != nil:
+		return renderFeed(ts.feedData, width)
+	case ts.listPage != nil:
+		content, links := ts.listPage(width)
+		return content, links, true
+	}
+	return "", nil, false
+}
+
+// renderFeed renders a feed page from the items it was fetched with.
+// Returns false for pages whose items don't say how they were drawn, like a
+// plugin's.
+func renderFeed(data *feeds.Data, width int) (string, []browser.Link, bool) {
+	var content string
+	var links []browser.Link
+	switch items := data.Items.(type) {
+	case []feeds.HNStory:
+		content, links = feeds.RenderHNStories(items, data.Title)
+	case []feeds.RedditPost:
+		content, links = feeds.RenderRedditPosts(items, data.Title)
+	case *feeds.RedditPostDetail:
+		content, links = feeds.RenderPostDetail(items)
+	case *feeds.Feed:
+		content, links = feeds.RenderFeed(items)
+	case []feeds.SearchResult:
+		content, links = feeds.RenderSearchResults(items, strings.TrimPrefix(data.Title, searchTitle))
+	case feeds.GitHubRepoPage:
+		content, links = feeds.RenderRepo(items.Repo, items.Readme, width)
+	case feeds.GitHubUserPage:
+		content, links = feeds.RenderUser(items.User, items.Repos, width)
+	case *feeds.GitHubGist:
+		content, links = feeds.RenderGist(items, width)
+	case *feeds.GitHubIssue:
+		info := feeds.ParseGitHubURL(data.URL)
+		if info == nil {
+			return "", nil, false
+		}
+		content, links = feeds.RenderIssue(items, info.Owner, info.Repo, width)
+	case *feeds.GitHubPR:
+		info := feeds.ParseGitHubURL(data.URL)
+		if info == nil {
+			return "", nil, false
+		}
+		content, links = feeds.RenderPR(items, info.Owner, info.Repo, width)
+	default:
+		return "", nil, false
+	}
+	return content, links, true
+}
+
 // handleInsertMode processes keys when the URL bar is focused.
 func (m Model) handleInsertMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.editingField != nil {
+		return m.handleFieldEdit(msg)
+	}
+
 	switch msg.Type {
 	case tea.KeyEsc:
+		// The first Esc only closes the suggestions.
+		if m.urlBar.CloseSuggestions() {
+			return m, nil
+		}
 		m.mode = ModeNormal
 		m.urlBar.Blur()
 		m.statusBar.SetMode("NORMAL")
@@ -928,42 +1062,107 @@ func (m Model) handleInsertMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	typed := m.urlBar.Typed()
 	ub, cmd := m.urlBar.Update(msg)
 	m.urlBar = *ub
+	if q := m.urlBar.Typed(); q != typed && m.historyStore != nil {
+		return m, tea.Batch(cmd, m.suggest(q))
+	}
 	return m, cmd
 }
 
+// suggest looks up the URL bar suggestions for q in the background, so
+// typing does not wait on the database.
+func (m *Model) suggest(q string) tea.Cmd {
+	historyStore := m.historyStore
+	return func() tea.Msg {
+		return suggestionsMsg{query: q, suggestions: historyStore.Suggest(q, ui.MaxSuggestions)}
+	}
+}
+
 // handleCommandMode processes keys in command/search/follow mode.
 func (m Model) handleCommandMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
 	case tea.KeyEsc:
+		// The first Esc only dismisses the completion or history popup.
+		if m.commandBar.CloseMenu() {
+			return m, nil
+		}
+		if ts := m.activeTabState(); ts != nil {
+			switch m.mode {
+			case ModeSearch:
+				ts.viewport.CancelSearch()
+			case ModeFollow:
+				ts.viewport.ClearHints()
+				m.followTarget = followHere
+			}
+		}
 		m.commandBar.Close()
-		m.mode = ModeNormal
-		m.statusBar.SetMode("NORMAL")
+		m.leaveCommandMode()
+		m.syncStatusBar()
 		return m, nil
 
 	case tea.KeyEnter:
 		result := m.commandBar.Submit()
-		m.mode = ModeNormal
-		m.statusBar.SetMode("NORMAL")
+		m.leaveCommandMode()
 		return m.handleCommandResult(result)
+
+	case tea.KeyTab:
+		m.commandBar.Complete(1, m.completeCommand)
+		return m, nil
+
+	case tea.KeyShiftTab:
+		m.commandBar.Complete(-1, m.completeCommand)
+		return m, nil
 	}
 
 	cb, cmd := m.commandBar.Update(msg)
 	m.commandBar = *cb
+
+	// Incremental search: highlight matches as the pattern is typed.
+	if m.mode == ModeSearch {
+		if ts := m.activeTabState(); ts != nil {
+			ts.viewport.Search(m.commandBar.Value())
+			m.syncStatusBar()
+		}
+	}
+
+	// Link hints: follow as soon as a full label is typed.
+	if m.mode == ModeFollow {
+		return m, tea.Batch(cmd, m.updateHints())
+	}
+
 	return m, cmd
 }
 
+// leaveCommandMode returns from the command bar to the bookmark manager if
+// it is open (after r or t), else to normal mode.
+func (m *Model) leaveCommandMode() {
+	if m.bookmarkPanel.IsVisible() {
+		m.mode = ModeBookmarks
+		m.statusBar.SetMode("BOOKMARKS")
+		return
+	}
+	m.mode = ModeNormal
+	m.statusBar.SetMode("NORMAL")
+}
+
 // handleCommandResult processes a submitted command.
 func (m Model) handleCommandResult(result ui.CommandResult) (tea.Model, tea.Cmd) {
 	switch result.Type {
 	case ui.CommandEx:
+		if m.cmdLines != nil && strings.TrimSpace(result.Value) != "" {
+			m.cmdLines.Add(result.Value)
+		}
 		return m.executeCommand(result.Value)
 	case ui.CommandSearch:
-		m.statusBar.SetMessage(fmt.Sprintf("Search: %s (not yet implemented)", result.Value))
+		m.runSearch(result.Value)
 		return m, nil
 	case ui.CommandFollow:
 		return m.followLink(result.Value)
+	case ui.CommandField:
+		cmd := m.activateField(result.Value)
+		return m, cmd
 	}
 	return m, nil
 }
@@ -977,7 +1176,7 @@ func (m Model) executeCommand(cmd string) (tea.Model, tea.Cmd) {
 
 	switch parts[0] {
 	case "q", "quit":
-		return m, tea.Quit
+		return m, m.quit()
 	case "o", "open":
 		if len(parts) > 1 {
 			url := strings.Join(parts[1:], " ")
@@ -986,21 +1185,20 @@ func (m Model) executeCommand(cmd string) (tea.Model, tea.Cmd) {
 		m.statusBar.SetMessage("Usage: :open <url>")
 	case "theme":
 		if len(parts) > 1 {
-			if theme.Set(parts[1]) {
-				m.statusBar.SetMessage(fmt.Sprintf("Theme: %s", parts[1]))
-			} else {
+			if !theme.Set(parts[1]) {
 				m.statusBar.SetMessage(fmt.Sprintf("Unknown theme: %s (available: %s)", parts[1], strings.Join(theme.List(), ", ")))
+				return m, nil
 			}
+			cmd := m.rerenderPages()
+			m.statusBar.SetMessage(fmt.Sprintf("Theme: %s", parts[1]))
+			return m, cmd
 		} else {
 			m.statusBar.SetMessage(fmt.Sprintf("Current: %s | Available: %s", theme.Current.Name, strings.Join(theme.List(), ", ")))
 		}
 	case "tab", "tabnew":
 		m.tabBar.NewTab()
 		tab := m.tabBar.ActiveTab()
-		m.tabStates[tab.ID] = &tabState{
-			viewport: ui.NewPageViewport(),
-			history:  browser.NewHistory(),
-		}
+		m.tabStates[tab.ID] = newTabState()
 		m.layout()
 		m.syncTabUI()
 		if len(parts) > 1 {
@@ -1008,20 +1206,29 @@ func (m Model) executeCommand(cmd string) (tea.Model, tea.Cmd) {
 			return m, m.navigateTo(url)
 		}
 	case "tabclose", "tc":
-		tab := m.tabBar.ActiveTab()
-		if m.tabBar.CloseCurrentTab() {
-			delete(m.tabStates, tab.ID)
-			m.syncTabUI()
+		m.closeActiveTab()
+	case "tabopen", "to", "bgopen", "bg":
+		name := "tabopen"
+		if parts[0] == "bgopen" || parts[0] == "bg" {
+			name = "bgopen"
 		}
+		cmd := m.tabOpenCommand(name, parts[1:])
+		return m, cmd
+	case "undo":
+		cmd := m.reopenClosedTab()
+		return m, cmd
 	case "split", "vs", "vsplit":
-		m.splitPane.Split(ui.SplitVertical)
-		m.layout()
+		m.splitWindow(ui.SplitVertical)
 	case "sp", "hsplit":
-		m.splitPane.Split(ui.SplitHorizontal)
-		m.layout()
-	case "unsplit":
-		m.splitPane.Unsplit()
-		m.layout()
+		m.splitWindow(ui.SplitHorizontal)
+	case "unsplit", "only":
+		m.unsplitWindow()
+	case "resize":
+		if len(parts) > 1 {
+			m.resizeSplit(parts[1])
+		} else {
+			m.statusBar.SetMessage("Usage: :resize <0.3|30%|+10|-10>")
+		}
 	case "help":
 		m.showHelp()
 	case "hn":
@@ -1057,28 +1264,27 @@ func (m Model) executeCommand(cmd string) (tea.Model, tea.Cmd) {
 		}
 		m.statusBar.SetMessage("Usage: :search <query>")
 	case "bookmarks", "bm":
-		if m.bookmarks != nil {
-			content, links := storage.RenderBookmarks(m.bookmarks.List())
-			ts := m.activeTabState()
-			if ts != nil {
-				ts.page = nil
-				ts.feedLinks = links
-				ts.viewport.SetContent(content)
-				m.tabBar.SetActiveTitle("Bookmarks")
-				m.statusBar.SetTitle("Bookmarks")
-				m.statusBar.SetLinkCount(len(links))
-			}
-		} else {
-			m.statusBar.SetMessage("Bookmarks not available")
-		}
+		m.showBookmarks(parts[1:])
+	case "bmanager", "bmm":
+		m.openBookmarkPanel(parts[1:])
+	case "bmrename":
+		m.renameBookmark(parts[1:])
+	case "bmtag":
+		m.retagBookmark(parts[1:])
+	case "bmimport":
+		m.importBookmarks(parts[1:])
+	case "bmexport":
+		m.exportBookmarks(parts[1:])
+	case "export":
+		m.exportPage(parts[1:])
 	case "readlater", "rl":
 		if m.readLater != nil {
-			content, links := storage.RenderReadLater(m.readLater.ListAll())
+			items := m.readLater.ListAll()
 			ts := m.activeTabState()
 			if ts != nil {
-				ts.page = nil
-				ts.feedLinks = links
-				ts.viewport.SetContent(content)
+				links := ts.showList(m.renderWidth(ts), func(int) (string, []browser.Link) {
+					return storage.RenderReadLater(items)
+				})
 				m.tabBar.SetActiveTitle("Read Later")
 				m.statusBar.SetTitle("Read Later")
 				m.statusBar.SetLinkCount(len(links))
@@ -1087,22 +1293,12 @@ func (m Model) executeCommand(cmd string) (tea.Model, tea.Cmd) {
 			m.statusBar.SetMessage("Read later not available")
 		}
 	case "bookmark":
-		if m.bookmarks != nil {
-			tab := m.tabBar.ActiveTab()
-			if tab != nil && tab.URL != "" {
-				if m.bookmarks.Add(tab.URL, tab.Title) {
-					m.statusBar.SetMessage(fmt.Sprintf("Bookmarked: %s", tab.Title))
-				} else {
-					m.statusBar.SetMessage("Already bookmarked")
-				}
-			} else {
-				m.statusBar.SetMessage("No page to bookmark")
-			}
-		}
+		m.bookmarkCommand(parts[1:])
 	case "history":
 		if m.historyStore != nil {
 			entries := m.historyStore.List()
 			m.historyPanel.SetEntries(entries)
+			m.bookmarkPanel.Hide()
 			m.historyPanel.Show()
 			m.mode = ModeHistory
 			m.statusBar.SetMode("HISTORY")
@@ -1110,11 +1306,43 @@ func (m Model) executeCommand(cmd string) (tea.Model, tea.Cmd) {
 		} else {
 			m.statusBar.SetMessage("History not available")
 		}
+	case "grep":
+		m.grepCommand(parts[1:])
 	case "clearhistory":
 		if m.historyStore != nil {
 			m.historyStore.Clear()
 			m.statusBar.SetMessage("History cleared")
 		}
+	case "clearcache":
+		if cache := browser.SharedTransport.Cache(); cache != nil {
+			if err := cache.Clear(); err != nil {
+				m.statusBar.SetMessage(fmt.Sprintf("Error: %s", err))
+			} else {
+				m.pageCache.Purge()
+				m.statusBar.SetMessage("Cache cleared")
+			}
+		}
+	case "cookies":
+		m.cookiesCommand(parts[1:])
+	case "plugins":
+		m.showPlugins()
+	case "offline":
+		arg := ""
+		if len(parts) > 1 {
+			arg = parts[1]
+		}
+		m.toggleOffline(arg)
+	case "session":
+		cmd := m.sessionCommand(parts[1:])
+		return m, cmd
+	case "submit":
+		cmd := m.submitCommand(parts[1:])
+		return m, cmd
+	case "noh", "nohlsearch":
+		if ts := m.activeTabState(); ts != nil {
+			ts.viewport.ClearSearch()
+			m.syncStatusBar()
+		}
 	default:
 		m.statusBar.SetMessage(fmt.Sprintf("Unknown command: %s", parts[0]))
 	}
@@ -1122,40 +1350,101 @@ func (m Model) executeCommand(cmd string) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// followLink navigates to a link by its index number.
+// followLink navigates to a link by its index number, or by a hint label
+// (or unambiguous label prefix) when letters were typed.
 func (m Model) followLink(input string) (tea.Model, tea.Cmd) {
 	ts := m.activeTabState()
 	if ts == nil {
 		m.statusBar.SetMessage("No page loaded")
 		return m, nil
 	}
+	input = strings.TrimSpace(input)
 
-	num, err := strconv.Atoi(strings.TrimSpace(input))
+	num, err := strconv.Atoi(input)
 	if err != nil {
-		m.statusBar.SetMessage(fmt.Sprintf("Invalid link number: %s", input))
-		return m, nil
+		hints := ts.viewport.FilterHints(strings.ToLower(input))
+		if input == "" || len(hints) != 1 {
+			ts.viewport.ClearHints()
+			m.followTarget = followHere
+			m.statusBar.SetMessage(fmt.Sprintf("Invalid link number: %s", input))
+			return m, nil
+		}
+		num = hints[0].Link
 	}
 
-	// Check page links first (normal web pages).
-	if ts.page != nil {
-		for _, link := range ts.page.Links {
-			if link.Index == num {
-				return m, m.navigateTo(link.URL)
-			}
-		}
+	cmd := m.followNumber(num)
+	return m, cmd
+}
+
+// openSearch opens the / prompt for searching the active page.
+func (m *Model) openSearch() tea.Cmd {
+	if ts := m.activeTabState(); ts != nil {
+		ts.viewport.StartSearch()
 	}
+	m.mode = ModeSearch
+	m.statusBar.SetMode("SEARCH")
+	return m.commandBar.Open(ui.CommandSearch)
+}
 
-	// Check feed links (HN, Reddit, RSS, Search, Bookmarks, Read Later).
-	if len(ts.feedLinks) > 0 {
-		for _, link := range ts.feedLinks {
-			if link.Index == num {
-				return m, m.navigateTo(link.URL)
-			}
+// runSearch applies a submitted / pattern. An empty pattern repeats the last search.
+func (m *Model) runSearch(pattern string) {
+	ts := m.activeTabState()
+	if ts == nil {
+		return
+	}
+
+	if pattern == "" {
+		pattern = ts.viewport.SearchTerm()
+		if pattern == "" {
+			m.syncStatusBar()
+			return
 		}
 	}
 
-	m.statusBar.SetMessage(fmt.Sprintf("Link [%d] not found", num))
-	return m, nil
+	if err := ts.viewport.Search(pattern); err != nil {
+		m.statusBar.SetMessage(fmt.Sprintf("Invalid pattern: %s", err))
+		m.syncStatusBar()
+		return
+	}
+
+	if _, total := ts.viewport.MatchInfo(); total == 0 {
+		m.statusBar.SetMessage(fmt.Sprintf("Pattern not found: %s", pattern))
+	} else {
+		m.statusBar.SetMessage("")
+	}
+	m.syncStatusBar()
+}
+
+// stepSearch jumps to the next (dir > 0) or previous (dir < 0) search match.
+func (m *Model) stepSearch(dir int) {
+	ts := m.activeTabState()
+	if ts == nil {
+		return
+	}
+
+	if ts.viewport.SearchTerm() == "" {
+		m.statusBar.SetMessage("No previous search pattern")
+		return
+	}
+
+	var ok, wrapped bool
+	if dir > 0 {
+		ok, wrapped = ts.viewport.NextMatch()
+	} else {
+		ok, wrapped = ts.viewport.PrevMatch()
+	}
+
+	switch {
+	case !ok:
+		m.statusBar.SetMessage(fmt.Sprintf("Pattern not found: %s", ts.viewport.SearchTerm()))
+	case wrapped && dir > 0:
+		m.statusBar.SetMessage("Search hit BOTTOM, continuing at TOP")
+	case wrapped:
+		m.statusBar.SetMessage("Search hit TOP, continuing at BOTTOM")
+	default:
+		m.statusBar.SetMessage("")
+	}
+	m.syncStatusBar()
 }
 
 // navigateTo loads a URL in the active tab and pushes to history.
@@ -1165,13 +1454,21 @@ func (m Model) navigateTo(url string) tea.Cmd {
 
 // loadPage fetches and renders a page. If pushHistory is true, adds to history.
 func (m Model) loadPage(url string, pushHistory bool) tea.Cmd {
-	ts := m.activeTabState()
-	if ts == nil {
+	tab := m.tabBar.ActiveTab()
+	if tab == nil {
 		return nil
 	}
+	return m.loadPageInTab(tab.ID, url, pushHistory)
+}
 
-	tab := m.tabBar.ActiveTab()
-	tabID := tab.ID
+// loadPageInTab fetches and renders a page in the given tab, which need not
+// be the active one.
+func (m Model) loadPageInTab(tabID int, url string, pushHistory bool) tea.Cmd {
+	ts, ok := m.tabStates[tabID]
+	if !ok {
+		return nil
+	}
+	active := m.isActiveTab(tabID)
 
 	// Cancel previous load if any.
 	if ts.cancelFunc != nil {
@@ -1180,92 +1477,108 @@ func (m Model) loadPage(url string, pushHistory bool) tea.Cmd {
 
 	// Check page cache first (for instant back/forward navigation).
 	if m.pageCache != nil {
-		if cachedPage, ok := m.pageCache.Get(url); ok {
+		width := m.renderWidth(ts)
+		if cached, ok := m.pageCache.Get(pageKey{url: url, width: width}); ok {
 			// Return cached page immediately.
 			ts.loading = false
-			m.statusBar.SetLoading(false)
-			m.urlBar.SetValue(url)
-			m.tabBar.SetActiveURL(url)
+			if active {
+				m.statusBar.SetLoading(false)
+				m.urlBar.SetValue(url)
+			}
+			m.tabBar.SetURL(tabID, url)
 			if pushHistory {
 				ts.history.Push(url)
 			}
 			return func() tea.Msg {
-				return pageLoadedMsg{tabID: tabID, page: cachedPage, url: url}
+				return pageLoadedMsg{tabID: tabID, page: cached.page, article: cached.article, width: width, url: url}
 			}
 		}
 	}
 
 	ts.loading = true
-	m.statusBar.SetLoading(true)
-	m.statusBar.SetMessage("")
-	m.urlBar.SetValue(url)
-	m.tabBar.SetActiveTitle("Loading...")
-	m.tabBar.SetActiveURL(url)
+	if active {
+		m.statusBar.SetLoading(true)
+		m.statusBar.SetMessage("")
+		m.urlBar.SetValue(url)
+	}
+	m.tabBar.SetTitle(tabID, "Loading...")
+	m.tabBar.SetURL(tabID, url)
 
 	if pushHistory {
 		ts.history.Push(url)
 	}
 
-	// Intercept Reddit URLs and use .json API instead of HTML fetching.
-	redditInfo := feeds.ParseRedditURL(url)
-	if redditInfo != nil && redditInfo.Type != feeds.RedditURLNone {
-		client := m.redditClient
-		return func() tea.Msg {
-			content, title, links, err := client.FetchURL(redditInfo)
-			if err != nil {
-				return feedLoadedMsg{tabID: tabID, err: err}
+	// Unread read-later pages are shown from their snapshot, network or not.
+	if m.readLater != nil {
+		if article := m.readLater.Snapshot(url); article != nil {
+			width := m.renderWidth(ts)
+			return func() tea.Msg {
+				page := browser.Render(article, width)
+				return pageLoadedMsg{tabID: tabID, page: page, article: article, width: width, url: url}
 			}
-			return feedLoadedMsg{tabID: tabID, content: content, title: title, links: links}
 		}
 	}
 
-	// Intercept GitHub URLs and use GitHub API for rich rendering.
-	githubInfo := feeds.ParseGitHubURL(url)
-	if githubInfo != nil && githubInfo.Type != feeds.GitHubURLNone {
-		client := m.githubClient
-		width := m.width
-		if width <= 0 {
-			width = 80
-		}
+	// Sites with a handler are rendered from their API instead of the HTML.
+	if site := m.sites.Match(url); site != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		ts.cancelFunc = cancel
+		width := m.renderWidth(ts)
 		return func() tea.Msg {
-			content, title, links, err := client.FetchURL(githubInfo, width)
+			ctx, age := browser.WithOfflineAge(ctx)
+			page, err := site.Fetch(ctx, url, width)
 			if err != nil {
 				return feedLoadedMsg{tabID: tabID, err: err}
 			}
-			return feedLoadedMsg{tabID: tabID, content: content, title: title, links: links}
+			return feedLoadedMsg{tabID: tabID, content: page.Content, title: page.Title, links: page.Links, data: page.Data, cachedAt: age.Oldest()}
 		}
 	}
 
+	return m.fetchPage(tabID, ts, browser.Request{Method: http.MethodGet, URL: url}, false)
+}
+
+// fetchPage performs req in the background and renders the response into the
+// tab. If pushHistory is true, the final URL is added to the tab's history
+// once loaded.
+func (m Model) fetchPage(tabID int, ts *tabState, req browser.Request, pushHistory bool) tea.Cmd {
 	ctx, cancel := context.WithCancel(context.Background())
 	ts.cancelFunc = cancel
 
 	fetcher := m.fetcher
+	// Only GET responses can be replayed from the cache by URL.
 	pageCache := m.pageCache
-	// Capture width for the goroutine (use actual terminal width, constrained for readability).
-	renderWidth := m.width
-	if renderWidth <= 0 {
-		renderWidth = 80
+	if req.Method != http.MethodGet {
+		pageCache = nil
 	}
+	// Capture width for the goroutine: the tab's pane width.
+	renderWidth := m.renderWidth(ts)
 
 	return func() tea.Msg {
-		result, err := fetcher.FetchWithContext(ctx, url)
+		result, err := fetcher.Do(ctx, req)
 		if err != nil {
-			return pageLoadedMsg{tabID: tabID, err: err, url: url}
+			return pageLoadedMsg{tabID: tabID, err: err, url: req.URL}
 		}
 
 		article, err := browser.Extract(result)
 		if err != nil {
-			return pageLoadedMsg{tabID: tabID, err: err, url: url}
+			return pageLoadedMsg{tabID: tabID, err: err, url: req.URL}
 		}
 
 		page := browser.Render(article, renderWidth)
 
 		// Store in cache for future back/forward navigation.
 		if pageCache != nil {
-			pageCache.Add(result.FinalURL, page)
+			pageCache.Add(pageKey{url: result.FinalURL, width: renderWidth}, &cachedPage{article: article, page: page})
 		}
 
-		return pageLoadedMsg{tabID: tabID, page: page, url: result.FinalURL}
+		return pageLoadedMsg{
+			tabID:       tabID,
+			page:        page,
+			article:     article,
+			width:       renderWidth,
+			url:         result.FinalURL,
+			pushHistory: pushHistory,
+		}
 	}
 }
 
@@ -1280,8 +1593,10 @@ func (m Model) handlePageLoaded(msg pageLoadedMsg) (tea.Model, tea.Cmd) {
 	ts.cancelFunc = nil
 
 	if msg.err != nil {
-		m.statusBar.SetLoading(false)
-		m.statusBar.SetMessage(fmt.Sprintf("Error: %s", msg.err))
+		if m.isActiveTab(msg.tabID) {
+			m.statusBar.SetLoading(false)
+			m.statusBar.SetMessage(fmt.Sprintf("Error: %s", msg.err))
+		}
 
 		errStyle := lipgloss.NewStyle().
 			Foreground(theme.Current.Error).
@@ -1293,27 +1608,46 @@ func (m Model) handlePageLoaded(msg pageLoadedMsg) (tea.Model, tea.Cmd) {
 
 		errContent := errStyle.Render("Failed to load page") + "\n\n" +
 			detailStyle.Render(fmt.Sprintf("URL: %s\nError: %s", msg.url, msg.err))
+		if offlineContent, ok := offlineErrorContent(msg.url, msg.err); ok {
+			errContent = offlineContent
+		}
 
+		ts.clearPage()
+		ts.cachedAt = time.Time{}
 		ts.viewport.SetContent(errContent)
-		m.tabBar.SetActiveTitle("Error")
+		m.tabBar.SetTitle(msg.tabID, "Error")
 		return m, nil
 	}
 
-	ts.page = msg.page
-	ts.viewport.SetContent(msg.page.Content)
-
-	m.tabBar.SetActiveTitle(msg.page.Title)
-	m.tabBar.SetActiveURL(msg.url)
-	m.urlBar.SetValue(msg.url)
-	m.statusBar.SetLoading(false)
-	m.statusBar.SetTitle(msg.page.Title)
-	m.statusBar.SetURL(msg.url)
-	m.statusBar.SetLinkCount(len(msg.page.Links))
-	m.syncStatusBar()
+	if msg.pushHistory {
+		ts.history.Push(msg.url)
+	}
+	// The page may also sit in the page cache; the tab fills in its own copy.
+	ts.page = msg.page.Clone()
+	ts.article = msg.article
+	ts.pageWidth = msg.width
+	ts.cachedAt = m.cachedAt(msg.url)
+	ts.viewport.SetContent(ts.page.Content)
+	ts.restoreScroll()
+	m.startReading(ts, msg.url)
+
+	m.tabBar.SetTitle(msg.tabID, msg.page.Title)
+	m.tabBar.SetURL(msg.tabID, msg.url)
+	if m.isActiveTab(msg.tabID) {
+		m.urlBar.SetValue(msg.url)
+		m.statusBar.SetLoading(false)
+		m.statusBar.SetTitle(msg.page.Title)
+		m.statusBar.SetURL(msg.url)
+		m.statusBar.SetLinkCount(len(msg.page.Links))
+		m.syncStatusBar()
+	}
 
-	// Record in global history.
+	// Record in global history, and index the text for :grep.
 	if m.historyStore != nil {
 		m.historyStore.Add(msg.url, msg.page.Title)
+		if msg.article != nil {
+			m.historyStore.IndexPage(msg.url, msg.page.Title, msg.article.TextContent)
+		}
 	}
 
 	return m, nil
@@ -1346,6 +1680,7 @@ func (m *Model) activeTabState() *tabState {
 
 // syncTabUI updates the URL bar, status bar, and link count to reflect the active tab.
 func (m *Model) syncTabUI() {
+	m.syncPanes()
 	tab := m.tabBar.ActiveTab()
 	if tab != nil {
 		m.urlBar.SetValue(tab.URL)
@@ -1360,6 +1695,13 @@ func (m *Model) syncStatusBar() {
 		return
 	}
 	m.statusBar.SetScrollInfo(ts.viewport.ScrollInfo())
+	m.statusBar.SetOffline(m.offlineLabel(ts))
+
+	if current, total := ts.viewport.MatchInfo(); total > 0 {
+		m.statusBar.SetSearchInfo(fmt.Sprintf("match %d/%d", current, total))
+	} else {
+		m.statusBar.SetSearchInfo("")
+	}
 
 	tab := m.tabBar.ActiveTab()
 	if tab != nil {
@@ -1382,10 +1724,15 @@ func (m Model) handleFeedLoaded(msg feedLoadedMsg) (tea.Model, tea.Cmd) {
 	}
 
 	ts.loading = false
-	m.statusBar.SetLoading(false)
+	active := m.isActiveTab(msg.tabID)
+	if active {
+		m.statusBar.SetLoading(false)
+	}
 
 	if msg.err != nil {
-		m.statusBar.SetMessage(fmt.Sprintf("Error: %s", msg.err))
+		if active {
+			m.statusBar.SetMessage(fmt.Sprintf("Error: %s", msg.err))
+		}
 
 		errStyle := lipgloss.NewStyle().
 			Foreground(theme.Current.Error).
@@ -1397,24 +1744,41 @@ func (m Model) handleFeedLoaded(msg feedLoadedMsg) (tea.Model, tea.Cmd) {
 
 		errContent := errStyle.Render("Failed to load feed") + "\n\n" +
 			detailStyle.Render(fmt.Sprintf("Error: %s", msg.err))
+		if offlineContent, ok := offlineErrorContent("", msg.err); ok {
+			errContent = offlineContent
+		} else if pluginContent, ok := pluginErrorContent(msg.err); ok {
+			errContent = pluginContent
+		}
 
+		ts.clearPage()
+		ts.cachedAt = time.Time{}
 		ts.viewport.SetContent(errContent)
-		m.tabBar.SetActiveTitle("Error")
+		m.tabBar.SetTitle(msg.tabID, "Error")
 		return m, nil
 	}
 
-	ts.page = nil // clear page state since this is feed content
+	ts.clearPage() // clear page state since this is feed content
 	ts.feedLinks = msg.links
+	ts.feedData = msg.data
+	ts.cachedAt = msg.cachedAt
 	ts.viewport.SetContent(msg.content)
-	m.tabBar.SetActiveTitle(msg.title)
-	m.statusBar.SetTitle(msg.title)
-	m.statusBar.SetMessage("")
-	m.statusBar.SetLinkCount(len(msg.links))
-	m.syncStatusBar()
+	// Comment threads are re-rendered here to track where each comment is.
+	if thread, ok := feedThread(msg.data); ok {
+		ts.thread = &threadView{thread: thread, folded: make(map[int]bool), loading: make(map[int]bool)}
+		m.showThread(ts, m.renderWidth(ts), 0)
+	}
+	ts.restoreScroll()
+	m.tabBar.SetTitle(msg.tabID, msg.title)
+	if active {
+		m.statusBar.SetTitle(msg.title)
+		m.statusBar.SetMessage("")
+		m.statusBar.SetLinkCount(len(msg.links))
+		m.syncStatusBar()
+	}
 
 	// Record feed page in global history.
 	if m.historyStore != nil {
-		tab := m.tabBar.ActiveTab()
+		tab := m.tabBar.Tab(msg.tabID)
 		if tab != nil && tab.URL != "" {
 			m.historyStore.Add(tab.URL, msg.title)
 		}
@@ -1433,26 +1797,27 @@ func (m Model) fetchHN(category string) tea.Cmd {
 	client := m.hnClient
 
 	return func() tea.Msg {
+		ctx, age := browser.WithOfflineAge(context.Background())
 		var stories []feeds.HNStory
 		var err error
-		var title string
+		var title, page string
 
 		switch category {
 		case "new":
-			title = "Hacker News - New Stories"
-			stories, err = client.NewStories(30)
+			title, page = "Hacker News - New Stories", "newest"
+			stories, err = client.NewStories(ctx, 30)
 		case "best":
-			title = "Hacker News - Best Stories"
-			stories, err = client.BestStories(30)
+			title, page = "Hacker News - Best Stories", "best"
+			stories, err = client.BestStories(ctx, 30)
 		case "ask":
-			title = "Hacker News - Ask HN"
-			stories, err = client.AskStories(30)
+			title, page = "Hacker News - Ask HN", "ask"
+			stories, err = client.AskStories(ctx, 30)
 		case "show":
-			title = "Hacker News - Show HN"
-			stories, err = client.ShowStories(30)
+			title, page = "Hacker News - Show HN", "show"
+			stories, err = client.ShowStories(ctx, 30)
 		default:
-			title = "Hacker News - Top Stories"
-			stories, err = client.TopStories(30)
+			title, page = "Hacker News - Top Stories", "news"
+			stories, err = client.TopStories(ctx, 30)
 		}
 
 		if err != nil {
@@ -1460,7 +1825,8 @@ func (m Model) fetchHN(category string) tea.Cmd {
 		}
 
 		content, links := feeds.RenderHNStories(stories, title)
-		return feedLoadedMsg{tabID: tabID, content: content, title: title, links: links}
+		data := &feeds.Data{Kind: "hn", Title: title, URL: "https://news.ycombinator.com/" + page, Items: stories}
+		return feedLoadedMsg{tabID: tabID, content: content, title: title, links: links, data: data, cachedAt: age.Oldest()}
 	}
 }
 
@@ -1474,14 +1840,16 @@ func (m Model) fetchReddit(subreddit string) tea.Cmd {
 	client := m.redditClient
 
 	return func() tea.Msg {
-		posts, err := client.FetchSubreddit(subreddit, "hot", 25)
+		ctx, age := browser.WithOfflineAge(context.Background())
+		posts, err := client.FetchSubreddit(ctx, subreddit, "hot", 25)
 		if err != nil {
 			return feedLoadedMsg{tabID: tabID, err: err}
 		}
 
 		title := fmt.Sprintf("r/%s - Hot", subreddit)
 		content, links := feeds.RenderRedditPosts(posts, title)
-		return feedLoadedMsg{tabID: tabID, content: content, title: title, links: links}
+		data := &feeds.Data{Kind: "reddit", Title: title, URL: "https://www.reddit.com/r/" + subreddit, Items: posts}
+		return feedLoadedMsg{tabID: tabID, content: content, title: title, links: links, data: data, cachedAt: age.Oldest()}
 	}
 }
 
@@ -1495,16 +1863,21 @@ func (m Model) fetchRSS(feedURL string) tea.Cmd {
 	client := m.rssClient
 
 	return func() tea.Msg {
-		feed, err := client.Fetch(feedURL)
+		ctx, age := browser.WithOfflineAge(context.Background())
+		feed, err := client.Fetch(ctx, feedURL)
 		if err != nil {
 			return feedLoadedMsg{tabID: tabID, err: err}
 		}
 
 		content, links := feeds.RenderFeed(feed)
-		return feedLoadedMsg{tabID: tabID, content: content, title: feed.Title, links: links}
+		data := &feeds.Data{Kind: "rss", Title: feed.Title, URL: feedURL, Items: feed}
+		return feedLoadedMsg{tabID: tabID, content: content, title: feed.Title, links: links, data: data, cachedAt: age.Oldest()}
 	}
 }
 
+// searchTitle starts the title of a search results page.
+const searchTitle = "Search: "
+
 // fetchSearch creates a tea.Cmd that searches DuckDuckGo asynchronously.
 func (m Model) fetchSearch(query string) tea.Cmd {
 	tab := m.tabBar.ActiveTab()
@@ -1514,14 +1887,16 @@ func (m Model) fetchSearch(query string) tea.Cmd {
 	tabID := tab.ID
 
 	return func() tea.Msg {
-		results, err := feeds.SearchDDG(query)
+		ctx, age := browser.WithOfflineAge(context.Background())
+		results, err := feeds.SearchDDG(ctx, query)
 		if err != nil {
 			return feedLoadedMsg{tabID: tabID, err: err}
 		}
 
 		content, links := feeds.RenderSearchResults(results, query)
-		title := fmt.Sprintf("Search: %s", query)
-		return feedLoadedMsg{tabID: tabID, content: content, title: title, links: links}
+		title := searchTitle + query
+		data := &feeds.Data{Kind: "search", Title: title, Items: results}
+		return feedLoadedMsg{tabID: tabID, content: content, title: title, links: links, data: data, cachedAt: age.Oldest()}
 	}
 }
 
@@ -1556,87 +1931,54 @@ func (m *Model) showHelp() {
 	sb.WriteString(titleStyle.Render("tsurf Keybindings"))
 	sb.WriteString("\n\n")
 
-	sections := []struct {
-		name string
-		keys []struct{ k, d string }
-	}{
-		{"Navigation", []struct{ k, d string }{
-			{"j / Down", "Scroll down"},
-			{"k / Up", "Scroll up"},
-			{"Ctrl+d", "Half page down"},
-			{"Ctrl+u", "Half page up"},
-			{"gg", "Go to top"},
-			{"G", "Go to bottom"},
-		}},
-		{"Browsing", []struct{ k, d string }{
-			{"o", "Open URL / search"},
-			{"f", "Follow link by number"},
-			{"H", "Go back in history"},
-			{"L", "Go forward in history"},
-			{"r", "Reload page"},
-			{"B", "Bookmark current page"},
-			{"R", "Add to read later"},
-			{"Ctrl+h", "Toggle history panel"},
-		}},
-		{"Tabs", []struct{ k, d string }{
-			{"Ctrl+t", "New tab"},
-			{"Ctrl+w", "Close tab"},
-			{"gt / Tab", "Next tab"},
-			{"gT / S-Tab", "Previous tab"},
-		}},
-		{"Modes", []struct{ k, d string }{
-			{":", "Command mode"},
-			{"/", "Search on page"},
-			{"Space", "Leader key (shortcut palette)"},
-			{"?", "Show this help"},
-		}},
-		{"Commands", []struct{ k, d string }{
+	sections := append(m.keys.helpSections(), []helpSection{
+		{"Commands", []helpEntry{
 			{":open <url>", "Open URL"},
 			{":theme <n>", "Change theme"},
 			{":tabnew", "New tab"},
 			{":tabclose", "Close tab"},
+			{":tabopen <n>", "Open link n (or a URL) in a new tab"},
+			{":bgopen <n>", "Open link n (or a URL) in a background tab"},
+			{":undo", "Reopen the last closed tab"},
 			{":vsplit", "Vertical split"},
 			{":hsplit", "Horizontal split"},
 			{":unsplit", "Remove split"},
+			{":resize <n>", "Resize split (0.3, 30%, +10, -10)"},
 			{":history", "Toggle history panel"},
+			{":grep <terms>", "Search the text of visited pages"},
 			{":clearhistory", "Clear all history"},
+			{":clearcache", "Clear the HTTP cache"},
+			{":offline", "Toggle offline mode (serve from cache)"},
+			{":cookies", "List cookies (rm <n>|<domain>, clear, policy)"},
+			{":plugins", "List site handlers and plugins"},
+			{":submit [n]", "Submit the nth form on the page"},
+			{":noh", "Clear search highlighting"},
+			{":session save <n>", "Save tabs and splits as a session"},
+			{":session load <n>", "Restore a saved session"},
+			{":session", "List saved sessions"},
 			{":quit", "Quit tsurf"},
 		}},
-		{"Feeds & Search", []struct{ k, d string }{
+		{"Feeds & Search", []helpEntry{
 			{":hn [type]", "Hacker News (top/new/best/ask/show)"},
 			{":reddit <sub>", "Browse subreddit"},
 			{":rss <url>", "Load RSS/Atom feed"},
 			{":search <q>", "DuckDuckGo search"},
-			{":bookmarks", "List bookmarks"},
+			{":bookmarks [tag:t]", "List bookmarks by tag"},
+			{":bmanager [tag:t]", "Manage bookmarks (dd, rename, retag)"},
 			{":readlater", "List read later queue"},
-			{":bookmark", "Bookmark current page"},
-		}},
-		{"Leader Key (Space+...)", []struct{ k, d string }{
-			{"Space o", "Open URL"},
-			{"Space b", "Back"},
-			{"Space f", "Forward"},
-			{"Space l", "Follow link"},
-			{"Space r", "Reload"},
-			{"Space t", "New tab"},
-			{"Space w", "Close tab"},
-			{"Space n/p", "Next/Prev tab"},
-			{"Space h", "Hacker News"},
-			{"Space e", "Reddit"},
-			{"Space s", "Search"},
-			{"Space a", "RSS feed"},
-			{"Space B", "Bookmarks"},
-			{"Space R", "Read later"},
-			{"Space H", "History panel"},
-			{"Space T", "Cycle theme"},
-			{"Space v", "Split vertical"},
-			{"Space ?", "Help"},
+			{":bookmark [+tag]", "Bookmark current page with tags"},
+			{":bmrename <title>", "Rename the bookmark"},
+			{":bmtag <tags>", "Set tags (+tag adds, -tag removes)"},
+			{":bmimport <file>", "Import browser bookmarks (HTML, Firefox/Chrome JSON)"},
+			{":bmexport <file>", "Export bookmarks as Netscape HTML"},
+			{":export json <file>", "Save the page or feed items as JSON"},
 		}},
-	}
+	}...)
 
 	for _, section := range sections {
 		sb.WriteString(sectionStyle.Render(section.name))
 		sb.WriteString("\n\n")
-		for _, binding := range section.keys {
+		for _, binding := range section.entries {
 			sb.WriteString(keyStyle.Render(binding.k))
 			sb.WriteString(descStyle.Render(binding.d))
 			sb.WriteString("\n")
@@ -1644,6 +1986,7 @@ func (m *Model) showHelp() {
 		sb.WriteString("\n")
 	}
 
+	ts.clearPage()
 	ts.viewport.SetContent(sb.String())
 	m.tabBar.SetActiveTitle("Help - Keybindings")
 	m.statusBar.SetTitle("Help - Keybindings")
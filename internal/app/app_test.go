@@ -0,0 +1,46 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/vidyasagar/tsurf/internal/feeds"
+	"github.com/vidyasagar/tsurf/internal/theme"
+	"github.com/vidyasagar/tsurf/internal/ui"
+)
+
+func TestThemeRerendersFeedTab(t *testing.T) {
+	defer theme.Set(theme.Current.Name)
+
+	m := Model{
+		tabBar:    ui.NewTabBar(),
+		urlBar:    ui.NewURLBar(),
+		statusBar: ui.NewStatusBar(),
+		tabStates: make(map[int]*tabState),
+	}
+	tab := m.tabBar.ActiveTab()
+	ts := newTabState()
+	ts.viewport.SetSize(80, 24)
+	ts.history.Push("https://example.com/")
+	m.tabStates[tab.ID] = ts
+
+	// :hn does not push history, so the tab's current URL is the page before.
+	stories := []feeds.HNStory{{ID: 1, Title: "A story", URL: "https://example.com/story", Score: 10}}
+	title := "Hacker News - Top Stories"
+	content, links := feeds.RenderHNStories(stories, title)
+	data := &feeds.Data{Kind: "hn", Title: title, Items: stories}
+	model, _ := m.handleFeedLoaded(feedLoadedMsg{tabID: tab.ID, content: content, title: title, links: links, data: data})
+	m = model.(Model)
+
+	model, cmd := m.executeCommand("theme nord")
+	m = model.(Model)
+	if cmd != nil {
+		t.Error("theme change started a load")
+	}
+	ts = m.tabStates[tab.ID]
+	if ts.loading || ts.feedData != data || ts.viewport.Content() != content || len(ts.feedLinks) != len(links) {
+		t.Errorf("feed tab not redrawn from its items: loading %v, content:\n%s", ts.loading, ts.viewport.Content())
+	}
+	if got := ts.history.Current(); got != "https://example.com/" {
+		t.Errorf("history moved to %q", got)
+	}
+}
@@ -0,0 +1,309 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vidyasagar/tsurf/internal/browser"
+	"github.com/vidyasagar/tsurf/internal/storage"
+)
+
+// bookmarkCommand handles :bookmark [+tag ...], bookmarking the page with
+// the tags, or adding them if it is already bookmarked.
+func (m *Model) bookmarkCommand(args []string) {
+	if m.bookmarks == nil {
+		m.statusBar.SetMessage("Bookmarks not available")
+		return
+	}
+	tab := m.tabBar.ActiveTab()
+	if tab == nil || tab.URL == "" {
+		m.statusBar.SetMessage("No page to bookmark")
+		return
+	}
+
+	tags := storage.NormalizeTags(args)
+	switch {
+	case m.bookmarks.Add(tab.URL, tab.Title, tags...):
+		m.statusBar.SetMessage(fmt.Sprintf("Bookmarked: %s%s", tab.Title, formatTags(tags)))
+	case len(tags) > 0:
+		m.bookmarks.AddTags(tab.URL, tags...)
+		m.statusBar.SetMessage(fmt.Sprintf("Tagged: %s%s", tab.Title, formatTags(tags)))
+	default:
+		m.statusBar.SetMessage("Already bookmarked")
+	}
+	m.refreshBookmarkPanel()
+}
+
+// showBookmarks renders the bookmark tree in the active tab, limited to a
+// tag when args has "tag:<name>".
+func (m *Model) showBookmarks(args []string) {
+	if m.bookmarks == nil {
+		m.statusBar.SetMessage("Bookmarks not available")
+		return
+	}
+	ts := m.activeTabState()
+	if ts == nil {
+		return
+	}
+
+	tag := parseTagFilter(args)
+	bookmarks := m.bookmarks.List()
+	links := ts.showList(m.renderWidth(ts), func(int) (string, []browser.Link) {
+		return storage.RenderBookmarks(bookmarks, tag)
+	})
+	title := "Bookmarks"
+	if tag != "" {
+		title += " tag:" + tag
+	}
+	m.tabBar.SetActiveTitle(title)
+	m.statusBar.SetTitle(title)
+	m.statusBar.SetLinkCount(len(links))
+}
+
+// openBookmarkPanel shows the bookmark manager, limited to a tag when args
+// has "tag:<name>". The history panel is closed, as both use the same side.
+func (m *Model) openBookmarkPanel(args []string) {
+	if m.bookmarks == nil {
+		m.statusBar.SetMessage("Bookmarks not available")
+		return
+	}
+	m.historyPanel.Hide()
+	m.bookmarkPanel.SetBookmarks(m.bookmarks.List(), parseTagFilter(args))
+	m.bookmarkPanel.Show()
+	m.mode = ModeBookmarks
+	m.statusBar.SetMode("BOOKMARKS")
+	m.layout()
+}
+
+// closeBookmarkPanel hides the bookmark manager and returns to normal mode.
+func (m *Model) closeBookmarkPanel() {
+	m.bookmarkPanel.Hide()
+	m.mode = ModeNormal
+	m.statusBar.SetMode("NORMAL")
+	m.layout()
+}
+
+// refreshBookmarkPanel reloads the bookmark manager after a change.
+func (m *Model) refreshBookmarkPanel() {
+	if m.bookmarks != nil && m.bookmarkPanel.IsVisible() {
+		m.bookmarkPanel.SetBookmarks(m.bookmarks.List(), m.bookmarkPanel.Filter())
+	}
+}
+
+// handleBookmarkMode processes keys when the bookmark manager is active.
+func (m Model) handleBookmarkMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key != "g" && key != "d" {
+		m.bookmarkPanel.ResetKeys()
+	}
+
+	switch key {
+	case "j", "down":
+		m.bookmarkPanel.CursorDown()
+	case "k", "up":
+		m.bookmarkPanel.CursorUp()
+	case "g":
+		if m.bookmarkPanel.HandleRepeatKey("g") {
+			m.bookmarkPanel.GotoTop()
+		}
+	case "G":
+		m.bookmarkPanel.GotoBottom()
+	case "ctrl+d":
+		m.bookmarkPanel.HalfPageDown()
+	case "ctrl+u":
+		m.bookmarkPanel.HalfPageUp()
+
+	case "d":
+		if !m.bookmarkPanel.HandleRepeatKey("d") {
+			return m, nil
+		}
+		if b := m.bookmarkPanel.SelectedBookmark(); b != nil && m.bookmarks.Remove(b.URL) {
+			m.refreshBookmarkPanel()
+			m.statusBar.SetMessage(fmt.Sprintf("Deleted bookmark: %s", b.Title))
+		}
+
+	case "r":
+		if b := m.bookmarkPanel.SelectedBookmark(); b != nil {
+			return m, m.openCommand("bmrename " + b.Title)
+		}
+	case "t":
+		if b := m.bookmarkPanel.SelectedBookmark(); b != nil {
+			return m, m.openCommand("bmtag " + strings.Join(b.Tags, " "))
+		}
+
+	case "enter", "l":
+		if m.bookmarkPanel.ToggleFolder() {
+			return m, nil
+		}
+		b := m.bookmarkPanel.SelectedBookmark()
+		if b == nil {
+			return m, nil
+		}
+		m.tabBar.NewTab()
+		tab := m.tabBar.ActiveTab()
+		m.tabStates[tab.ID] = newTabState()
+		m.closeBookmarkPanel()
+		m.syncTabUI()
+		return m, m.navigateTo(b.URL)
+
+	case "esc", "q":
+		m.closeBookmarkPanel()
+	}
+	return m, nil
+}
+
+// bookmarkTarget returns the URL that :bmrename and :bmtag change: the
+// selected bookmark while the manager is open, else the active page.
+func (m *Model) bookmarkTarget() string {
+	if m.bookmarkPanel.IsVisible() {
+		if b := m.bookmarkPanel.SelectedBookmark(); b != nil {
+			return b.URL
+		}
+		return ""
+	}
+	if tab := m.tabBar.ActiveTab(); tab != nil {
+		return tab.URL
+	}
+	return ""
+}
+
+// renameBookmark handles :bmrename <title>.
+func (m *Model) renameBookmark(args []string) {
+	if m.bookmarks == nil {
+		m.statusBar.SetMessage("Bookmarks not available")
+		return
+	}
+	title := strings.Join(args, " ")
+	if title == "" {
+		m.statusBar.SetMessage("Usage: :bmrename <title>")
+		return
+	}
+	if url := m.bookmarkTarget(); url == "" || !m.bookmarks.Rename(url, title) {
+		m.statusBar.SetMessage("Not bookmarked")
+		return
+	}
+	m.refreshBookmarkPanel()
+	m.statusBar.SetMessage(fmt.Sprintf("Renamed to: %s", title))
+}
+
+// retagBookmark handles :bmtag [tag ...] [+tag ...] [-tag ...]. Bare tags
+// replace the bookmark's tags, +tag adds one and -tag removes one.
+func (m *Model) retagBookmark(args []string) {
+	if m.bookmarks == nil {
+		m.statusBar.SetMessage("Bookmarks not available")
+		return
+	}
+	url := m.bookmarkTarget()
+	b := m.bookmarks.Get(url)
+	if b == nil {
+		m.statusBar.SetMessage("Not bookmarked")
+		return
+	}
+
+	tags := b.Tags
+	if slices.ContainsFunc(args, func(a string) bool { return !strings.HasPrefix(a, "+") && !strings.HasPrefix(a, "-") }) {
+		tags = nil
+	}
+	for _, a := range args {
+		if name, ok := strings.CutPrefix(a, "-"); ok {
+			tags = slices.DeleteFunc(tags, func(t string) bool { return strings.EqualFold(t, name) })
+		} else {
+			tags = append(tags, a)
+		}
+	}
+
+	m.bookmarks.SetTags(url, tags)
+	m.refreshBookmarkPanel()
+	if tags = storage.NormalizeTags(tags); len(tags) == 0 {
+		m.statusBar.SetMessage("Tags cleared")
+	} else {
+		m.statusBar.SetMessage("Tags:" + formatTags(tags))
+	}
+}
+
+// importBookmarks handles :bmimport <file>, reading a browser's bookmark
+// export (Netscape HTML, Firefox JSON or Chrome's Bookmarks file).
+func (m *Model) importBookmarks(args []string) {
+	if m.bookmarks == nil {
+		m.statusBar.SetMessage("Bookmarks not available")
+		return
+	}
+	if len(args) == 0 {
+		m.statusBar.SetMessage("Usage: :bmimport <file>")
+		return
+	}
+
+	path := expandHome(strings.Join(args, " "))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		m.statusBar.SetMessage(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	parsed, err := storage.ParseBookmarkFile(data)
+	if err != nil {
+		m.statusBar.SetMessage(fmt.Sprintf("Error: %s: %s", filepath.Base(path), err))
+		return
+	}
+	added, updated := m.bookmarks.Import(parsed)
+	m.refreshBookmarkPanel()
+	m.statusBar.SetMessage(fmt.Sprintf("Imported %d bookmarks: %d new, %d retagged", len(parsed), added, updated))
+}
+
+// exportBookmarks handles :bmexport <file>, writing Netscape bookmark HTML
+// that browsers can import.
+func (m *Model) exportBookmarks(args []string) {
+	if m.bookmarks == nil {
+		m.statusBar.SetMessage("Bookmarks not available")
+		return
+	}
+	if len(args) == 0 {
+		m.statusBar.SetMessage("Usage: :bmexport <file>")
+		return
+	}
+
+	path := expandHome(strings.Join(args, " "))
+	f, err := os.Create(path)
+	if err == nil {
+		err = storage.ExportNetscape(f, m.bookmarks.List())
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		m.statusBar.SetMessage(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	m.statusBar.SetMessage(fmt.Sprintf("Exported %d bookmarks to %s", m.bookmarks.Count(), path))
+}
+
+// expandHome replaces a leading "~/" in a path with the home directory.
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
+
+// parseTagFilter returns the tag of a "tag:<name>" argument, if any.
+func parseTagFilter(args []string) string {
+	for _, a := range args {
+		if tag, ok := strings.CutPrefix(a, "tag:"); ok {
+			return tag
+		}
+	}
+	return ""
+}
+
+// formatTags returns tags as " +go +web" for status messages.
+func formatTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return " +" + strings.Join(tags, " +")
+}
@@ -0,0 +1,161 @@
+package app
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/vidyasagar/tsurf/internal/storage"
+	"github.com/vidyasagar/tsurf/internal/theme"
+)
+
+// exCommands are the command names offered by Tab completion, one per case of
+// executeCommand (aliases left out).
+var exCommands = []string{
+	"bgopen", "bmanager", "bmexport", "bmimport", "bmrename", "bmtag", "bookmark",
+	"bookmarks", "clearcache", "clearhistory", "cookies", "export", "grep", "help",
+	"history", "hn", "hsplit", "nohlsearch", "offline", "open", "plugins", "quit",
+	"readlater", "reddit", "resize", "rss", "search", "session", "submit", "tabclose",
+	"tabnew", "tabopen", "theme", "undo", "unsplit", "vsplit",
+}
+
+// maxURLCompletions bounds the bookmark and history URLs offered at once.
+const maxURLCompletions = 50
+
+// completeCommand is the command bar's Completer: command names for the
+// first word, and arguments that depend on the command after it.
+func (m *Model) completeCommand(line string) (int, []string) {
+	start := strings.LastIndex(line, " ") + 1
+	word := line[start:]
+	fields := strings.Fields(line[:start])
+
+	if len(fields) == 0 {
+		var names []string
+		for _, name := range completePrefix(exCommands, word) {
+			names = append(names, name+" ")
+		}
+		return start, names
+	}
+
+	switch fields[0] {
+	case "theme":
+		return start, completePrefix(theme.List(), word)
+	case "hn":
+		return start, completePrefix([]string{"top", "new", "best", "ask", "show"}, word)
+	case "offline":
+		return start, completePrefix([]string{"on", "off"}, word)
+	case "export":
+		if len(fields) == 1 {
+			return start, completePrefix([]string{"json"}, word)
+		}
+	case "reddit":
+		if m.config != nil {
+			return start, completePrefix(m.config.Subreddits, word)
+		}
+	case "rss":
+		if m.config != nil {
+			return start, completeURL(m.config.RSSFeeds, word)
+		}
+	case "o", "open", "tabopen", "to", "bgopen", "bg", "tabnew", "tab":
+		return start, completeURL(m.knownURLs(), word)
+	case "session":
+		if len(fields) == 1 {
+			return start, completePrefix([]string{"save", "load", "delete"}, word)
+		}
+		if m.sessions != nil && (fields[1] == "load" || fields[1] == "delete" || fields[1] == "rm") {
+			var names []string
+			for _, s := range m.sessions.List() {
+				names = append(names, s.Name)
+			}
+			return start, completePrefix(names, word)
+		}
+	case "bookmarks", "bm", "bmanager", "bmm":
+		if m.bookmarks != nil {
+			return start, completeTags(m.bookmarks.Tags(), "tag:", word)
+		}
+	case "bookmark", "bmtag":
+		if m.bookmarks != nil {
+			prefix := word[:len(word)-len(strings.TrimLeft(word, "+-"))]
+			return start, completeTags(m.bookmarks.Tags(), prefix, word)
+		}
+	case "cookies":
+		if len(fields) == 1 {
+			return start, completePrefix([]string{"rm", "clear", "policy"}, word)
+		}
+		if fields[1] == "policy" {
+			return start, completePrefix([]string{
+				storage.CookiePolicyAll, storage.CookiePolicyFirstParty,
+				storage.CookiePolicyAllowList, storage.CookiePolicyBlock,
+			}, word)
+		}
+	}
+	return start, nil
+}
+
+// knownURLs returns the bookmarked URLs followed by the visited ones, newest
+// first, without duplicates.
+func (m *Model) knownURLs() []string {
+	var urls []string
+	if m.bookmarks != nil {
+		for _, b := range m.bookmarks.List() {
+			urls = append(urls, b.URL)
+		}
+	}
+	if m.historyStore != nil {
+		for _, e := range m.historyStore.List() {
+			if !slices.Contains(urls, e.URL) {
+				urls = append(urls, e.URL)
+			}
+		}
+	}
+	return urls
+}
+
+// completePrefix returns the words starting with prefix, ignoring case.
+func completePrefix(words []string, prefix string) []string {
+	var matches []string
+	prefix = strings.ToLower(prefix)
+	for _, w := range words {
+		if strings.HasPrefix(strings.ToLower(w), prefix) {
+			matches = append(matches, w)
+		}
+	}
+	return matches
+}
+
+// completeTags returns the tags matching word after its prefix ("tag:",
+// "+" or "-"), with the prefix kept.
+func completeTags(tags []string, prefix, word string) []string {
+	rest, ok := strings.CutPrefix(word, prefix)
+	if !ok {
+		if !strings.HasPrefix(prefix, word) {
+			return nil
+		}
+		rest = ""
+	}
+	var matches []string
+	for _, t := range completePrefix(tags, rest) {
+		matches = append(matches, prefix+t)
+	}
+	return matches
+}
+
+// completeURL returns the URLs containing word anywhere, so "golang" finds
+// https://go.dev/blog/golang. URLs starting with it come first.
+func completeURL(urls []string, word string) []string {
+	var prefixed, contained []string
+	lower := strings.ToLower(word)
+	for _, u := range urls {
+		lu := strings.ToLower(u)
+		switch {
+		case strings.HasPrefix(lu, lower) || strings.HasPrefix(strings.TrimPrefix(lu, "https://"), lower):
+			prefixed = append(prefixed, u)
+		case strings.Contains(lu, lower):
+			contained = append(contained, u)
+		}
+	}
+	matches := append(prefixed, contained...)
+	if len(matches) > maxURLCompletions {
+		matches = matches[:maxURLCompletions]
+	}
+	return matches
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompleteCommand(t *testing.T) {
+	var m Model
+	tests := []struct {
+		line  string
+		start int
+		want  []string
+	}{
+		{"tabo", 0, []string{"tabopen "}},
+		{"re", 0, []string{"readlater ", "reddit ", "resize "}},
+		{"theme dr", 6, []string{"dracula"}},
+		{"offline ", 8, []string{"on", "off"}},
+		{"session l", 8, []string{"load"}},
+		{"nosuchcommand x", 14, nil},
+	}
+	for _, tt := range tests {
+		start, got := m.completeCommand(tt.line)
+		if start != tt.start || !slices.Equal(got, tt.want) {
+			t.Errorf("completeCommand(%q) = %d, %q; want %d, %q", tt.line, start, got, tt.start, tt.want)
+		}
+	}
+}
+
+func TestCompleteTags(t *testing.T) {
+	tags := []string{"go", "golang", "web"}
+	if got := completeTags(tags, "tag:", "tag:go"); !slices.Equal(got, []string{"tag:go", "tag:golang"}) {
+		t.Errorf("completeTags(tag:go) = %q", got)
+	}
+	if got := completeTags(tags, "+", "+w"); !slices.Equal(got, []string{"+web"}) {
+		t.Errorf("completeTags(+w) = %q", got)
+	}
+}
+
+func TestCompleteURL(t *testing.T) {
+	urls := []string{"https://go.dev/blog/golang", "https://golang.org", "https://example.com"}
+	got := completeURL(urls, "golang")
+	want := []string{"https://golang.org", "https://go.dev/blog/golang"}
+	if !slices.Equal(got, want) {
+		t.Errorf("completeURL = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,146 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vidyasagar/tsurf/internal/browser"
+	"github.com/vidyasagar/tsurf/internal/storage"
+)
+
+// cookiesCommand handles :cookies [rm <n>|<domain> | clear | policy [name]].
+func (m *Model) cookiesCommand(args []string) {
+	if m.cookies == nil {
+		m.statusBar.SetMessage("Cookies not available")
+		return
+	}
+
+	if len(args) == 0 {
+		m.showCookies()
+		return
+	}
+
+	switch args[0] {
+	case "rm", "del", "delete":
+		if len(args) < 2 {
+			m.statusBar.SetMessage("Usage: :cookies rm <n>[,<n>|<n>-<m>] or :cookies rm <domain>")
+			return
+		}
+		m.deleteCookies(args[1:])
+	case "clear":
+		m.cookies.Clear()
+		m.showCookies()
+		m.statusBar.SetMessage("All cookies deleted")
+	case "policy":
+		if len(args) < 2 {
+			m.statusBar.SetMessage(fmt.Sprintf("Cookie policy: %s (available: %s)",
+				m.cookies.Policy(), strings.Join(storage.CookiePolicies, ", ")))
+			return
+		}
+		m.setCookiePolicy(args[1])
+	default:
+		m.statusBar.SetMessage("Usage: :cookies [rm <n>|<domain> | clear | policy <name>]")
+	}
+}
+
+// showCookies renders the cookie manager page in the active tab.
+func (m *Model) showCookies() {
+	ts := m.activeTabState()
+	if ts == nil {
+		return
+	}
+
+	m.cookieList = m.cookies.All()
+	cookies, policy := m.cookieList, m.cookies.Policy()
+	links := ts.showList(m.renderWidth(ts), func(int) (string, []browser.Link) {
+		return storage.RenderCookies(cookies, policy)
+	})
+	m.tabBar.SetActiveTitle("Cookies")
+	m.statusBar.SetTitle("Cookies")
+	m.statusBar.SetLinkCount(len(links))
+}
+
+// deleteCookies deletes cookies by their number on the :cookies page, or
+// every cookie of a domain.
+func (m *Model) deleteCookies(args []string) {
+	sel, ok := parseSelection(strings.Join(args, ","), len(m.cookieList))
+	if !ok {
+		domain := args[0]
+		if n := m.cookies.DeleteDomain(domain); n > 0 {
+			m.showCookies()
+			m.statusBar.SetMessage(fmt.Sprintf("Deleted %d cookies for %s", n, domain))
+		} else {
+			m.statusBar.SetMessage(fmt.Sprintf("No cookies for %s (numbers refer to the :cookies page)", domain))
+		}
+		return
+	}
+
+	deleted := 0
+	for _, n := range sel {
+		if m.cookies.Delete(m.cookieList[n-1]) {
+			deleted++
+		}
+	}
+	m.showCookies()
+	m.statusBar.SetMessage(fmt.Sprintf("Deleted %d cookies", deleted))
+}
+
+// setCookiePolicy changes the cookie policy and saves it to the config.
+func (m *Model) setCookiePolicy(policy string) {
+	if !storage.ValidCookiePolicy(policy) {
+		m.statusBar.SetMessage(fmt.Sprintf("Unknown cookie policy: %s (available: %s)",
+			policy, strings.Join(storage.CookiePolicies, ", ")))
+		return
+	}
+
+	var allow []string
+	if m.config != nil {
+		allow = m.config.CookieAllow
+		m.config.CookiePolicy = policy
+		m.config.Save()
+	}
+	m.cookies.SetPolicy(policy, allow)
+	m.statusBar.SetMessage(fmt.Sprintf("Cookie policy: %s", policy))
+}
+
+// parseSelection parses link-number selections like "3", "1,4" and "2-5"
+// into sorted, de-duplicated numbers between 1 and max.
+func parseSelection(s string, max int) ([]int, bool) {
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, false
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil {
+				return nil, false
+			}
+		}
+		if first < 1 || last > max || first > last {
+			return nil, false
+		}
+		for n := first; n <= last; n++ {
+			seen[n] = true
+		}
+	}
+	if len(seen) == 0 {
+		return nil, false
+	}
+
+	sel := make([]int, 0, len(seen))
+	for n := range seen {
+		sel = append(sel, n)
+	}
+	sort.Ints(sel)
+	return sel, true
+}
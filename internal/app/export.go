@@ -0,0 +1,51 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/vidyasagar/tsurf/internal/browser"
+)
+
+// exportPage handles :export json <file>, writing the active tab as JSON:
+// the extracted article for web pages, or the fetched items for feed pages.
+func (m *Model) exportPage(args []string) {
+	if len(args) < 2 {
+		m.statusBar.SetMessage("Usage: :export json <file>")
+		return
+	}
+	if args[0] != "json" {
+		m.statusBar.SetMessage(fmt.Sprintf("Unknown export format: %s (json)", args[0]))
+		return
+	}
+	ts := m.activeTabState()
+	if ts == nil {
+		return
+	}
+
+	var v any
+	switch {
+	case ts.article != nil:
+		v = browser.ExportArticle(ts.article, ts.page)
+	case ts.feedData != nil:
+		v = ts.feedData
+	default:
+		m.statusBar.SetMessage("Nothing to export on this page")
+		return
+	}
+
+	path := expandHome(strings.Join(args[1:], " "))
+	f, err := os.Create(path)
+	if err == nil {
+		err = browser.WriteJSON(f, v)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		m.statusBar.SetMessage(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	m.statusBar.SetMessage(fmt.Sprintf("Exported to %s", path))
+}
@@ -0,0 +1,236 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vidyasagar/tsurf/internal/browser"
+	"github.com/vidyasagar/tsurf/internal/ui"
+)
+
+// pageForms returns the forms of the active tab's page.
+func (m *Model) pageForms() []*browser.Form {
+	if ts := m.activeTabState(); ts != nil && ts.page != nil {
+		return ts.page.Forms
+	}
+	return nil
+}
+
+// openFieldPrompt opens the i<n> prompt for choosing a form field.
+func (m *Model) openFieldPrompt() tea.Cmd {
+	if len(m.pageForms()) == 0 {
+		m.statusBar.SetMessage("No form fields on this page")
+		return nil
+	}
+	m.mode = ModeField
+	m.statusBar.SetMode("FIELD")
+	return m.commandBar.Open(ui.CommandField)
+}
+
+// editFirstField starts editing the first text field on the page (gi).
+func (m *Model) editFirstField() tea.Cmd {
+	ff := browser.FirstEditable(m.pageForms())
+	if ff == nil {
+		m.statusBar.SetMessage("No form fields on this page")
+		return nil
+	}
+	return m.editField(ff)
+}
+
+// activateField acts on a field chosen by number: text fields open the
+// editor, checkboxes and radio buttons toggle, and buttons submit the form.
+func (m *Model) activateField(input string) tea.Cmd {
+	num, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil {
+		m.statusBar.SetMessage(fmt.Sprintf("Invalid field number: %s", input))
+		return nil
+	}
+
+	ff := browser.FindField(m.pageForms(), num)
+	if ff == nil {
+		m.statusBar.SetMessage(fmt.Sprintf("Field {%d} not found", num))
+		return nil
+	}
+
+	switch {
+	case ff.Type == "submit":
+		return m.submitForm(ff.Form(), ff)
+	case ff.Editable():
+		return m.editField(ff)
+	default:
+		ff.Toggle()
+		m.refreshForms()
+		return nil
+	}
+}
+
+// editField opens the insert-mode editor for a form field.
+func (m *Model) editField(ff *browser.FormField) tea.Cmd {
+	m.editingField = ff
+	m.mode = ModeInsert
+	m.statusBar.SetMode("INSERT")
+
+	value := ff.Value
+	hint := "Enter: submit · Tab: next field · Esc: done"
+	switch ff.Type {
+	case "select":
+		value = ff.OptionLabel()
+		hint = "Up/Down: choose option · " + hint
+	case "file":
+		hint = "Path of the file to upload · " + hint
+	}
+	m.statusBar.SetMessage(hint)
+
+	label := fmt.Sprintf("{%d} %s", ff.Index, ff.Label)
+	return m.commandBar.OpenEdit(label, value, ff.Type == "password")
+}
+
+// handleFieldEdit processes keys while a form field is being edited.
+func (m Model) handleFieldEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	ff := m.editingField
+
+	switch msg.String() {
+	case "esc":
+		m.saveField()
+		m.stopEditing()
+		return m, nil
+
+	case "enter":
+		m.saveField()
+		m.stopEditing()
+		cmd := m.submitForm(ff.Form(), nil)
+		return m, cmd
+
+	case "tab", "shift+tab":
+		delta := 1
+		if msg.String() == "shift+tab" {
+			delta = -1
+		}
+		m.saveField()
+		next := ff.Form().NextEditable(ff, delta)
+		if next == nil {
+			return m, nil
+		}
+		m.commandBar.Close()
+		cmd := m.editField(next)
+		return m, cmd
+
+	case "up", "down":
+		if ff.Type == "select" {
+			delta := 1
+			if msg.String() == "up" {
+				delta = -1
+			}
+			ff.SetOption(m.commandBar.Value())
+			ff.CycleOption(delta)
+			m.commandBar.SetValue(ff.OptionLabel())
+			m.refreshForms()
+		}
+		return m, nil
+	}
+
+	cb, cmd := m.commandBar.Update(msg)
+	m.commandBar = *cb
+	return m, cmd
+}
+
+// saveField stores the editor's text in the field being edited.
+func (m *Model) saveField() {
+	ff := m.editingField
+	if ff == nil {
+		return
+	}
+
+	value := m.commandBar.Value()
+	if ff.Type == "select" {
+		if !ff.SetOption(value) {
+			m.statusBar.SetMessage(fmt.Sprintf("No option %q", value))
+		}
+	} else {
+		ff.Value = value
+	}
+	m.refreshForms()
+}
+
+// stopEditing closes the field editor and returns to normal mode.
+func (m *Model) stopEditing() {
+	m.editingField = nil
+	m.commandBar.Close()
+	m.mode = ModeNormal
+	m.statusBar.SetMode("NORMAL")
+	m.statusBar.SetMessage("")
+}
+
+// refreshForms re-renders the active page's forms, keeping the scroll position.
+func (m *Model) refreshForms() {
+	ts := m.activeTabState()
+	if ts == nil || ts.page == nil {
+		return
+	}
+	offset := ts.viewport.YOffset()
+	ts.page.RefreshForms()
+	ts.viewport.SetContent(ts.page.Content)
+	ts.viewport.SetYOffset(offset)
+}
+
+// submitCommand handles :submit [n], submitting the nth form on the page.
+func (m *Model) submitCommand(args []string) tea.Cmd {
+	forms := m.pageForms()
+	if len(forms) == 0 {
+		m.statusBar.SetMessage("No forms on this page")
+		return nil
+	}
+
+	n := 1
+	if len(args) > 0 {
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil || n < 1 || n > len(forms) {
+			m.statusBar.SetMessage(fmt.Sprintf("Usage: :submit [1-%d]", len(forms)))
+			return nil
+		}
+	}
+	return m.submitForm(forms[n-1], nil)
+}
+
+// submitForm sends a form in the active tab. Without an explicit submitter
+// the form's first submit button is used, as browsers do on Enter.
+func (m *Model) submitForm(form *browser.Form, submitter *browser.FormField) tea.Cmd {
+	tab := m.tabBar.ActiveTab()
+	if tab == nil {
+		return nil
+	}
+	ts := m.tabStates[tab.ID]
+
+	if submitter == nil {
+		for _, ff := range form.Fields {
+			if ff.Type == "submit" {
+				submitter = ff
+				break
+			}
+		}
+	}
+
+	req, err := form.Request(submitter)
+	if err != nil {
+		m.statusBar.SetMessage(fmt.Sprintf("Error: %s", err))
+		return nil
+	}
+
+	if ts.cancelFunc != nil {
+		ts.cancelFunc()
+	}
+	ts.loading = true
+	m.statusBar.SetLoading(true)
+	if req.Method == http.MethodPost {
+		m.statusBar.SetMessage(fmt.Sprintf("Submitting form to %s...", req.URL))
+	} else {
+		m.statusBar.SetMessage("")
+		m.urlBar.SetValue(req.URL)
+	}
+	m.tabBar.SetTitle(tab.ID, "Loading...")
+
+	return m.fetchPage(tab.ID, ts, req, true)
+}
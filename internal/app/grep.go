@@ -0,0 +1,113 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vidyasagar/tsurf/internal/browser"
+	"github.com/vidyasagar/tsurf/internal/storage"
+	"github.com/vidyasagar/tsurf/internal/theme"
+)
+
+// maxGrepResults bounds the pages listed by :grep.
+const maxGrepResults = 50
+
+// grepCommand handles :grep <terms>, searching the text of visited pages.
+func (m *Model) grepCommand(args []string) {
+	if m.historyStore == nil {
+		m.statusBar.SetMessage("History not available")
+		return
+	}
+	if len(args) == 0 {
+		m.statusBar.SetMessage("Usage: :grep <terms>")
+		return
+	}
+	ts := m.activeTabState()
+	if ts == nil {
+		return
+	}
+
+	query := strings.Join(args, " ")
+	matches, err := m.historyStore.Grep(query, maxGrepResults)
+	if err != nil {
+		m.statusBar.SetMessage(fmt.Sprintf("Error: %s", err))
+		return
+	}
+
+	links := ts.showList(m.renderWidth(ts), func(width int) (string, []browser.Link) {
+		return renderGrep(query, matches, width)
+	})
+	title := "grep: " + query
+	m.tabBar.SetActiveTitle(title)
+	m.statusBar.SetTitle(title)
+	m.statusBar.SetLinkCount(len(links))
+	m.statusBar.SetMessage(fmt.Sprintf("%d pages match", len(matches)))
+}
+
+// renderGrep formats :grep results as a numbered link page, with the
+// matched words highlighted in each snippet.
+func renderGrep(query string, matches []storage.PageMatch, width int) (string, []browser.Link) {
+	t := theme.Current
+	matchStyle := lipgloss.NewStyle().
+		Foreground(t.Background).
+		Background(t.Warning)
+	snippetStyle := lipgloss.NewStyle().
+		Foreground(t.Text).
+		PaddingLeft(7).
+		Width(max(width-2, 20))
+
+	var sb strings.Builder
+	var links []browser.Link
+
+	sb.WriteString(fmt.Sprintf("  🔎 Pages matching %q\n", query))
+	sb.WriteString("  ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	if len(matches) == 0 {
+		sb.WriteString("  No visited page contains these words.\n")
+		return sb.String(), links
+	}
+
+	for i, pm := range matches {
+		idx := i + 1
+		title := pm.Title
+		if title == "" {
+			title = pm.URL
+		}
+		sb.WriteString(fmt.Sprintf("  [%d] %s\n", idx, title))
+		sb.WriteString(fmt.Sprintf("       %s · visited %s\n", pm.URL, timeAgo(pm.VisitedAt)))
+
+		snippet := strings.Join(strings.Fields(pm.Snippet), " ")
+		snippet = highlightMatches(snippet, matchStyle)
+		sb.WriteString(snippetStyle.Render(snippet) + "\n\n")
+
+		links = append(links, browser.Link{
+			Index: idx,
+			Text:  title,
+			URL:   pm.URL,
+		})
+	}
+
+	return sb.String(), links
+}
+
+// highlightMatches styles the text between the storage match markers.
+func highlightMatches(s string, style lipgloss.Style) string {
+	var sb strings.Builder
+	for {
+		start := strings.Index(s, storage.MatchStart)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(s[start:], storage.MatchEnd)
+		if end < 0 {
+			break
+		}
+		end += start
+		sb.WriteString(s[:start])
+		sb.WriteString(style.Render(s[start+len(storage.MatchStart) : end]))
+		s = s[end+len(storage.MatchEnd):]
+	}
+	sb.WriteString(s)
+	return strings.NewReplacer(storage.MatchStart, "", storage.MatchEnd, "").Replace(sb.String())
+}
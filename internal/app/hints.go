@@ -0,0 +1,130 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vidyasagar/tsurf/internal/browser"
+	"github.com/vidyasagar/tsurf/internal/ui"
+)
+
+// followTarget is where follow mode opens the chosen link.
+type followTarget int
+
+const (
+	followHere       followTarget = iota // f: the active tab
+	followNewTab                         // gf: a new tab, switched to
+	followBackground                     // F: a new tab in the background
+)
+
+// startFollow opens follow mode with letter hints on the visible links.
+func (m *Model) startFollow(target followTarget) tea.Cmd {
+	m.followTarget = target
+	m.mode = ModeFollow
+	m.statusBar.SetMode("FOLLOW")
+
+	cmd := m.commandBar.Open(ui.CommandFollow)
+	switch target {
+	case followNewTab:
+		m.commandBar.SetPrompt("gf")
+	case followBackground:
+		m.commandBar.SetPrompt("F")
+	}
+
+	if ts := m.activeTabState(); ts != nil {
+		var refs []browser.LinkRef
+		if ts.page != nil {
+			refs = ts.page.Refs
+		}
+		ts.viewport.ShowHints(refs, func(n int) bool {
+			_, ok := linkURL(ts, n)
+			return ok
+		})
+	}
+	return cmd
+}
+
+// updateHints narrows the hints to the typed letters and follows the link
+// once a full label has been typed. Digits are left for numeric following.
+func (m *Model) updateHints() tea.Cmd {
+	ts := m.activeTabState()
+	if ts == nil {
+		return nil
+	}
+
+	typed := strings.ToLower(m.commandBar.Value())
+	if isNumber(typed) {
+		ts.viewport.FilterHints("")
+		return nil
+	}
+
+	matching := ts.viewport.FilterHints(typed)
+	if len(matching) == 1 && matching[0].Label == typed {
+		m.commandBar.Close()
+		m.mode = ModeNormal
+		m.statusBar.SetMode("NORMAL")
+		return m.followNumber(matching[0].Link)
+	}
+	if len(matching) == 0 {
+		m.statusBar.SetMessage(fmt.Sprintf("No hint %q", typed))
+	} else {
+		m.statusBar.SetMessage("")
+	}
+	return nil
+}
+
+// followNumber opens link number n of the active tab where follow mode was
+// asked to open it.
+func (m *Model) followNumber(n int) tea.Cmd {
+	ts := m.activeTabState()
+	if ts == nil {
+		m.statusBar.SetMessage("No page loaded")
+		return nil
+	}
+	ts.viewport.ClearHints()
+
+	url, ok := linkURL(ts, n)
+	if !ok {
+		m.statusBar.SetMessage(fmt.Sprintf("Link [%d] not found", n))
+		return nil
+	}
+
+	target := m.followTarget
+	m.followTarget = followHere
+	switch target {
+	case followNewTab:
+		return m.openInNewTab(url)
+	case followBackground:
+		return m.openInBackground(url)
+	}
+	return m.navigateTo(url)
+}
+
+// linkURL looks up link number n among the page links, then the feed links
+// (HN, Reddit, RSS, Search, Bookmarks, Read Later).
+func linkURL(ts *tabState, n int) (string, bool) {
+	if ts.page != nil {
+		for _, link := range ts.page.Links {
+			if link.Index == n {
+				return link.URL, true
+			}
+		}
+	}
+	for _, link := range ts.feedLinks {
+		if link.Index == n {
+			return link.URL, true
+		}
+	}
+	return "", false
+}
+
+// isNumber reports whether s is a non-empty run of digits.
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	_, err := strconv.Atoi(s)
+	return err == nil && !strings.ContainsAny(s, "+-")
+}
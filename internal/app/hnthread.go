@@ -0,0 +1,221 @@
+package app
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vidyasagar/tsurf/internal/feeds"
+)
+
+// threadView is a Hacker News comment thread shown in a tab, with the
+// subthreads folded away.
+type threadView struct {
+	thread  *feeds.HNThread
+	folded  map[int]bool
+	loading map[int]bool // comments whose replies are being fetched
+	rows    []feeds.HNThreadRow
+	width   int
+}
+
+// clone returns a copy of the view with its own folds, for another tab. The
+// thread itself is shared: replies loaded in either tab show in both.
+func (tv *threadView) clone() *threadView {
+	return &threadView{
+		thread:  tv.thread,
+		folded:  maps.Clone(tv.folded),
+		loading: make(map[int]bool),
+		rows:    slices.Clone(tv.rows),
+		width:   tv.width,
+	}
+}
+
+// hnRepliesMsg is sent when the replies of a comment have been fetched.
+type hnRepliesMsg struct {
+	tabID   int
+	thread  *feeds.HNThread
+	id      int
+	replies []*feeds.HNCommentNode
+}
+
+// feedThread returns the comment thread behind a feed page, if it is one.
+func feedThread(data *feeds.Data) (*feeds.HNThread, bool) {
+	if data == nil {
+		return nil, false
+	}
+	thread, ok := data.Items.(*feeds.HNThread)
+	return thread, ok
+}
+
+// showThread renders the tab's thread at width, scrolling so that line is
+// at the top.
+func (m *Model) showThread(ts *tabState, width, line int) {
+	tv := ts.thread
+	content, links, rows := feeds.RenderHNThread(tv.thread, tv.folded, width)
+	tv.rows = rows
+	tv.width = width
+	ts.feedLinks = links
+	ts.viewport.SetContent(content)
+	ts.viewport.SetYOffset(line)
+}
+
+// currentComment returns the comment at the top of the screen: the one
+// whose text is showing there, or the first one below the story.
+func (tv *threadView) currentComment(offset int) (feeds.HNThreadRow, bool) {
+	if len(tv.rows) == 0 {
+		return feeds.HNThreadRow{}, false
+	}
+	cur := tv.rows[0]
+	for _, row := range tv.rows {
+		if row.Line > offset {
+			break
+		}
+		cur = row
+	}
+	return cur, true
+}
+
+// threadAction runs a fold or comment motion action on the active tab's
+// thread.
+func (m *Model) threadAction(action Action) tea.Cmd {
+	ts := m.activeTabState()
+	if ts == nil || ts.thread == nil {
+		m.statusBar.SetMessage("No comment thread on this page")
+		return nil
+	}
+	tv := ts.thread
+	offset := ts.viewport.YOffset()
+
+	switch action {
+	case ActionNextComment, ActionPrevComment:
+		m.jumpTopLevel(ts, action == ActionNextComment)
+		return nil
+	case ActionFoldCloseAll:
+		for _, c := range tv.thread.Comments {
+			if len(c.Kids) > 0 {
+				tv.folded[c.ID] = true
+			}
+		}
+		m.showThread(ts, tv.width, 0)
+		return nil
+	case ActionFoldOpenAll:
+		clear(tv.folded)
+		m.showThread(ts, tv.width, offset)
+		return nil
+	}
+
+	row, ok := tv.currentComment(offset)
+	if !ok {
+		return nil
+	}
+	node := tv.thread.Find(row.ID)
+	if node == nil {
+		return nil
+	}
+
+	open := action == ActionFoldOpen || (action == ActionFoldToggle && (tv.folded[node.ID] || node.Pending() > 0))
+	switch {
+	case open && node.Pending() > 0:
+		return m.loadReplies(ts, node)
+	case open:
+		delete(tv.folded, node.ID)
+	case tv.folded[node.ID] || len(node.Replies) == 0:
+		// Nothing left to close here: close the enclosing subthread, as zc
+		// does on a closed fold in vim.
+		parent := tv.thread.Find(node.Parent)
+		if parent == nil {
+			return nil
+		}
+		tv.folded[parent.ID] = true
+		m.showThread(ts, tv.width, tv.lineOf(parent.ID))
+		m.syncStatusBar()
+		return nil
+	default:
+		tv.folded[node.ID] = true
+	}
+	m.showThread(ts, tv.width, row.Line)
+	m.syncStatusBar()
+	return nil
+}
+
+// lineOf returns the line of a comment's header after the last render.
+func (tv *threadView) lineOf(id int) int {
+	for _, row := range tv.rows {
+		if row.ID == id {
+			return row.Line
+		}
+	}
+	return 0
+}
+
+// jumpTopLevel scrolls to the next (or previous) top-level comment.
+func (m *Model) jumpTopLevel(ts *tabState, next bool) {
+	offset := ts.viewport.YOffset()
+	target := -1
+	for _, row := range ts.thread.rows {
+		if row.Depth != 0 {
+			continue
+		}
+		if next && row.Line > offset {
+			target = row.Line
+			break
+		}
+		if !next && row.Line < offset {
+			target = row.Line
+		}
+	}
+	if target < 0 {
+		m.statusBar.SetMessage("No more comments")
+		return
+	}
+	ts.viewport.SetYOffset(target)
+	m.syncStatusBar()
+}
+
+// loadReplies fetches the replies left out of a comment in the background.
+func (m *Model) loadReplies(ts *tabState, node *feeds.HNCommentNode) tea.Cmd {
+	tv := ts.thread
+	if tv.loading[node.ID] {
+		return nil
+	}
+	tab := m.tabBar.ActiveTab()
+	if tab == nil {
+		return nil
+	}
+	tv.loading[node.ID] = true
+	m.statusBar.SetMessage("Loading replies...")
+
+	tabID, thread, id := tab.ID, tv.thread, node.ID
+	kids := append([]int(nil), node.Kids...)
+	client := m.hnClient
+	return func() tea.Msg {
+		return hnRepliesMsg{tabID: tabID, thread: thread, id: id, replies: client.FetchReplies(context.Background(), kids)}
+	}
+}
+
+// handleRepliesLoaded attaches fetched replies to their comment and opens
+// it. Replies for a thread the tab no longer shows are dropped.
+func (m *Model) handleRepliesLoaded(msg hnRepliesMsg) {
+	ts, ok := m.tabStates[msg.tabID]
+	if !ok || ts.thread == nil || ts.thread.thread != msg.thread {
+		return
+	}
+	tv := ts.thread
+	delete(tv.loading, msg.id)
+	node := tv.thread.Find(msg.id)
+	if node == nil {
+		return
+	}
+	node.Replies = msg.replies
+	node.Loaded = true
+	delete(tv.folded, msg.id)
+
+	// Keep the reader where they are; the opened comment is usually on screen.
+	m.showThread(ts, tv.width, ts.viewport.YOffset())
+	if m.isActiveTab(msg.tabID) {
+		m.statusBar.SetMessage("")
+		m.statusBar.SetLinkCount(len(ts.feedLinks))
+		m.syncStatusBar()
+	}
+}
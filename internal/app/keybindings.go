@@ -1,156 +1,510 @@
 package app
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
 
-// KeyMap defines all keybindings for tsurf.
-type KeyMap struct {
+	"github.com/vidyasagar/tsurf/internal/ui"
+)
+
+// Action names something a key binding does. Actions are what the "keys"
+// section of the config file binds key sequences to.
+type Action string
+
+const (
 	// Navigation
-	ScrollDown   key.Binding
-	ScrollUp     key.Binding
-	HalfPageDown key.Binding
-	HalfPageUp   key.Binding
-	GotoTop      key.Binding
-	GotoBottom   key.Binding
+	ActionScrollDown   Action = "scroll_down"
+	ActionScrollUp     Action = "scroll_up"
+	ActionHalfPageDown Action = "half_page_down"
+	ActionHalfPageUp   Action = "half_page_up"
+	ActionGotoTop      Action = "goto_top"
+	ActionGotoBottom   Action = "goto_bottom"
 
 	// Browser
-	OpenURL    key.Binding
-	Back       key.Binding
-	Forward    key.Binding
-	Reload     key.Binding
-	FollowLink key.Binding
+	ActionOpenURL        Action = "open_url"
+	ActionFollowLink     Action = "follow_link"
+	ActionFollowNewTab   Action = "follow_new_tab"
+	ActionFollowBg       Action = "follow_background"
+	ActionEditField      Action = "edit_field"
+	ActionEditFirstField Action = "edit_first_field"
+	ActionBack           Action = "back"
+	ActionForward        Action = "forward"
+	ActionReload         Action = "reload"
+	ActionBookmark       Action = "bookmark"
+	ActionReadLater      Action = "read_later"
+	ActionHistory        Action = "history"
+
+	// Comment threads
+	ActionFoldToggle   Action = "fold_toggle"
+	ActionFoldOpen     Action = "fold_open"
+	ActionFoldClose    Action = "fold_close"
+	ActionFoldOpenAll  Action = "fold_open_all"
+	ActionFoldCloseAll Action = "fold_close_all"
+	ActionNextComment  Action = "next_comment"
+	ActionPrevComment  Action = "prev_comment"
 
 	// Tabs
-	NewTab   key.Binding
-	CloseTab key.Binding
-	NextTab  key.Binding
-	PrevTab  key.Binding
+	ActionNewTab    Action = "new_tab"
+	ActionWindow    Action = "window" // close tab, or window command prefix while split
+	ActionCloseTab  Action = "close_tab"
+	ActionNextTab   Action = "next_tab"
+	ActionPrevTab   Action = "prev_tab"
+	ActionUndoClose Action = "undo_close"
+
+	// Splits
+	ActionSplitVertical   Action = "split_vertical"
+	ActionSplitHorizontal Action = "split_horizontal"
+	ActionSplitClose      Action = "split_close"
+	ActionSplitToggle     Action = "split_toggle"
 
 	// Modes
-	CommandMode key.Binding
-	SearchMode  key.Binding
+	ActionCommand        Action = "command"
+	ActionCommandHistory Action = "command_history"
+	ActionSearch         Action = "search"
+	ActionSearchNext     Action = "search_next"
+	ActionSearchPrev     Action = "search_prev"
+	ActionLeader         Action = "leader"
+	ActionHelp           Action = "help"
+	ActionQuit           Action = "quit"
 
-	// Actions
-	Quit      key.Binding
-	Help      key.Binding
-	Bookmark  key.Binding
-	ReadLater key.Binding
+	// Feeds and lists
+	ActionHackerNews      Action = "hacker_news"
+	ActionReddit          Action = "reddit"
+	ActionWebSearch       Action = "web_search"
+	ActionRSS             Action = "rss"
+	ActionBookmarks       Action = "bookmarks"
+	ActionBookmarkManager Action = "bookmark_manager"
+	ActionReadLaterList   Action = "read_later_list"
+	ActionThemeCycle      Action = "theme_cycle"
+)
 
-	// Splits
-	SplitVertical   key.Binding
-	SplitHorizontal key.Binding
-	SplitClose      key.Binding
-	SplitToggle     key.Binding
+// actionInfo describes an action for the help screen and leader palette.
+type actionInfo struct {
+	action Action
+	group  string // help screen section
+	desc   string // help screen description
+	column string // leader palette column
+	short  string // leader palette description
+}
+
+// actions lists every action in display order.
+var actions = []actionInfo{
+	{ActionScrollDown, "Navigation", "Scroll down", "Navigate", "Scroll down"},
+	{ActionScrollUp, "Navigation", "Scroll up", "Navigate", "Scroll up"},
+	{ActionHalfPageDown, "Navigation", "Half page down", "Navigate", "Page down"},
+	{ActionHalfPageUp, "Navigation", "Half page up", "Navigate", "Page up"},
+	{ActionGotoTop, "Navigation", "Go to top", "Navigate", "Top"},
+	{ActionGotoBottom, "Navigation", "Go to bottom", "Navigate", "Bottom"},
+
+	{ActionOpenURL, "Browsing", "Open URL / search", "Navigate", "Open URL"},
+	{ActionBack, "Browsing", "Go back in history", "Navigate", "Back"},
+	{ActionForward, "Browsing", "Go forward in history", "Navigate", "Forward"},
+	{ActionFollowLink, "Browsing", "Follow link by hint letters or number", "Navigate", "Follow link"},
+	{ActionFollowNewTab, "Browsing", "Open link in a new tab", "Navigate", "Link in tab"},
+	{ActionFollowBg, "Browsing", "Open link in a background tab", "Navigate", "Link in bg"},
+	{ActionEditField, "Browsing", "Edit form field by number", "Navigate", "Edit field"},
+	{ActionEditFirstField, "Browsing", "Edit the first form field", "Navigate", "First field"},
+	{ActionReload, "Browsing", "Reload page", "Navigate", "Reload"},
+	{ActionBookmark, "Browsing", "Bookmark current page", "Tools", "Bookmark"},
+	{ActionReadLater, "Browsing", "Add to read later", "Tools", "Save for later"},
+
+	{ActionFoldToggle, "Comments", "Fold or open the comment at the top", "Navigate", "Toggle fold"},
+	{ActionFoldOpen, "Comments", "Open the comment's replies", "Navigate", "Open fold"},
+	{ActionFoldClose, "Comments", "Fold the comment (again: its parent)", "Navigate", "Close fold"},
+	{ActionFoldOpenAll, "Comments", "Open all comments", "Navigate", "Open all"},
+	{ActionFoldCloseAll, "Comments", "Fold all top-level comments", "Navigate", "Fold all"},
+	{ActionNextComment, "Comments", "Next top-level comment", "Navigate", "Next comment"},
+	{ActionPrevComment, "Comments", "Previous top-level comment", "Navigate", "Prev comment"},
+
+	{ActionNewTab, "Tabs", "New tab", "Tabs", "New tab"},
+	{ActionWindow, "Tabs", "Close tab (window prefix while split)", "Tabs", "Close tab"},
+	{ActionCloseTab, "Tabs", "Close tab", "Tabs", "Close tab"},
+	{ActionUndoClose, "Tabs", "Reopen last closed tab", "Tabs", "Reopen closed tab"},
+	{ActionNextTab, "Tabs", "Next tab", "Tabs", "Next tab"},
+	{ActionPrevTab, "Tabs", "Previous tab", "Tabs", "Prev tab"},
+
+	{ActionHackerNews, "Feeds", "Hacker News", "Feeds", "Hacker News"},
+	{ActionReddit, "Feeds", "Reddit", "Feeds", "Reddit"},
+	{ActionWebSearch, "Feeds", "Search the web", "Feeds", "Search"},
+	{ActionRSS, "Feeds", "RSS feed", "Feeds", "RSS feed"},
+
+	{ActionBookmarks, "Browsing", "List bookmarks", "Tools", "Bookmarks"},
+	{ActionBookmarkManager, "Browsing", "Toggle bookmark manager", "Tools", "Manage marks"},
+	{ActionReadLaterList, "Browsing", "List read later queue", "Tools", "Read later"},
+	{ActionSearch, "Modes", "Search on page (\\v for regex)", "Tools", "Search page"},
+	{ActionSearchNext, "Modes", "Next match", "Tools", "Next match"},
+	{ActionSearchPrev, "Modes", "Previous match", "Tools", "Prev match"},
+	{ActionCommand, "Modes", "Command mode", "Tools", "Command"},
+	{ActionCommandHistory, "Modes", "Browse command history", "Tools", "Cmd history"},
+
+	{ActionHistory, "Browsing", "Toggle history panel", "Views", "History"},
+	{ActionSplitVertical, "Splits", "Split vertical", "Views", "Split vert"},
+	{ActionSplitHorizontal, "Splits", "Split horizontal", "Views", "Split horiz"},
+	{ActionSplitClose, "Splits", "Close split", "Views", "Close split"},
+	{ActionSplitToggle, "Splits", "Focus other pane", "Views", "Other pane"},
+	{ActionThemeCycle, "Views", "Cycle theme", "Views", "Theme cycle"},
+	{ActionLeader, "Modes", "Leader key (shortcut palette)", "Views", "Leader"},
+	{ActionHelp, "Modes", "Show this help", "Views", "Help"},
+	{ActionQuit, "Modes", "Quit", "Views", "Quit"},
+}
+
+// leaderColumns are the leader palette columns, in order.
+var leaderColumns = []struct{ name, icon string }{
+	{"Navigate", "🧭"},
+	{"Tabs", "📑"},
+	{"Feeds", "📡"},
+	{"Tools", "🔧"},
+	{"Views", "👁"},
+}
+
+// Key binding modes, as named in the config file.
+const (
+	keyModeNormal = "normal"
+	keyModeLeader = "leader"
+)
 
-	// History
-	HistoryToggle key.Binding
+// defaultKeys are the built-in bindings per mode. Keys in a sequence are
+// separated by spaces ("g g"); the space bar is "space".
+var defaultKeys = map[string]map[Action][]string{
+	keyModeNormal: {
+		ActionScrollDown:      {"j", "down"},
+		ActionScrollUp:        {"k", "up"},
+		ActionHalfPageDown:    {"ctrl+d"},
+		ActionHalfPageUp:      {"ctrl+u"},
+		ActionGotoTop:         {"g g"},
+		ActionGotoBottom:      {"G"},
+		ActionOpenURL:         {"o"},
+		ActionBack:            {"H"},
+		ActionForward:         {"L"},
+		ActionReload:          {"r"},
+		ActionFollowLink:      {"f"},
+		ActionFollowNewTab:    {"g f"},
+		ActionFollowBg:        {"F"},
+		ActionEditField:       {"i"},
+		ActionEditFirstField:  {"g i"},
+		ActionBookmark:        {"B"},
+		ActionReadLater:       {"R"},
+		ActionHistory:         {"ctrl+h"},
+		ActionFoldToggle:      {"z a"},
+		ActionFoldOpen:        {"z o"},
+		ActionFoldClose:       {"z c"},
+		ActionFoldOpenAll:     {"z R"},
+		ActionFoldCloseAll:    {"z M"},
+		ActionNextComment:     {"] ]"},
+		ActionPrevComment:     {"[ ["},
+		ActionBookmarkManager: {"g b"},
+		ActionNewTab:          {"ctrl+t"},
+		ActionWindow:          {"ctrl+w"},
+		ActionNextTab:         {"g t", "tab"},
+		ActionPrevTab:         {"g T", "shift+tab"},
+		ActionUndoClose:       {"u"},
+		ActionSplitVertical:   {"ctrl+\\"},
+		ActionSplitHorizontal: {"ctrl+_"},
+		ActionSplitClose:      {"ctrl+x"},
+		ActionSplitToggle:     {"ctrl+o"},
+		ActionCommand:         {":"},
+		ActionSearch:          {"/"},
+		ActionSearchNext:      {"n"},
+		ActionSearchPrev:      {"N"},
+		ActionLeader:          {"space"},
+		ActionHelp:            {"?"},
+		ActionQuit:            {"q"},
+	},
+	keyModeLeader: {
+		ActionOpenURL:         {"o"},
+		ActionBack:            {"b"},
+		ActionForward:         {"f"},
+		ActionFollowLink:      {"l"},
+		ActionReload:          {"r"},
+		ActionNewTab:          {"t"},
+		ActionCloseTab:        {"w"},
+		ActionUndoClose:       {"u"},
+		ActionNextTab:         {"n"},
+		ActionPrevTab:         {"p"},
+		ActionHackerNews:      {"h"},
+		ActionReddit:          {"e"},
+		ActionWebSearch:       {"s"},
+		ActionRSS:             {"a"},
+		ActionBookmarks:       {"B"},
+		ActionBookmarkManager: {"m"},
+		ActionReadLaterList:   {"R"},
+		ActionSearch:          {"/"},
+		ActionCommand:         {":"},
+		ActionCommandHistory:  {"c"},
+		ActionHistory:         {"H"},
+		ActionSplitVertical:   {"v"},
+		ActionSplitClose:      {"x"},
+		ActionThemeCycle:      {"T"},
+		ActionHelp:            {"?"},
+	},
+}
+
+// Binding binds key sequences to an action.
+type Binding struct {
+	Action Action
+	Keys   [][]string // each a sequence of keys as reported by tea.KeyMsg.String
+}
+
+// Bindings are the key bindings of one mode, in display order.
+type Bindings []Binding
+
+// KeyMap defines all keybindings for tsurf.
+type KeyMap struct {
+	Normal Bindings
+	Leader Bindings
 }
 
 // DefaultKeyMap returns the default vim-style keybindings.
 func DefaultKeyMap() KeyMap {
-	return KeyMap{
-		ScrollDown: key.NewBinding(
-			key.WithKeys("j", "down"),
-			key.WithHelp("j/down", "scroll down"),
-		),
-		ScrollUp: key.NewBinding(
-			key.WithKeys("k", "up"),
-			key.WithHelp("k/up", "scroll up"),
-		),
-		HalfPageDown: key.NewBinding(
-			key.WithKeys("ctrl+d"),
-			key.WithHelp("Ctrl+d", "half page down"),
-		),
-		HalfPageUp: key.NewBinding(
-			key.WithKeys("ctrl+u"),
-			key.WithHelp("Ctrl+u", "half page up"),
-		),
-		GotoTop: key.NewBinding(
-			key.WithKeys("g"),
-			key.WithHelp("gg", "go to top"),
-		),
-		GotoBottom: key.NewBinding(
-			key.WithKeys("G"),
-			key.WithHelp("G", "go to bottom"),
-		),
-		OpenURL: key.NewBinding(
-			key.WithKeys("o"),
-			key.WithHelp("o", "open URL"),
-		),
-		Back: key.NewBinding(
-			key.WithKeys("H"),
-			key.WithHelp("H", "go back"),
-		),
-		Forward: key.NewBinding(
-			key.WithKeys("L"),
-			key.WithHelp("L", "go forward"),
-		),
-		Reload: key.NewBinding(
-			key.WithKeys("r"),
-			key.WithHelp("r", "reload page"),
-		),
-		FollowLink: key.NewBinding(
-			key.WithKeys("f"),
-			key.WithHelp("f", "follow link"),
-		),
-		NewTab: key.NewBinding(
-			key.WithKeys("ctrl+t"),
-			key.WithHelp("Ctrl+t", "new tab"),
-		),
-		CloseTab: key.NewBinding(
-			key.WithKeys("ctrl+w"),
-			key.WithHelp("Ctrl+w", "close tab"),
-		),
-		NextTab: key.NewBinding(
-			key.WithKeys("tab"),
-			key.WithHelp("gt/Tab", "next tab"),
-		),
-		PrevTab: key.NewBinding(
-			key.WithKeys("shift+tab"),
-			key.WithHelp("gT/S-Tab", "prev tab"),
-		),
-		CommandMode: key.NewBinding(
-			key.WithKeys(":"),
-			key.WithHelp(":", "command mode"),
-		),
-		SearchMode: key.NewBinding(
-			key.WithKeys("/"),
-			key.WithHelp("/", "search"),
-		),
-		Quit: key.NewBinding(
-			key.WithKeys("q", "ctrl+c"),
-			key.WithHelp("q", "quit"),
-		),
-		Help: key.NewBinding(
-			key.WithKeys("?"),
-			key.WithHelp("?", "help"),
-		),
-		Bookmark: key.NewBinding(
-			key.WithKeys("B"),
-			key.WithHelp("B", "bookmark page"),
-		),
-		ReadLater: key.NewBinding(
-			key.WithKeys("R"),
-			key.WithHelp("R", "read later"),
-		),
-		SplitVertical: key.NewBinding(
-			key.WithKeys("ctrl+\\"),
-			key.WithHelp("Ctrl+\\", "split vertical"),
-		),
-		SplitHorizontal: key.NewBinding(
-			key.WithKeys("ctrl+_"),
-			key.WithHelp("Ctrl+_", "split horizontal"),
-		),
-		SplitClose: key.NewBinding(
-			key.WithKeys("ctrl+x"),
-			key.WithHelp("Ctrl+x", "close split"),
-		),
-		SplitToggle: key.NewBinding(
-			key.WithKeys("ctrl+o"),
-			key.WithHelp("Ctrl+o", "toggle split"),
-		),
-		HistoryToggle: key.NewBinding(
-			key.WithKeys("ctrl+h"),
-			key.WithHelp("Ctrl+h", "toggle history"),
-		),
+	km, _ := NewKeyMap(nil)
+	return km
+}
+
+// NewKeyMap returns the default keybindings with the config file's overrides
+// applied. Overrides map a mode ("normal" or "leader") to actions and the
+// key sequences that trigger them; an action listed there loses its default
+// keys, and an empty list unbinds it. Unknown names and conflicting bindings
+// are reported in the returned error; everything else still takes effect.
+func NewKeyMap(overrides map[string]map[string][]string) (KeyMap, error) {
+	var errs []error
+	for mode, bound := range overrides {
+		if _, ok := defaultKeys[mode]; !ok {
+			errs = append(errs, fmt.Errorf("keys: unknown mode %q", mode))
+			continue
+		}
+		for name := range bound {
+			if !knownAction(Action(name)) {
+				errs = append(errs, fmt.Errorf("keys.%s: unknown action %q", mode, name))
+			}
+		}
+	}
+
+	build := func(mode string) Bindings {
+		var bs Bindings
+		for _, info := range actions {
+			keys := defaultKeys[mode][info.action]
+			if custom, ok := overrides[mode][string(info.action)]; ok {
+				keys = custom
+			}
+			if len(keys) == 0 {
+				continue
+			}
+			b := Binding{Action: info.action}
+			for _, k := range keys {
+				seq := parseKeySequence(k)
+				if len(seq) == 0 {
+					errs = append(errs, fmt.Errorf("keys.%s.%s: empty key", mode, info.action))
+					continue
+				}
+				b.Keys = append(b.Keys, seq)
+			}
+			bs = append(bs, b)
+		}
+		errs = append(errs, bs.conflicts(mode)...)
+		return bs
+	}
+
+	km := KeyMap{
+		Normal: build(keyModeNormal),
+		Leader: build(keyModeLeader),
+	}
+	return km, errors.Join(errs...)
+}
+
+// knownAction reports whether a is a bindable action.
+func knownAction(a Action) bool {
+	return slices.ContainsFunc(actions, func(info actionInfo) bool { return info.action == a })
+}
+
+// parseKeySequence splits a configured sequence like "g g" or "space h" into
+// key names as tea.KeyMsg.String reports them.
+func parseKeySequence(s string) []string {
+	seq := strings.Fields(s)
+	for i, k := range seq {
+		if k == "space" {
+			seq[i] = " "
+		}
+	}
+	return seq
+}
+
+// Match looks up the keys typed so far. It returns the action they trigger,
+// or pending if they are the start of a longer sequence.
+func (bs Bindings) Match(typed []string) (action Action, pending bool) {
+	for _, b := range bs {
+		for _, seq := range b.Keys {
+			switch {
+			case slices.Equal(seq, typed):
+				return b.Action, false
+			case len(seq) > len(typed) && slices.Equal(seq[:len(typed)], typed):
+				pending = true
+			}
+		}
+	}
+	return "", pending
+}
+
+// Keys returns the key sequences bound to an action, if any.
+func (bs Bindings) Keys(a Action) [][]string {
+	for _, b := range bs {
+		if b.Action == a {
+			return b.Keys
+		}
+	}
+	return nil
+}
+
+// conflicts reports sequences bound to two actions, and sequences that can
+// never fire because a shorter one bound to a prefix of them fires first.
+func (bs Bindings) conflicts(mode string) []error {
+	type bound struct {
+		seq    []string
+		action Action
+	}
+	var all []bound
+	for _, b := range bs {
+		for _, seq := range b.Keys {
+			all = append(all, bound{seq, b.Action})
+		}
+	}
+
+	var errs []error
+	for i, a := range all {
+		for _, b := range all[i+1:] {
+			short, long := a, b
+			if len(short.seq) > len(long.seq) {
+				short, long = long, short
+			}
+			if !slices.Equal(long.seq[:len(short.seq)], short.seq) {
+				continue
+			}
+			switch {
+			case len(short.seq) < len(long.seq):
+				errs = append(errs, fmt.Errorf("keys.%s: %q (%s) hides %q (%s)",
+					mode, formatKeys(short.seq), short.action, formatKeys(long.seq), long.action))
+			case a.action != b.action:
+				errs = append(errs, fmt.Errorf("keys.%s: %q is bound to both %s and %s",
+					mode, formatKeys(a.seq), a.action, b.action))
+			}
+		}
+	}
+	return errs
+}
+
+// keyNames are display names for keys that aren't shown as typed.
+var keyNames = map[string]string{
+	" ":         "Space",
+	"shift+tab": "S-Tab",
+}
+
+// formatKey returns the display name of a key: "Ctrl+d", "Down", "Space".
+func formatKey(k string) string {
+	if name, ok := keyNames[k]; ok {
+		return name
+	}
+	if utf8.RuneCountInString(k) == 1 {
+		return k
+	}
+	if mod, rest, ok := strings.Cut(k, "+"); ok && rest != "" {
+		return strings.ToUpper(mod[:1]) + mod[1:] + "+" + rest
+	}
+	return strings.ToUpper(k[:1]) + k[1:]
+}
+
+// formatKeys returns the display form of a key sequence: "gg" for plain
+// characters, "Ctrl+w c" when any key is named.
+func formatKeys(seq []string) string {
+	plain := true
+	names := make([]string, len(seq))
+	for i, k := range seq {
+		names[i] = formatKey(k)
+		if names[i] != k {
+			plain = false
+		}
+	}
+	if plain {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names, " ")
+}
+
+// formatBinding returns the display form of all sequences bound to an action.
+func formatBinding(keys [][]string) string {
+	parts := make([]string, len(keys))
+	for i, seq := range keys {
+		parts[i] = formatKeys(seq)
+	}
+	return strings.Join(parts, " / ")
+}
+
+// helpEntry is one line of the help screen.
+type helpEntry struct{ k, d string }
+
+// helpSection is a titled block of the help screen.
+type helpSection struct {
+	name    string
+	entries []helpEntry
+}
+
+// helpGroups are the help screen sections for key bindings, in order.
+var helpGroups = []string{"Navigation", "Browsing", "Comments", "Tabs", "Splits", "Feeds", "Views", "Modes"}
+
+// helpSections returns the help screen sections for the effective bindings:
+// one per group of normal mode actions, then the leader chords.
+func (km KeyMap) helpSections() []helpSection {
+	var sections []helpSection
+	for _, group := range helpGroups {
+		section := helpSection{name: group}
+		for _, info := range actions {
+			if keys := km.Normal.Keys(info.action); info.group == group && len(keys) > 0 {
+				section.entries = append(section.entries, helpEntry{formatBinding(keys), info.desc})
+			}
+		}
+		// Window commands follow the Ctrl+w prefix and aren't remappable.
+		if keys := km.Normal.Keys(ActionWindow); group == "Splits" && len(keys) > 0 {
+			prefix := formatKeys(keys[0])
+			section.entries = append(section.entries,
+				helpEntry{prefix + " h/j/k/l", "Focus pane left/down/up/right"},
+				helpEntry{prefix + " w", "Focus other pane"},
+				helpEntry{prefix + " +/-/=", "Grow / shrink / equalize pane"},
+				helpEntry{prefix + " c", "Close split"},
+			)
+		}
+		if len(section.entries) > 0 {
+			sections = append(sections, section)
+		}
+	}
+
+	leader := km.Normal.Keys(ActionLeader)
+	if len(leader) == 0 {
+		return sections
+	}
+	prefix := formatKeys(leader[0])
+	section := helpSection{name: fmt.Sprintf("Leader Key (%s+...)", prefix)}
+	for _, info := range actions {
+		for _, seq := range km.Leader.Keys(info.action) {
+			section.entries = append(section.entries, helpEntry{prefix + " " + formatKeys(seq), info.short})
+		}
+	}
+	return append(sections, section)
+}
+
+// leaderGroups returns the leader palette columns for the effective bindings.
+func (km KeyMap) leaderGroups() []ui.LeaderGroup {
+	var groups []ui.LeaderGroup
+	for _, col := range leaderColumns {
+		group := ui.LeaderGroup{Name: col.name, Icon: col.icon}
+		for _, info := range actions {
+			if keys := km.Leader.Keys(info.action); info.column == col.name && len(keys) > 0 {
+				group.Bindings = append(group.Bindings, ui.LeaderBinding{Key: formatBinding(keys), Desc: info.short})
+			}
+		}
+		if len(group.Bindings) > 0 {
+			groups = append(groups, group)
+		}
 	}
+	return groups
 }
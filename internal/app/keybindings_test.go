@@ -0,0 +1,80 @@
+package app
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultKeyMapHasNoConflicts(t *testing.T) {
+	if _, err := NewKeyMap(nil); err != nil {
+		t.Errorf("default key map: %v", err)
+	}
+}
+
+func TestKeyMapMatch(t *testing.T) {
+	km, err := NewKeyMap(map[string]map[string][]string{
+		"normal": {"scroll_down": {"ctrl+n"}, "goto_top": {"space g"}, "leader": {"space space"}},
+		"leader": {"hacker_news": {}},
+	})
+	if err != nil {
+		t.Fatalf("NewKeyMap: %v", err)
+	}
+
+	tests := []struct {
+		typed   []string
+		action  Action
+		pending bool
+	}{
+		{[]string{"ctrl+n"}, ActionScrollDown, false},
+		{[]string{"j"}, "", false}, // default replaced
+		{[]string{"g"}, "", true},
+		{[]string{"g", "t"}, ActionNextTab, false},
+		{[]string{" "}, "", true},
+		{[]string{" ", "g"}, ActionGotoTop, false},
+	}
+	for _, tt := range tests {
+		action, pending := km.Normal.Match(tt.typed)
+		if action != tt.action || pending != tt.pending {
+			t.Errorf("Match(%q) = %q, %v; want %q, %v", tt.typed, action, pending, tt.action, tt.pending)
+		}
+	}
+
+	if action, _ := km.Leader.Match([]string{"h"}); action != "" {
+		t.Errorf("unbound leader h still triggers %q", action)
+	}
+}
+
+func TestKeyMapConflicts(t *testing.T) {
+	_, err := NewKeyMap(map[string]map[string][]string{
+		"normal": {"reload": {"j"}, "back": {"g"}, "bogus": {"x"}},
+		"insert": {"quit": {"q"}},
+	})
+	if err == nil {
+		t.Fatal("expected conflicts")
+	}
+	for _, want := range []string{
+		`"j" is bound to both scroll_down and reload`,
+		`"g" (back) hides "gg" (goto_top)`,
+		`unknown action "bogus"`,
+		`unknown mode "insert"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
+
+func TestFormatKeys(t *testing.T) {
+	tests := map[string]string{
+		"g g":       "gg",
+		"ctrl+w c":  "Ctrl+w c",
+		"space h":   "Space h",
+		"shift+tab": "S-Tab",
+		"down":      "Down",
+	}
+	for in, want := range tests {
+		if got := formatKeys(parseKeySequence(in)); got != want {
+			t.Errorf("formatKeys(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
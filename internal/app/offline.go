@@ -0,0 +1,95 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vidyasagar/tsurf/internal/browser"
+	"github.com/vidyasagar/tsurf/internal/theme"
+)
+
+// Offline mode lives in browser.SharedTransport, so pages and every feed
+// client are served from the HTTP cache without touching the network.
+
+// SetOffline switches offline mode on or off.
+func (m *Model) SetOffline(offline bool) {
+	browser.SharedTransport.SetOffline(offline)
+	m.syncStatusBar()
+}
+
+// toggleOffline handles :offline [on|off].
+func (m *Model) toggleOffline(arg string) {
+	offline := !browser.SharedTransport.Offline()
+	switch arg {
+	case "on":
+		offline = true
+	case "off":
+		offline = false
+	case "":
+	default:
+		m.statusBar.SetMessage("Usage: :offline [on|off]")
+		return
+	}
+
+	if offline && browser.SharedTransport.Cache() == nil {
+		m.statusBar.SetMessage("Offline mode unavailable (no cache)")
+		return
+	}
+
+	m.SetOffline(offline)
+	if offline {
+		m.statusBar.SetMessage("Offline: serving pages from the local cache")
+	} else {
+		m.statusBar.SetMessage("Online")
+	}
+}
+
+// cachedAt returns when the page for url was stored, if in offline mode.
+func (m *Model) cachedAt(url string) time.Time {
+	if !browser.SharedTransport.Offline() {
+		return time.Time{}
+	}
+	if cache := browser.SharedTransport.Cache(); cache != nil {
+		if t, ok := cache.StoredAt(url); ok {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// offlineLabel returns the status bar offline indicator for a tab.
+func (m *Model) offlineLabel(ts *tabState) string {
+	if !browser.SharedTransport.Offline() {
+		return ""
+	}
+	if ts == nil || ts.cachedAt.IsZero() {
+		return "OFFLINE"
+	}
+	return fmt.Sprintf("OFFLINE (cached %s)", timeAgo(ts.cachedAt))
+}
+
+// offlineErrorContent renders the error page for a fetch that failed because
+// nothing is stored locally. Returns false for other errors.
+func offlineErrorContent(url string, err error) (string, bool) {
+	if !errors.Is(err, browser.ErrNotCached) {
+		return "", false
+	}
+
+	errStyle := lipgloss.NewStyle().
+		Foreground(theme.Current.Error).
+		Bold(true).
+		Padding(2, 4)
+	detailStyle := lipgloss.NewStyle().
+		Foreground(theme.Current.TextDim).
+		Padding(0, 4)
+
+	detail := "This page has not been stored locally."
+	if url != "" {
+		detail = fmt.Sprintf("URL: %s\n\n%s", url, detail)
+	}
+	detail += "\nUse :offline to go back online."
+
+	return errStyle.Render("Not available offline") + "\n\n" + detailStyle.Render(detail), true
+}
@@ -0,0 +1,248 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vidyasagar/tsurf/internal/ui"
+)
+
+// Split panes are bound to tabs: m.panes holds the tab ID shown in each pane,
+// and the focused pane always shows the tab bar's active tab. Switching tabs
+// therefore changes what the focused pane displays, while the other pane keeps
+// its own tab (and with it its own history, viewport and link numbering).
+
+// splitWindow splits the view. The new pane opens a copy of the current page
+// in a new tab and receives focus. If already split, only the direction changes.
+func (m *Model) splitWindow(dir ui.SplitDirection) {
+	if m.splitPane.IsSplit() {
+		m.splitPane.Direction = dir
+		m.layout()
+		return
+	}
+
+	cur := m.tabBar.ActiveTab()
+	curTS := m.activeTabState()
+	if cur == nil || curTS == nil {
+		return
+	}
+	curID, title, url := cur.ID, cur.Title, cur.URL
+
+	m.tabBar.NewTab()
+	tab := m.tabBar.ActiveTab()
+	ts := &tabState{
+		viewport:  ui.NewPageViewport(),
+		history:   curTS.history.Clone(),
+		article:   curTS.article,
+		pageWidth: curTS.pageWidth,
+		feedLinks: curTS.feedLinks,
+		feedData:  curTS.feedData,
+		listPage:  curTS.listPage,
+	}
+	if curTS.thread != nil {
+		ts.thread = curTS.thread.clone()
+	}
+	if curTS.page != nil {
+		// Values typed into one pane's fields stay out of the other.
+		ts.page = curTS.page.Clone()
+	}
+	m.tabStates[tab.ID] = ts
+	m.tabBar.SetActiveTitle(title)
+	m.tabBar.SetActiveURL(url)
+
+	m.panes = [2]int{curID, tab.ID}
+	m.splitPane.Split(dir)
+	m.splitPane.Active = 1
+	m.layout()
+
+	switch {
+	case curTS.page != nil && curTS.page.Edited():
+		ts.viewport.SetContent(ts.page.Content)
+	case curTS.viewport.HasContent():
+		ts.viewport.SetContent(curTS.viewport.Content())
+	}
+	m.syncTabUI()
+}
+
+// unsplitWindow closes the split, keeping the focused pane's tab active.
+// The other pane's tab stays open in the tab bar.
+func (m *Model) unsplitWindow() {
+	m.splitPane.Unsplit()
+	m.pendingWindowCmd = false
+	m.layout()
+	m.syncTabUI()
+}
+
+// focusPane moves focus to the given pane (0 or 1).
+func (m *Model) focusPane(idx int) {
+	if !m.splitPane.IsSplit() || idx == m.splitPane.Active {
+		return
+	}
+	m.splitPane.Active = idx
+	m.tabBar.SetActive(m.panes[idx])
+	m.syncTabUI()
+}
+
+// syncPanes keeps the focused pane bound to the active tab and collapses the
+// split if one of the pane tabs was closed.
+func (m *Model) syncPanes() {
+	if !m.splitPane.IsSplit() {
+		return
+	}
+
+	active := m.tabBar.ActiveTab()
+	focused := m.splitPane.Active
+	other := 1 - focused
+
+	switch {
+	case active == nil || m.tabBar.Tab(m.panes[other]) == nil:
+		m.splitPane.Unsplit()
+	case active.ID == m.panes[other]:
+		// Switched to the tab shown in the other pane: move focus there,
+		// unless the focused pane's tab is gone.
+		if m.tabBar.Tab(m.panes[focused]) == nil {
+			m.splitPane.Unsplit()
+		} else {
+			m.splitPane.Active = other
+		}
+	default:
+		m.panes[focused] = active.ID
+	}
+
+	m.layout()
+}
+
+// isActiveTab reports whether the tab is the active (focused) tab.
+func (m *Model) isActiveTab(tabID int) bool {
+	tab := m.tabBar.ActiveTab()
+	return tab != nil && tab.ID == tabID
+}
+
+// pagesView renders the page area: a single viewport, or both split panes.
+func (m *Model) pagesView() string {
+	if !m.splitPane.IsSplit() {
+		if ts := m.activeTabState(); ts != nil {
+			return ts.viewport.View()
+		}
+		return ""
+	}
+
+	var views [2]string
+	for i, id := range m.panes {
+		if ts, ok := m.tabStates[id]; ok {
+			views[i] = ts.viewport.View()
+		}
+	}
+	return m.splitPane.RenderSplit(views[0], views[1])
+}
+
+// layoutPanes sizes the viewports of the two split panes.
+func (m *Model) layoutPanes() {
+	if !m.splitPane.IsSplit() {
+		return
+	}
+	if ts, ok := m.tabStates[m.panes[0]]; ok {
+		ts.viewport.SetSize(m.splitPane.FirstPaneDimensions())
+	}
+	if ts, ok := m.tabStates[m.panes[1]]; ok {
+		ts.viewport.SetSize(m.splitPane.SecondPaneDimensions())
+	}
+}
+
+// handleWindowCommand processes the key following Ctrl+w while split.
+func (m Model) handleWindowCommand(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.pendingWindowCmd = false
+	m.statusBar.SetMessage("")
+
+	vertical := m.splitPane.Direction == ui.SplitVertical
+
+	switch msg.String() {
+	case "h", "left":
+		if vertical {
+			m.focusPane(0)
+		}
+	case "l", "right":
+		if vertical {
+			m.focusPane(1)
+		}
+	case "k", "up":
+		if !vertical {
+			m.focusPane(0)
+		}
+	case "j", "down":
+		if !vertical {
+			m.focusPane(1)
+		}
+	case "w", "p", "ctrl+w":
+		m.focusPane(1 - m.splitPane.Active)
+	case "c", "q", "o":
+		m.unsplitWindow()
+	case "v":
+		m.splitWindow(ui.SplitVertical)
+	case "s":
+		m.splitWindow(ui.SplitHorizontal)
+	case "+", ">":
+		m.resizeSplit(fmt.Sprintf("+%d", resizeStep))
+	case "-", "<":
+		m.resizeSplit(fmt.Sprintf("-%d", resizeStep))
+	case "=":
+		m.resizeSplit("50")
+	}
+
+	return m, nil
+}
+
+// resizeStep is the percentage applied by Ctrl+w +/- and Ctrl+w </>.
+const resizeStep = 5
+
+// resizeSplit applies a :resize argument to the focused pane. Accepted forms:
+// "0.3" (fraction), "30" or "30%" (percent), and "+10"/"-10" (relative percent).
+func (m *Model) resizeSplit(arg string) {
+	if !m.splitPane.IsSplit() {
+		m.statusBar.SetMessage("No split to resize")
+		return
+	}
+
+	// Sizes apply to the focused pane, so work in terms of its share.
+	share := m.splitPane.Ratio
+	if m.splitPane.Active == 1 {
+		share = 1 - share
+	}
+
+	newShare, ok := parseRatio(arg, share)
+	if !ok {
+		m.statusBar.SetMessage(fmt.Sprintf("Invalid size: %s (use 0.3, 30%%, +10 or -10)", arg))
+		return
+	}
+
+	if m.splitPane.Active == 1 {
+		newShare = 1 - newShare
+	}
+	m.splitPane.SetRatio(newShare)
+	m.layout()
+	m.statusBar.SetMessage(fmt.Sprintf("Split: %d%% / %d%%",
+		int(m.splitPane.Ratio*100+0.5), 100-int(m.splitPane.Ratio*100+0.5)))
+}
+
+// parseRatio parses a :resize argument relative to the current ratio.
+func parseRatio(arg string, current float64) (float64, bool) {
+	arg = strings.TrimSpace(arg)
+	relative := strings.HasPrefix(arg, "+") || strings.HasPrefix(arg, "-")
+	n, err := strconv.ParseFloat(strings.TrimSuffix(arg, "%"), 64)
+	if err != nil {
+		return 0, false
+	}
+
+	switch {
+	case relative:
+		return current + n/100, true
+	case n > 0 && n < 1 && !strings.HasSuffix(arg, "%"):
+		return n, true
+	case n >= 1 && n < 100:
+		return n / 100, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vidyasagar/tsurf/internal/browser"
+	"github.com/vidyasagar/tsurf/internal/feeds"
+	"github.com/vidyasagar/tsurf/internal/storage"
+	"github.com/vidyasagar/tsurf/internal/theme"
+)
+
+// loadPlugins creates the site handlers of the plugins in the config. Bad
+// entries are skipped and reported together.
+func loadPlugins(configs []storage.PluginConfig) ([]feeds.SiteHandler, error) {
+	var plugins []feeds.SiteHandler
+	var errs []error
+	for _, c := range configs {
+		var timeout time.Duration
+		if c.Timeout != "" {
+			d, err := time.ParseDuration(c.Timeout)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("plugin %s: bad timeout %q", c.Name, c.Timeout))
+				continue
+			}
+			timeout = d
+		}
+		command := append([]string(nil), c.Command...)
+		if len(command) > 0 {
+			command[0] = expandHome(command[0])
+		}
+		p, err := feeds.NewPluginSite(c.Name, c.Match, command, timeout)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		plugins = append(plugins, p)
+	}
+	return plugins, errors.Join(errs...)
+}
+
+// showPlugins renders the list of site handlers in the active tab.
+func (m *Model) showPlugins() {
+	ts := m.activeTabState()
+	if ts == nil {
+		return
+	}
+
+	sites := m.sites
+	ts.showList(m.renderWidth(ts), func(int) (string, []browser.Link) {
+		return feeds.RenderSites(sites), nil
+	})
+	m.tabBar.SetActiveTitle("Plugins")
+	m.statusBar.SetTitle("Plugins")
+	m.statusBar.SetLinkCount(0)
+}
+
+// pluginErrorContent renders the error page for a failed plugin run, with
+// the end of what it wrote to stderr. Returns false for other errors.
+func pluginErrorContent(err error) (string, bool) {
+	var perr *feeds.PluginError
+	if !errors.As(err, &perr) {
+		return "", false
+	}
+
+	errStyle := lipgloss.NewStyle().
+		Foreground(theme.Current.Error).
+		Bold(true).
+		Padding(2, 4)
+	detailStyle := lipgloss.NewStyle().
+		Foreground(theme.Current.TextDim).
+		Padding(0, 4)
+
+	detail := fmt.Sprintf("Error: %s", perr.Err)
+	if perr.Stderr != "" {
+		detail += "\n\n" + perr.Stderr
+	}
+	detail += "\n\nSee :plugins for the configured command."
+
+	title := fmt.Sprintf("Plugin %s failed", perr.Plugin)
+	return errStyle.Render(title) + "\n\n" + detailStyle.Render(strings.TrimRight(detail, "\n")), true
+}
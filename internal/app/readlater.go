@@ -0,0 +1,71 @@
+package app
+
+import "fmt"
+
+// saveReadLater queues the tab's page (R), with a snapshot of its article so
+// it can be read offline.
+func (m *Model) saveReadLater(ts *tabState) {
+	tab := m.tabBar.ActiveTab()
+	if tab == nil || tab.URL == "" {
+		m.statusBar.SetMessage("No page to save")
+		return
+	}
+	if !m.readLater.Add(tab.URL, tab.Title) {
+		m.statusBar.SetMessage("Already in read later")
+		return
+	}
+
+	if ts.article != nil {
+		m.readLater.SaveSnapshot(tab.URL, ts.article)
+		m.statusBar.SetMessage(fmt.Sprintf("Added to read later (saved offline): %s", tab.Title))
+	} else {
+		m.statusBar.SetMessage(fmt.Sprintf("Added to read later: %s", tab.Title))
+	}
+	m.startReading(ts, tab.URL)
+}
+
+// startReading resumes a read-later page where the reader left it, and
+// tracks the position from then on. Other pages are not tracked.
+func (m *Model) startReading(ts *tabState, url string) {
+	ts.readingURL = ""
+	if m.readLater == nil {
+		return
+	}
+	progress, ok := m.readLater.Progress(url)
+	if !ok {
+		return
+	}
+
+	ts.readingURL = url
+	ts.readProgress = progress
+	if progress > 0 && progress < 100 && ts.viewport.YOffset() == 0 {
+		ts.viewport.SetScrollPercent(float64(progress) / 100)
+	}
+	ts.readOffset = ts.viewport.YOffset()
+}
+
+// trackReading saves the reading position of the active read-later page
+// whenever the reader scrolls it by a percent. Scrolling to the end marks
+// the page read. A page that fits on screen cannot be scrolled, so opening
+// one does not count as reading it.
+func (m *Model) trackReading() {
+	ts := m.activeTabState()
+	if m.readLater == nil || ts == nil || ts.readingURL == "" || ts.loading || ts.article == nil {
+		return
+	}
+	offset := ts.viewport.YOffset()
+	if offset == ts.readOffset {
+		return
+	}
+	ts.readOffset = offset
+
+	progress := int(ts.viewport.ScrollPercent() * 100)
+	if progress == ts.readProgress {
+		return
+	}
+	if progress >= 100 && ts.readProgress < 100 {
+		m.statusBar.SetMessage("Finished: marked as read")
+	}
+	ts.readProgress = progress
+	m.readLater.SetProgress(ts.readingURL, progress)
+}
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vidyasagar/tsurf/internal/browser"
+)
+
+// rerenderDelay is how long the layout must stay unchanged before pages are
+// re-rendered at their new width, so dragging a terminal edge renders once.
+const rerenderDelay = 150 * time.Millisecond
+
+// pageKey identifies a rendered page in the page cache. Word-wrap is baked
+// into the rendered text, so the width is part of the key.
+type pageKey struct {
+	url   string
+	width int
+}
+
+// cachedPage is a page cache entry. The article is kept so the page can be
+// re-rendered at another width after a cache hit.
+type cachedPage struct {
+	article *browser.Article
+	page    *browser.RenderedPage
+}
+
+// rerenderTickMsg ends a re-render debounce period.
+type rerenderTickMsg struct {
+	seq int
+}
+
+// pageRenderedMsg carries a page re-rendered from a tab's article.
+type pageRenderedMsg struct {
+	tabID   int
+	article *browser.Article
+	page    *browser.RenderedPage
+	width   int
+}
+
+// renderWidth returns the width to render a tab's page at: the width of the
+// pane showing it, or of the terminal before the first layout.
+func (m *Model) renderWidth(ts *tabState) int {
+	if w := ts.viewport.Width(); w > 0 {
+		return w
+	}
+	if m.width > 0 {
+		return m.width
+	}
+	return 80
+}
+
+// scheduleRerender starts (or restarts) the debounce before re-rendering
+// pages whose width no longer matches their pane.
+func (m *Model) scheduleRerender() tea.Cmd {
+	m.rerenderSeq++
+	seq := m.rerenderSeq
+	return tea.Tick(rerenderDelay, func(time.Time) tea.Msg {
+		return rerenderTickMsg{seq: seq}
+	})
+}
+
+// rerenderTabs re-renders tab pages from their articles in the background.
+// Unless force is set, only pages wrapped at a stale width are rendered.
+func (m *Model) rerenderTabs(force bool) tea.Cmd {
+	var cmds []tea.Cmd
+	for id, ts := range m.tabStates {
+		// Threads render quickly enough to redo in place.
+		if tv := ts.thread; tv != nil && !ts.loading && (force || tv.width != m.renderWidth(ts)) {
+			m.showThread(ts, m.renderWidth(ts), ts.viewport.YOffset())
+			continue
+		}
+		if ts.article == nil || ts.loading {
+			continue
+		}
+		width := m.renderWidth(ts)
+		if !force && width == ts.pageWidth {
+			continue
+		}
+		url := ""
+		if tab := m.tabBar.Tab(id); tab != nil {
+			url = tab.URL
+		}
+		cmds = append(cmds, m.renderArticle(id, url, ts.article, width))
+	}
+	return tea.Batch(cmds...)
+}
+
+// renderArticle renders article at width off the UI goroutine and caches the
+// result under url.
+func (m *Model) renderArticle(tabID int, url string, article *browser.Article, width int) tea.Cmd {
+	pageCache := m.pageCache
+	return func() tea.Msg {
+		page := browser.Render(article, width)
+		if pageCache != nil && url != "" {
+			pageCache.Add(pageKey{url: url, width: width}, &cachedPage{article: article, page: page})
+		}
+		return pageRenderedMsg{tabID: tabID, article: article, page: page, width: width}
+	}
+}
+
+// handlePageRendered swaps in a re-rendered page, keeping the reader at the
+// same relative position. Results for a tab that has since moved on to
+// another page are dropped.
+func (m *Model) handlePageRendered(msg pageRenderedMsg) {
+	ts, ok := m.tabStates[msg.tabID]
+	if !ok || ts.loading || ts.article != msg.article {
+		return
+	}
+
+	oldLines := strings.Count(ts.viewport.Content(), "\n") + 1
+	offset := ts.viewport.YOffset()
+
+	// Keep whatever has been typed into the page's fields, in the tab's own
+	// copy: the re-rendered page is in the cache as it came.
+	page := msg.page.Clone()
+	if ts.page != nil && ts.page.Edited() {
+		page.Forms = ts.page.Forms
+		page.RefreshForms()
+	}
+	ts.page = page
+	ts.pageWidth = msg.width
+	ts.viewport.SetContent(page.Content)
+
+	newLines := strings.Count(page.Content, "\n") + 1
+	ts.viewport.SetYOffset(offset * newLines / oldLines)
+}
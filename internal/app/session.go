@@ -0,0 +1,223 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vidyasagar/tsurf/internal/browser"
+	"github.com/vidyasagar/tsurf/internal/storage"
+	"github.com/vidyasagar/tsurf/internal/ui"
+)
+
+// quit saves the current session, so it can be restored on the next launch,
+// and exits.
+func (m *Model) quit() tea.Cmd {
+	if m.sessions != nil {
+		m.sessions.Save(m.snapshotSession(storage.LastSession))
+	}
+	return tea.Quit
+}
+
+// snapshotSession captures the open tabs and split layout.
+func (m *Model) snapshotSession(name string) storage.Session {
+	s := storage.Session{Name: name}
+
+	index := make(map[int]int) // tab ID -> index in s.Tabs
+	for i, tab := range m.tabBar.Tabs() {
+		st := storage.SessionTab{URL: tab.URL, Title: tab.Title, HistoryPos: -1}
+		if ts, ok := m.tabStates[tab.ID]; ok {
+			st.History = ts.history.Entries()
+			st.HistoryPos = ts.history.Position()
+			st.Scroll = ts.viewport.YOffset()
+			if ts.pendingScroll > 0 {
+				st.Scroll = ts.pendingScroll // page not loaded yet
+			}
+		}
+		index[tab.ID] = i
+		s.Tabs = append(s.Tabs, st)
+	}
+	s.ActiveTab = m.tabBar.Active()
+
+	if m.splitPane.IsSplit() {
+		first, ok1 := index[m.panes[0]]
+		second, ok2 := index[m.panes[1]]
+		if ok1 && ok2 {
+			s.Split = storage.SessionSplit{
+				Direction: int(m.splitPane.Direction),
+				Ratio:     m.splitPane.Ratio,
+				Panes:     [2]int{first, second},
+				Active:    m.splitPane.Active,
+			}
+		}
+	}
+
+	return s
+}
+
+// restoreSession replaces the open tabs with those of a saved session and
+// reloads each tab's current page.
+func (m *Model) restoreSession(s *storage.Session) tea.Cmd {
+	if len(s.Tabs) == 0 {
+		return nil
+	}
+
+	m.unsplitWindow()
+
+	// Open the restored tabs after the existing ones, then close the old ones.
+	var oldIDs []int
+	for _, tab := range m.tabBar.Tabs() {
+		oldIDs = append(oldIDs, tab.ID)
+	}
+	m.tabBar.SetActive(oldIDs[len(oldIDs)-1])
+
+	ids := make([]int, len(s.Tabs))
+	for i, st := range s.Tabs {
+		m.tabBar.NewTab()
+		tab := m.tabBar.ActiveTab()
+		ids[i] = tab.ID
+
+		title := st.Title
+		if title == "" {
+			title = "New Tab"
+		}
+		m.tabBar.SetActiveTitle(title)
+		m.tabBar.SetActiveURL(st.URL)
+
+		m.tabStates[tab.ID] = &tabState{
+			viewport:      ui.NewPageViewport(),
+			history:       browser.NewHistoryFrom(st.History, st.HistoryPos),
+			pendingScroll: st.Scroll,
+		}
+	}
+
+	for _, id := range oldIDs {
+		for i, tab := range m.tabBar.Tabs() {
+			if tab.ID == id {
+				m.tabBar.CloseTab(i)
+				break
+			}
+		}
+		if ts, ok := m.tabStates[id]; ok {
+			if ts.cancelFunc != nil {
+				ts.cancelFunc()
+			}
+			delete(m.tabStates, id)
+		}
+	}
+
+	active := s.ActiveTab
+	if active < 0 || active >= len(ids) {
+		active = 0
+	}
+	m.tabBar.SetActive(ids[active])
+
+	sp := s.Split
+	if sp.Direction != int(ui.SplitNone) && validPaneIndexes(sp.Panes, len(ids)) {
+		m.panes = [2]int{ids[sp.Panes[0]], ids[sp.Panes[1]]}
+		m.splitPane.Split(ui.SplitDirection(sp.Direction))
+		m.splitPane.SetRatio(sp.Ratio)
+		if sp.Active == 1 {
+			m.splitPane.Active = 1
+		}
+		m.tabBar.SetActive(m.panes[m.splitPane.Active])
+	}
+
+	m.layout()
+	m.syncTabUI()
+
+	var cmds []tea.Cmd
+	for i, st := range s.Tabs {
+		if st.URL != "" {
+			cmds = append(cmds, m.loadPageInTab(ids[i], st.URL, false))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// validPaneIndexes reports whether both pane indexes refer to distinct tabs.
+func validPaneIndexes(panes [2]int, n int) bool {
+	return panes[0] != panes[1] &&
+		panes[0] >= 0 && panes[0] < n &&
+		panes[1] >= 0 && panes[1] < n
+}
+
+// sessionCommand handles :session save|load|delete|list.
+func (m *Model) sessionCommand(args []string) tea.Cmd {
+	if m.sessions == nil {
+		m.statusBar.SetMessage("Sessions unavailable (no database)")
+		return nil
+	}
+
+	if len(args) == 0 || args[0] == "list" {
+		m.listSessions()
+		return nil
+	}
+
+	if len(args) < 2 {
+		m.statusBar.SetMessage("Usage: :session save|load|delete <name>")
+		return nil
+	}
+	name := strings.Join(args[1:], " ")
+
+	switch args[0] {
+	case "save":
+		if err := m.sessions.Save(m.snapshotSession(name)); err != nil {
+			m.statusBar.SetMessage(fmt.Sprintf("Error: %s", err))
+			return nil
+		}
+		m.statusBar.SetMessage(fmt.Sprintf("Session saved: %s (%d tabs)", name, m.tabBar.Count()))
+	case "load":
+		s, err := m.sessions.Load(name)
+		if err != nil {
+			m.statusBar.SetMessage(fmt.Sprintf("Error: %s", err))
+			return nil
+		}
+		cmd := m.restoreSession(s)
+		m.statusBar.SetMessage(fmt.Sprintf("Session loaded: %s (%d tabs)", name, len(s.Tabs)))
+		return cmd
+	case "delete", "rm":
+		if m.sessions.Delete(name) {
+			m.statusBar.SetMessage(fmt.Sprintf("Session deleted: %s", name))
+		} else {
+			m.statusBar.SetMessage(fmt.Sprintf("No session named %q", name))
+		}
+	default:
+		m.statusBar.SetMessage("Usage: :session save|load|delete <name>")
+	}
+	return nil
+}
+
+// listSessions shows the saved sessions in the status bar.
+func (m *Model) listSessions() {
+	sessions := m.sessions.List()
+	if len(sessions) == 0 {
+		m.statusBar.SetMessage("No saved sessions")
+		return
+	}
+
+	var parts []string
+	for _, s := range sessions {
+		parts = append(parts, fmt.Sprintf("%s (%d tabs, %s)", s.Name, len(s.Tabs), timeAgo(s.UpdatedAt)))
+	}
+	m.statusBar.SetMessage("Sessions: " + strings.Join(parts, ", "))
+}
+
+// timeAgo formats a timestamp relative to now, e.g. "3h ago".
+func timeAgo(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,144 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vidyasagar/tsurf/internal/browser"
+	"github.com/vidyasagar/tsurf/internal/ui"
+)
+
+// maxClosedTabs bounds the undo-close stack.
+const maxClosedTabs = 20
+
+// closedTab is what undo needs to bring a closed tab back.
+type closedTab struct {
+	title      string
+	url        string
+	history    []string
+	historyPos int
+	scroll     int
+}
+
+// newTabState returns the state for an empty tab.
+func newTabState() *tabState {
+	return &tabState{
+		viewport: ui.NewPageViewport(),
+		history:  browser.NewHistory(),
+	}
+}
+
+// closeActiveTab closes the active tab and remembers it for undo.
+// Returns false if it is the last tab.
+func (m *Model) closeActiveTab() bool {
+	tab := m.tabBar.ActiveTab()
+	if tab == nil {
+		return false
+	}
+	// Copy before closing: the tab bar reuses the slot.
+	closed := *tab
+	if !m.tabBar.CloseCurrentTab() {
+		return false
+	}
+
+	if ts, ok := m.tabStates[closed.ID]; ok {
+		if ts.cancelFunc != nil {
+			ts.cancelFunc()
+		}
+		if entries := ts.history.Entries(); len(entries) > 0 {
+			m.closedTabs = append(m.closedTabs, closedTab{
+				title:      closed.Title,
+				url:        closed.URL,
+				history:    entries,
+				historyPos: ts.history.Position(),
+				scroll:     ts.viewport.YOffset(),
+			})
+			if len(m.closedTabs) > maxClosedTabs {
+				m.closedTabs = m.closedTabs[1:]
+			}
+		}
+		delete(m.tabStates, closed.ID)
+	}
+	m.syncTabUI()
+	return true
+}
+
+// reopenClosedTab restores the most recently closed tab with its history and
+// scroll position.
+func (m *Model) reopenClosedTab() tea.Cmd {
+	if len(m.closedTabs) == 0 {
+		m.statusBar.SetMessage("No closed tabs")
+		return nil
+	}
+	ct := m.closedTabs[len(m.closedTabs)-1]
+	m.closedTabs = m.closedTabs[:len(m.closedTabs)-1]
+
+	m.tabBar.NewTab()
+	tab := m.tabBar.ActiveTab()
+	history := browser.NewHistoryFrom(ct.history, ct.historyPos)
+	m.tabStates[tab.ID] = &tabState{
+		viewport:      ui.NewPageViewport(),
+		history:       history,
+		pendingScroll: ct.scroll,
+	}
+	m.tabBar.SetActiveTitle(ct.title)
+	m.layout()
+	m.syncTabUI()
+
+	url := history.Current()
+	if url == "" {
+		url = ct.url
+	}
+	m.statusBar.SetMessage(fmt.Sprintf("Reopened: %s", ct.title))
+	return m.loadPageInTab(tab.ID, url, false)
+}
+
+// openInNewTab loads url in a new tab and switches to it.
+func (m *Model) openInNewTab(url string) tea.Cmd {
+	m.tabBar.NewTab()
+	tab := m.tabBar.ActiveTab()
+	m.tabStates[tab.ID] = newTabState()
+	m.layout()
+	m.syncTabUI()
+	return m.loadPageInTab(tab.ID, url, true)
+}
+
+// openInBackground loads url in a new tab without switching to it.
+func (m *Model) openInBackground(url string) tea.Cmd {
+	id := m.tabBar.NewBackgroundTab()
+	m.tabStates[id] = newTabState()
+	m.layout()
+	m.statusBar.SetMessage(fmt.Sprintf("Opened in background: %s", url))
+	return m.loadPageInTab(id, url, true)
+}
+
+// tabOpenCommand handles :tabopen and :bgopen. The argument is a link number
+// on the current page or a URL.
+func (m *Model) tabOpenCommand(name string, args []string) tea.Cmd {
+	if len(args) == 0 {
+		m.statusBar.SetMessage(fmt.Sprintf("Usage: :%s <link #|url>", name))
+		return nil
+	}
+
+	target := strings.Join(args, " ")
+	if n, err := strconv.Atoi(target); err == nil {
+		ts := m.activeTabState()
+		if ts == nil {
+			m.statusBar.SetMessage("No page loaded")
+			return nil
+		}
+		url, ok := linkURL(ts, n)
+		if !ok {
+			m.statusBar.SetMessage(fmt.Sprintf("Link [%d] not found", n))
+			return nil
+		}
+		target = url
+	}
+
+	if name == "bgopen" {
+		return m.openInBackground(target)
+	}
+	return m.openInNewTab(target)
+}
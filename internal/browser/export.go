@@ -0,0 +1,69 @@
+package browser
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ArticleJSON is the JSON export of an extracted article, for feeding pages
+// to other tools (`tsurf dump --format=json` and :export json).
+type ArticleJSON struct {
+	Title       string `json:"title"`
+	Byline      string `json:"byline,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+	Excerpt     string `json:"excerpt,omitempty"`
+	Text        string `json:"text"`
+	Content     string `json:"content"` // the rendered page as plain text
+	URL         string `json:"url"`
+	FinalURL    string `json:"final_url"`
+	Canonical   string `json:"canonical_url,omitempty"`
+	Links       []Link `json:"links"`
+	FetchTimeMS int64  `json:"fetch_time_ms"`
+}
+
+// ExportArticle returns the JSON form of an article rendered as page. The
+// links come from the page, as the article itself does not hold them.
+func ExportArticle(a *Article, page *RenderedPage) ArticleJSON {
+	links := []Link{}
+	content := ""
+	if page != nil {
+		if page.Links != nil {
+			links = page.Links
+		}
+		content = PlainText(page.Content)
+	}
+	return ArticleJSON{
+		Title:       a.Title,
+		Byline:      a.Byline,
+		SiteName:    a.SiteName,
+		Excerpt:     a.Excerpt,
+		Text:        a.TextContent,
+		Content:     content,
+		URL:         a.URL,
+		FinalURL:    a.FinalURL,
+		Canonical:   a.Canonical,
+		Links:       links,
+		FetchTimeMS: a.FetchTime.Milliseconds(),
+	}
+}
+
+// PlainText strips the colors from rendered content, and the padding glamour
+// leaves at the end of each line.
+func PlainText(content string) string {
+	lines := strings.Split(ansi.Strip(content), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// WriteJSON writes v to w as indented JSON.
+func WriteJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
@@ -0,0 +1,62 @@
+package browser
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportArticle(t *testing.T) {
+	result := &FetchResult{
+		URL:         "http://example.com/post?utm_source=feed",
+		FinalURL:    "https://example.com/post?utm_source=feed",
+		ContentType: "text/html; charset=utf-8",
+		Duration:    1500 * time.Millisecond,
+		Body: []byte(`<html><head><title>A Post</title>
+<link rel="canonical" href="/post"></head>
+<body><article><h1>A Post</h1>
+<p>` + strings.Repeat("Some words that make this paragraph long enough to be the article. ", 10) + `</p>
+<p>Read <a href="/next">the next post</a>.</p></article></body></html>`),
+	}
+
+	article, err := Extract(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Canonical != "https://example.com/post" {
+		t.Errorf("Canonical = %q", article.Canonical)
+	}
+
+	page := Render(article, 80)
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, ExportArticle(article, page)); err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	for key, want := range map[string]any{
+		"title":         "A Post",
+		"url":           result.URL,
+		"final_url":     result.FinalURL,
+		"canonical_url": "https://example.com/post",
+		"fetch_time_ms": 1500.0,
+	} {
+		if got[key] != want {
+			t.Errorf("%s = %v, want %v", key, got[key], want)
+		}
+	}
+	if text, _ := got["text"].(string); !strings.Contains(text, "Some words") {
+		t.Errorf("text = %q", text)
+	}
+	if content, _ := got["content"].(string); !strings.Contains(content, "Read the next post") || strings.Contains(content, "\x1b") {
+		t.Errorf("content = %q, want the rendered page without colors", content)
+	}
+	if links, _ := got["links"].([]any); len(links) != 1 {
+		t.Errorf("links = %v, want the link to the next post", got["links"])
+	}
+}
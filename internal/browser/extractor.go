@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	readability "github.com/go-shiori/go-readability"
 )
 
@@ -19,15 +21,17 @@ type Article struct {
 	SiteName    string
 	URL         string
 	FinalURL    string
+	Canonical   string // from <link rel="canonical">, if the page has one
 	FetchTime   time.Duration
 	Links       []Link
+	Forms       []*Form // the forms marked in Content
 }
 
 // Link represents a hyperlink found in the page content.
 type Link struct {
-	Index int
-	Text  string
-	URL   string
+	Index int    `json:"index"`
+	Text  string `json:"text"`
+	URL   string `json:"url"`
 }
 
 // Extract takes a FetchResult and extracts the readable article content.
@@ -50,7 +54,18 @@ func Extract(result *FetchResult) (*Article, error) {
 		return nil, fmt.Errorf("parsing URL: %w", err)
 	}
 
-	article, err := readability.FromReader(bytes.NewReader(result.Body), parsedURL)
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(result.Body))
+	if err != nil {
+		return nil, fmt.Errorf("parsing HTML: %w", err)
+	}
+
+	// Readability drops the head and the form controls, so the canonical URL
+	// and the forms are read first. The forms it keeps stay marked in the
+	// content, where the renderer shows their fields.
+	forms := ExtractForms(doc, parsedURL)
+	canonical := canonicalURL(doc, parsedURL)
+
+	article, err := readability.FromDocument(doc.Nodes[0], parsedURL)
 	if err != nil {
 		return nil, fmt.Errorf("extracting article: %w", err)
 	}
@@ -64,7 +79,71 @@ func Extract(result *FetchResult) (*Article, error) {
 		SiteName:    article.SiteName,
 		URL:         result.URL,
 		FinalURL:    result.FinalURL,
+		Canonical:   canonical,
 		FetchTime:   result.Duration,
 		Links:       nil, // Links are populated by the renderer
+		Forms:       keptForms(forms, article.Content),
 	}, nil
 }
+
+// ExtractFull is Extract without readability: the whole page body is kept,
+// navigation and sidebars included, for when the article extraction drops
+// too much. Scripts and styles are removed and links made absolute.
+func ExtractFull(result *FetchResult) (*Article, error) {
+	if !IsHTML(result.ContentType) {
+		return Extract(result)
+	}
+
+	base, err := url.Parse(result.FinalURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing URL: %w", err)
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(result.Body))
+	if err != nil {
+		return nil, fmt.Errorf("parsing HTML: %w", err)
+	}
+
+	doc.Find("script, style, noscript, template, svg, iframe").Remove()
+	forms := ExtractForms(doc, base)
+	for _, attr := range []string{"href", "src"} {
+		doc.Find("[" + attr + "]").Each(func(i int, s *goquery.Selection) {
+			if ref, err := base.Parse(s.AttrOr(attr, "")); err == nil {
+				s.SetAttr(attr, ref.String())
+			}
+		})
+	}
+
+	body, err := goquery.OuterHtml(doc.Find("body"))
+	if err != nil {
+		return nil, fmt.Errorf("rendering HTML: %w", err)
+	}
+	title := strings.TrimSpace(doc.Find("title").First().Text())
+	if title == "" {
+		title = result.FinalURL
+	}
+
+	return &Article{
+		Title:       title,
+		Content:     body,
+		TextContent: strings.TrimSpace(doc.Find("body").Text()),
+		URL:         result.URL,
+		FinalURL:    result.FinalURL,
+		Canonical:   canonicalURL(doc, base),
+		FetchTime:   result.Duration,
+		Forms:       forms,
+	}, nil
+}
+
+// canonicalURL returns the page's <link rel="canonical"> made absolute, or
+// "" when it has none.
+func canonicalURL(doc *goquery.Document, base *url.URL) string {
+	href, ok := doc.Find(`link[rel~="canonical"]`).First().Attr("href")
+	if !ok || strings.TrimSpace(href) == "" {
+		return ""
+	}
+	ref, err := base.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return ""
+	}
+	return ref.String()
+}
@@ -1,6 +1,7 @@
 package browser
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -17,9 +18,13 @@ const (
 	defaultUserAgent = "tsurf/0.1 (terminal browser; +https://github.com/vidyasagar/tsurf)"
 )
 
-// SharedTransport is a tuned HTTP transport shared across all clients.
-// This enables connection pooling and reuse across the application.
-var SharedTransport = &http.Transport{
+// SharedTransport is the HTTP transport shared across all clients.
+// This enables connection pooling and reuse across the application, and
+// lets every client use the disk cache once EnableHTTPCache is called.
+var SharedTransport = &CachingTransport{Transport: baseTransport}
+
+// baseTransport is the tuned network transport behind SharedTransport.
+var baseTransport = &http.Transport{
 	Proxy: http.ProxyFromEnvironment,
 	DialContext: (&net.Dialer{
 		Timeout:   10 * time.Second,
@@ -44,9 +49,16 @@ type FetchResult struct {
 	Duration    time.Duration
 }
 
+// SiteJar is a cookie jar that can be scoped to the top-level site being
+// loaded, so it can tell first-party cookies from third-party ones.
+type SiteJar interface {
+	ForSite(site *url.URL) http.CookieJar
+}
+
 // Fetcher handles HTTP requests with proper headers and timeouts.
 type Fetcher struct {
 	client    *http.Client
+	jar       SiteJar
 	userAgent string
 }
 
@@ -67,6 +79,11 @@ func NewFetcher() *Fetcher {
 	}
 }
 
+// SetCookieJar sets the cookie jar used for page loads. Nil disables cookies.
+func (f *Fetcher) SetCookieJar(jar SiteJar) {
+	f.jar = jar
+}
+
 // Client returns the underlying HTTP client for use by other packages.
 func (f *Fetcher) Client() *http.Client {
 	return f.client
@@ -77,11 +94,33 @@ func (f *Fetcher) Fetch(rawURL string) (*FetchResult, error) {
 	return f.FetchWithContext(context.Background(), rawURL)
 }
 
+// Request describes an HTTP request for Do. An empty Method means GET.
+type Request struct {
+	Method      string
+	URL         string
+	Body        []byte
+	ContentType string
+}
+
 // FetchWithContext retrieves content with a cancellable context.
 func (f *Fetcher) FetchWithContext(ctx context.Context, rawURL string) (*FetchResult, error) {
-	rawURL = normalizeURL(rawURL)
+	return f.Do(ctx, Request{Method: http.MethodGet, URL: rawURL})
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+// Do performs a request, such as a form submission, and reads the response.
+func (f *Fetcher) Do(ctx context.Context, r Request) (*FetchResult, error) {
+	rawURL := normalizeURL(r.URL)
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var reqBody io.Reader
+	if r.Body != nil {
+		reqBody = bytes.NewReader(r.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
@@ -89,9 +128,19 @@ func (f *Fetcher) FetchWithContext(ctx context.Context, rawURL string) (*FetchRe
 	req.Header.Set("User-Agent", f.userAgent)
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	if r.ContentType != "" {
+		req.Header.Set("Content-Type", r.ContentType)
+	}
+
+	client := f.client
+	if f.jar != nil {
+		c := *f.client
+		c.Jar = f.jar.ForSite(req.URL)
+		client = &c
+	}
 
 	start := time.Now()
-	resp, err := f.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("fetching %s: %w", rawURL, err)
 	}
@@ -0,0 +1,625 @@
+package browser
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vidyasagar/tsurf/internal/theme"
+)
+
+const maxForms = 20
+
+// formAttr marks a form in the page HTML with its number, so that the
+// renderer can show its fields in place.
+const formAttr = "data-tsurf-form"
+
+// Form encodings.
+const (
+	FormURLEncoded = "application/x-www-form-urlencoded"
+	FormMultipart  = "multipart/form-data"
+)
+
+// Form is an HTML form found on a page.
+type Form struct {
+	Index   int    // 1-based, in page order
+	Action  string // absolute URL
+	Method  string // http.MethodGet or http.MethodPost
+	Enctype string // FormURLEncoded or FormMultipart
+	Fields  []*FormField
+	mark    int // the form's formAttr value
+}
+
+// FormField is a single control in a form.
+type FormField struct {
+	Index       int // 1-based field number across the page; 0 for hidden fields
+	Name        string
+	Type        string // input type, or "select" / "textarea"
+	Label       string
+	Value       string // for file inputs, the local path to upload
+	Placeholder string
+	Checked     bool // checkboxes and radio buttons
+	Options     []FormOption
+	form        *Form
+}
+
+// FormOption is an option of a <select>.
+type FormOption struct {
+	Value string
+	Label string
+}
+
+// Form returns the form the field belongs to.
+func (ff *FormField) Form() *Form {
+	return ff.form
+}
+
+// Editable reports whether the field takes typed input (or an option choice),
+// as opposed to being toggled or pressed.
+func (ff *FormField) Editable() bool {
+	switch ff.Type {
+	case "hidden", "checkbox", "radio", "submit":
+		return false
+	}
+	return true
+}
+
+// Toggle flips a checkbox, or selects a radio button and clears the others
+// in its group.
+func (ff *FormField) Toggle() {
+	switch ff.Type {
+	case "checkbox":
+		ff.Checked = !ff.Checked
+	case "radio":
+		for _, other := range ff.form.Fields {
+			if other.Type == "radio" && other.Name == ff.Name {
+				other.Checked = false
+			}
+		}
+		ff.Checked = true
+	}
+}
+
+// OptionLabel returns the label of the selected option of a <select>.
+func (ff *FormField) OptionLabel() string {
+	for _, o := range ff.Options {
+		if o.Value == ff.Value {
+			return o.Label
+		}
+	}
+	return ff.Value
+}
+
+// CycleOption selects the next (delta > 0) or previous option of a <select>.
+func (ff *FormField) CycleOption(delta int) {
+	if len(ff.Options) == 0 {
+		return
+	}
+	cur := 0
+	for i, o := range ff.Options {
+		if o.Value == ff.Value {
+			cur = i
+			break
+		}
+	}
+	cur = (cur + delta + len(ff.Options)) % len(ff.Options)
+	ff.Value = ff.Options[cur].Value
+}
+
+// SetOption selects the option whose label or value matches s
+// (case-insensitive). Returns false if none matches.
+func (ff *FormField) SetOption(s string) bool {
+	for _, o := range ff.Options {
+		if strings.EqualFold(o.Label, s) || strings.EqualFold(o.Value, s) {
+			ff.Value = o.Value
+			return true
+		}
+	}
+	return false
+}
+
+// cloneForms copies forms and their fields, so that the copy can be filled in
+// without touching the original.
+func cloneForms(forms []*Form) []*Form {
+	if forms == nil {
+		return nil
+	}
+	clones := make([]*Form, len(forms))
+	for i, f := range forms {
+		form := *f
+		form.Fields = make([]*FormField, len(f.Fields))
+		for j, ff := range f.Fields {
+			field := *ff
+			field.form = &form
+			form.Fields[j] = &field
+		}
+		clones[i] = &form
+	}
+	return clones
+}
+
+// FindField returns the field with the given page-wide number.
+func FindField(forms []*Form, index int) *FormField {
+	for _, f := range forms {
+		for _, ff := range f.Fields {
+			if ff.Index == index {
+				return ff
+			}
+		}
+	}
+	return nil
+}
+
+// NextEditable returns the editable field after (delta > 0) or before
+// (delta < 0) ff in the same form, wrapping around, or nil if there is none.
+func (f *Form) NextEditable(ff *FormField, delta int) *FormField {
+	var editable []*FormField
+	cur := -1
+	for _, field := range f.Fields {
+		if field.Index > 0 && field.Editable() {
+			if field == ff {
+				cur = len(editable)
+			}
+			editable = append(editable, field)
+		}
+	}
+	if len(editable) == 0 || (cur >= 0 && len(editable) == 1) {
+		return nil
+	}
+	if cur < 0 {
+		return editable[0]
+	}
+	return editable[(cur+delta+len(editable))%len(editable)]
+}
+
+// FirstEditable returns the first editable field across all forms.
+func FirstEditable(forms []*Form) *FormField {
+	for _, f := range forms {
+		for _, ff := range f.Fields {
+			if ff.Index > 0 && ff.Editable() {
+				return ff
+			}
+		}
+	}
+	return nil
+}
+
+// ExtractForms finds the forms in an HTML document and marks each one kept
+// with formAttr. Relative actions are resolved against base. Forms without
+// any visible field are skipped.
+func ExtractForms(doc *goquery.Document, base *url.URL) []*Form {
+	// Index <label for="..."> texts by target ID.
+	labels := make(map[string]string)
+	doc.Find("label[for]").Each(func(i int, s *goquery.Selection) {
+		id, _ := s.Attr("for")
+		labels[id] = cleanText(s.Text())
+	})
+
+	var forms []*Form
+	fieldIndex := 0
+	doc.Find("form").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		form := newForm(s, base)
+
+		s.Find("input, select, textarea, button").Each(func(j int, el *goquery.Selection) {
+			if _, disabled := el.Attr("disabled"); disabled {
+				return
+			}
+			if field := newField(el, labels); field != nil {
+				field.form = form
+				form.Fields = append(form.Fields, field)
+			}
+		})
+
+		visible := false
+		for _, ff := range form.Fields {
+			if ff.Type != "hidden" {
+				visible = true
+			}
+		}
+		if !visible {
+			return true
+		}
+
+		for _, ff := range form.Fields {
+			if ff.Type != "hidden" {
+				fieldIndex++
+				ff.Index = fieldIndex
+			}
+		}
+		form.Index = len(forms) + 1
+		form.mark = form.Index
+		s.SetAttr(formAttr, strconv.Itoa(form.mark))
+		forms = append(forms, form)
+		return len(forms) < maxForms
+	})
+
+	return forms
+}
+
+// keptForms returns the forms whose mark is still in content, such as the
+// HTML left by readability, numbered again from 1.
+func keptForms(forms []*Form, content string) []*Form {
+	if len(forms) == 0 {
+		return nil
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return nil
+	}
+	marked := make(map[int]bool)
+	doc.Find("[" + formAttr + "]").Each(func(i int, s *goquery.Selection) {
+		if n, err := strconv.Atoi(s.AttrOr(formAttr, "")); err == nil {
+			marked[n] = true
+		}
+	})
+
+	var kept []*Form
+	fieldIndex := 0
+	for _, f := range forms {
+		if !marked[f.mark] {
+			continue
+		}
+		for _, ff := range f.Fields {
+			if ff.Index > 0 {
+				fieldIndex++
+				ff.Index = fieldIndex
+			}
+		}
+		f.Index = len(kept) + 1
+		kept = append(kept, f)
+	}
+	return kept
+}
+
+// newForm reads a <form> element's attributes.
+func newForm(s *goquery.Selection, base *url.URL) *Form {
+	form := &Form{
+		Method:  http.MethodGet,
+		Enctype: FormURLEncoded,
+	}
+
+	action, _ := s.Attr("action")
+	form.Action = resolveURL(base, strings.TrimSpace(action))
+
+	if method, _ := s.Attr("method"); strings.EqualFold(method, "post") {
+		form.Method = http.MethodPost
+	}
+	if enctype, _ := s.Attr("enctype"); strings.EqualFold(enctype, FormMultipart) {
+		form.Enctype = FormMultipart
+	}
+	return form
+}
+
+// newField converts a form control into a field, or returns nil for
+// controls that are never submitted (reset and plain buttons).
+func newField(el *goquery.Selection, labels map[string]string) *FormField {
+	name, _ := el.Attr("name")
+	value, _ := el.Attr("value")
+	placeholder, _ := el.Attr("placeholder")
+	_, checked := el.Attr("checked")
+
+	field := &FormField{
+		Name:        name,
+		Value:       value,
+		Placeholder: placeholder,
+	}
+
+	switch goquery.NodeName(el) {
+	case "select":
+		field.Type = "select"
+		el.Find("option").Each(func(i int, o *goquery.Selection) {
+			label := cleanText(o.Text())
+			v, ok := o.Attr("value")
+			if !ok {
+				v = label
+			}
+			field.Options = append(field.Options, FormOption{Value: v, Label: label})
+			if _, selected := o.Attr("selected"); selected || i == 0 {
+				field.Value = v
+			}
+		})
+	case "textarea":
+		field.Type = "textarea"
+		field.Value = el.Text()
+	case "button":
+		t, _ := el.Attr("type")
+		if t != "" && !strings.EqualFold(t, "submit") {
+			return nil
+		}
+		field.Type = "submit"
+		if field.Label = cleanText(el.Text()); field.Label == "" {
+			field.Label = "Submit"
+		}
+	default:
+		t, _ := el.Attr("type")
+		field.Type = strings.ToLower(t)
+		switch field.Type {
+		case "", "text":
+			field.Type = "text"
+		case "reset", "button":
+			return nil
+		case "submit", "image":
+			field.Type = "submit"
+			field.Label = value
+			if field.Label == "" {
+				field.Label, _ = el.Attr("alt")
+			}
+			if field.Label == "" {
+				field.Label = "Submit"
+			}
+		case "checkbox", "radio":
+			field.Checked = checked
+			if field.Value == "" {
+				field.Value = "on"
+			}
+		case "file":
+			field.Value = ""
+		}
+	}
+
+	if field.Label == "" {
+		field.Label = fieldLabel(el, labels)
+	}
+	return field
+}
+
+// fieldLabel finds a human-readable label for a control.
+func fieldLabel(el *goquery.Selection, labels map[string]string) string {
+	if id, ok := el.Attr("id"); ok && labels[id] != "" {
+		return labels[id]
+	}
+	if wrap := el.Closest("label"); wrap.Length() > 0 {
+		clone := wrap.Clone()
+		clone.Find("select, textarea").Remove()
+		if text := cleanText(clone.Text()); text != "" {
+			return text
+		}
+	}
+	for _, attr := range []string{"aria-label", "title", "placeholder", "name"} {
+		if v, _ := el.Attr(attr); strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+// cleanText collapses whitespace.
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// resolveURL resolves ref against base, returning base itself for an empty ref.
+func resolveURL(base *url.URL, ref string) string {
+	if base == nil {
+		return ref
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return base.String()
+	}
+	return u.String()
+}
+
+// formPair is a name/value pair in submission order.
+type formPair struct {
+	name  string
+	value string
+	file  bool
+}
+
+// pairs returns the values to submit. Only the submit button used to send
+// the form (if any) is included.
+func (f *Form) pairs(submitter *FormField) []formPair {
+	var pairs []formPair
+	for _, ff := range f.Fields {
+		if ff.Name == "" {
+			continue
+		}
+		switch ff.Type {
+		case "submit":
+			if ff != submitter {
+				continue
+			}
+		case "checkbox", "radio":
+			if !ff.Checked {
+				continue
+			}
+		}
+		pairs = append(pairs, formPair{name: ff.Name, value: ff.Value, file: ff.Type == "file"})
+	}
+	return pairs
+}
+
+// Request builds the HTTP request that submits the form, optionally via a
+// specific submit button. GET forms replace the action's query string;
+// POST forms are sent url-encoded or as multipart with file uploads.
+func (f *Form) Request(submitter *FormField) (Request, error) {
+	pairs := f.pairs(submitter)
+
+	if f.Method != http.MethodPost {
+		u, err := url.Parse(f.Action)
+		if err != nil {
+			return Request{}, fmt.Errorf("parsing form action: %w", err)
+		}
+		u.RawQuery = encodePairs(pairs)
+		return Request{Method: http.MethodGet, URL: u.String()}, nil
+	}
+
+	if f.Enctype != FormMultipart {
+		return Request{
+			Method:      http.MethodPost,
+			URL:         f.Action,
+			Body:        []byte(encodePairs(pairs)),
+			ContentType: FormURLEncoded,
+		}, nil
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, p := range pairs {
+		if !p.file {
+			if err := w.WriteField(p.name, p.value); err != nil {
+				return Request{}, fmt.Errorf("encoding form: %w", err)
+			}
+			continue
+		}
+		if err := writeFilePart(w, p.name, p.value); err != nil {
+			return Request{}, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return Request{}, fmt.Errorf("encoding form: %w", err)
+	}
+
+	return Request{
+		Method:      http.MethodPost,
+		URL:         f.Action,
+		Body:        body.Bytes(),
+		ContentType: w.FormDataContentType(),
+	}, nil
+}
+
+// encodePairs url-encodes pairs, keeping their order.
+func encodePairs(pairs []formPair) string {
+	parts := make([]string, len(pairs))
+	for i, p := range pairs {
+		parts[i] = url.QueryEscape(p.name) + "=" + url.QueryEscape(p.value)
+	}
+	return strings.Join(parts, "&")
+}
+
+// writeFilePart adds a file upload to a multipart body. An empty path sends
+// an empty part, as browsers do for a file input with nothing chosen.
+func writeFilePart(w *multipart.Writer, name, path string) error {
+	if path == "" {
+		_, err := w.CreateFormFile(name, "")
+		return err
+	}
+
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening upload: %w", err)
+	}
+	defer file.Close()
+
+	part, err := w.CreateFormFile(name, filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("encoding form: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("reading upload: %w", err)
+	}
+	return nil
+}
+
+// RenderForms renders forms as numbered fields for the viewport.
+func RenderForms(forms []*Form, width int) string {
+	if len(forms) == 0 {
+		return ""
+	}
+
+	t := theme.Current
+	headerStyle := lipgloss.NewStyle().Foreground(t.Border)
+	indexStyle := lipgloss.NewStyle().Foreground(t.LinkIndex).Bold(true)
+	labelStyle := lipgloss.NewStyle().Foreground(t.Text)
+	boxStyle := lipgloss.NewStyle().Foreground(t.Code).Background(t.CodeBg)
+	dimStyle := lipgloss.NewStyle().Foreground(t.TextDim)
+	buttonStyle := lipgloss.NewStyle().Foreground(t.Background).Background(t.Accent).Bold(true)
+
+	boxWidth := 30
+	if width > 0 && width/2 < boxWidth {
+		boxWidth = width / 2
+	}
+
+	var sb strings.Builder
+	for _, f := range forms {
+		action := f.Action
+		if u, err := url.Parse(f.Action); err == nil {
+			action = u.Host + u.Path
+		}
+		sb.WriteString("\n  " + headerStyle.Render(fmt.Sprintf("── Form %d · %s %s ──", f.Index, f.Method, action)) + "\n\n")
+
+		for _, ff := range f.Fields {
+			if ff.Index == 0 {
+				continue
+			}
+			idx := indexStyle.Render(fmt.Sprintf("{%d}", ff.Index))
+
+			var line string
+			switch ff.Type {
+			case "submit":
+				line = buttonStyle.Render(" " + ff.Label + " ")
+			case "checkbox":
+				mark := "[ ]"
+				if ff.Checked {
+					mark = "[x]"
+				}
+				line = labelStyle.Render(mark + " " + ff.Label)
+			case "radio":
+				mark := "( )"
+				if ff.Checked {
+					mark = "(•)"
+				}
+				line = labelStyle.Render(mark + " " + ff.Label)
+			default:
+				line = labelStyle.Render(ff.Label+":") + " " + boxStyle.Render(fieldBox(ff, boxWidth, dimStyle))
+			}
+			sb.WriteString("  " + idx + " " + line + "\n")
+		}
+	}
+	sb.WriteString("\n  " + dimStyle.Render("Edit a field with i<n> (gi for the first), Enter submits") + "\n")
+	return sb.String()
+}
+
+// fieldBox renders the value area of an editable field, padded to width.
+func fieldBox(ff *FormField, width int, dim lipgloss.Style) string {
+	var text string
+	switch ff.Type {
+	case "password":
+		text = strings.Repeat("•", len([]rune(ff.Value)))
+	case "select":
+		text = ff.OptionLabel() + " ▾"
+	case "file":
+		text = "file: " + ff.Value
+	case "textarea":
+		text = strings.ReplaceAll(ff.Value, "\n", "⏎")
+	default:
+		text = ff.Value
+	}
+
+	if text == "" && ff.Placeholder != "" {
+		return dim.Render(padRight(truncateRunes(ff.Placeholder, width), width))
+	}
+	return padRight(truncateRunes(text, width), width)
+}
+
+// truncateRunes shortens s to at most n runes, marking the cut.
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n || n < 2 {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// padRight pads s with spaces to n cells.
+func padRight(s string, n int) string {
+	if w := lipgloss.Width(s); w < n {
+		return s + strings.Repeat(" ", n-w)
+	}
+	return s
+}
@@ -0,0 +1,205 @@
+package browser
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const testFormHTML = `<html><body>
+<form action="/search" id="s">
+  <label for="q">Query</label><input id="q" name="q" value="go">
+  <input type="hidden" name="src" value="tsurf">
+  <select name="lang"><option value="en">English</option><option value="de" selected>Deutsch</option></select>
+  <label><input type="checkbox" name="safe" checked> Safe search</label>
+  <input type="submit" name="go" value="Search">
+</form>
+<form method="post" action="https://example.com/upload" enctype="multipart/form-data">
+  <input name="title" placeholder="Title">
+  <input type="file" name="doc">
+  <button>Upload</button>
+</form>
+<form><input type="hidden" name="csrf" value="x"></form>
+</body></html>`
+
+func parseTestForms(t *testing.T) []*Form {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(testFormHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, _ := url.Parse("https://example.com/page?old=1")
+	return ExtractForms(doc, base)
+}
+
+func TestExtractForms(t *testing.T) {
+	forms := parseTestForms(t)
+	if len(forms) != 2 {
+		t.Fatalf("got %d forms, want 2 (hidden-only form skipped)", len(forms))
+	}
+
+	f := forms[0]
+	if f.Action != "https://example.com/search" || f.Method != http.MethodGet {
+		t.Errorf("form 1 = %s %s", f.Method, f.Action)
+	}
+
+	q := FindField(forms, 1)
+	if q == nil || q.Label != "Query" || q.Value != "go" {
+		t.Errorf("field 1 = %+v", q)
+	}
+	if lang := FindField(forms, 2); lang == nil || lang.Value != "de" || lang.OptionLabel() != "Deutsch" {
+		t.Errorf("field 2 = %+v", lang)
+	}
+	if safe := FindField(forms, 3); safe == nil || safe.Label != "Safe search" || !safe.Checked {
+		t.Errorf("field 3 = %+v", safe)
+	}
+
+	// Numbering continues across forms; hidden fields are not numbered.
+	if title := FindField(forms, 5); title == nil || title.Name != "title" || title.Form() != forms[1] {
+		t.Errorf("field 5 = %+v", title)
+	}
+	if forms[1].Method != http.MethodPost || forms[1].Enctype != FormMultipart {
+		t.Errorf("form 2 = %s %s", forms[1].Method, forms[1].Enctype)
+	}
+}
+
+func TestFormRequestGet(t *testing.T) {
+	forms := parseTestForms(t)
+	FindField(forms, 1).Value = "hello world"
+	FindField(forms, 3).Toggle()
+
+	req, err := forms[0].Request(FindField(forms, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "https://example.com/search?q=hello+world&src=tsurf&lang=de&go=Search"
+	if req.Method != http.MethodGet || req.URL != want {
+		t.Errorf("Request = %s %s, want GET %s", req.Method, req.URL, want)
+	}
+}
+
+func TestFormRequestMultipart(t *testing.T) {
+	forms := parseTestForms(t)
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("file body"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	FindField(forms, 5).Value = "My doc"
+	FindField(forms, 6).Value = path
+
+	req, err := forms[1].Request(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, params, err := mime.ParseMediaType(req.ContentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := multipart.NewReader(strings.NewReader(string(req.Body)), params["boundary"])
+
+	got := make(map[string]string)
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(part)
+		got[part.FormName()] = part.FileName() + ":" + string(data)
+	}
+
+	if got["title"] != ":My doc" || got["doc"] != "notes.txt:file body" {
+		t.Errorf("multipart parts = %v", got)
+	}
+}
+
+func TestRenderedPageCloneForms(t *testing.T) {
+	article := &Article{Title: "Login", Forms: parseTestForms(t)}
+	page := withForms(&RenderedPage{}, "body\n"+formMark+"1\n"+formMark+"2\n", article, 80)
+
+	FindField(page.Forms, 1).Value = "secret"
+	FindField(page.Forms, 3).Toggle()
+	page.RefreshForms()
+	if !page.Edited() || !strings.Contains(page.Content, "secret") {
+		t.Fatalf("edit not rendered:\n%s", page.Content)
+	}
+	if FindField(article.Forms, 1).Value != "go" {
+		t.Error("editing the page changed the article's forms")
+	}
+
+	clone := page.Clone()
+	if clone.Edited() || strings.Contains(clone.Content, "secret") {
+		t.Errorf("clone shows the typed value:\n%s", clone.Content)
+	}
+	q := FindField(clone.Forms, 1)
+	if q.Value != "go" || !FindField(clone.Forms, 3).Checked || q.Form() != clone.Forms[0] {
+		t.Errorf("clone field 1 = %+v", q)
+	}
+}
+
+func TestRenderPlacesForms(t *testing.T) {
+	result := &FetchResult{
+		URL:         "https://example.com/",
+		FinalURL:    "https://example.com/",
+		ContentType: "text/html",
+		Body: []byte(`<html><body><p>Before the form.</p>
+<form action="/search"><input name="q"></form>
+<p>After it, <a href="/next">next</a>.</p></body></html>`),
+	}
+	article, err := ExtractFull(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, page := range map[string]*RenderedPage{
+		"glamour":  Render(article, 80),
+		"fallback": RenderFallback(article, 80),
+	} {
+		content := ansi.Strip(page.Content)
+		before, form, after := strings.Index(content, "Before"), strings.Index(content, "Form 1"), strings.Index(content, "After")
+		if strings.Contains(content, formMark) || before < 0 || !(before < form && form < after) {
+			t.Errorf("%s: form not shown between the paragraphs:\n%s", name, content)
+		}
+		if len(page.Forms) != 1 || len(page.Refs) != 1 {
+			t.Fatalf("%s: %d forms, refs %+v", name, len(page.Forms), page.Refs)
+		}
+		ref := page.Refs[0]
+		if line := strings.Split(content, "\n")[ref.Line]; ansi.Cut(line, ref.Start, ref.End) != "[1]" {
+			t.Errorf("%s: ref %+v points into %q", name, ref, line)
+		}
+	}
+}
+
+func TestExtractDropsStrippedForms(t *testing.T) {
+	para := strings.Repeat("This paragraph is part of the article, with enough text to be kept. ", 8)
+	result := &FetchResult{
+		URL:         "https://example.com/post",
+		FinalURL:    "https://example.com/post",
+		ContentType: "text/html",
+		Body: []byte(`<html><body>
+<div id="sidebar"><form action="/subscribe"><input name="email"><input type="submit" value="Subscribe"></form></div>
+<article><p>` + para + `</p><p>` + para + `</p><p>` + para + `</p></article>
+</body></html>`),
+	}
+	article, err := Extract(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(article.Forms) != 0 {
+		t.Errorf("Forms = %+v, want the sidebar form left out", article.Forms)
+	}
+	if page := Render(article, 80); strings.Contains(page.Content, "Subscribe") {
+		t.Errorf("stripped form rendered:\n%s", page.Content)
+	}
+}
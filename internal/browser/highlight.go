@@ -0,0 +1,190 @@
+package browser
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/vidyasagar/tsurf/internal/theme"
+)
+
+// chromaMu guards registration in chroma's global style registry.
+var chromaMu sync.Mutex
+
+// classPrefixes are the class name prefixes sites use to tag a code block's
+// language: language-go (Markdown renderers, Prism), lang-go (highlight.js),
+// highlight-source-go (GitHub) and brush: go (SyntaxHighlighter).
+var classPrefixes = []string{"language-", "lang-", "highlight-source-", "highlight-", "brush:"}
+
+// DetectLanguage returns the chroma lexer alias for a code block, taken from
+// its class attributes or, failing that, guessed from the code itself.
+// Returns "" if the language is unknown.
+func DetectLanguage(classes, code string) string {
+	fields := strings.Fields(strings.ReplaceAll(classes, "brush: ", "brush:"))
+	for _, class := range fields {
+		class = strings.ToLower(class)
+		for _, prefix := range classPrefixes {
+			if name, ok := strings.CutPrefix(class, prefix); ok {
+				if lexer := lexers.Get(name); lexer != nil {
+					return lexerAlias(lexer)
+				}
+			}
+		}
+	}
+	// Bare class names, as in <pre class="sourceCode python">.
+	for _, class := range fields {
+		if lexer := lexers.Get(strings.ToLower(class)); lexer != nil && len(class) > 1 {
+			return lexerAlias(lexer)
+		}
+	}
+
+	if strings.Count(code, "\n") < 1 {
+		return "" // too little to guess from
+	}
+	if lexer := lexers.Analyse(code); lexer != nil {
+		return lexerAlias(lexer)
+	}
+	return ""
+}
+
+// lexerAlias returns the name a lexer can be looked up by.
+func lexerAlias(lexer chroma.Lexer) string {
+	cfg := lexer.Config()
+	if len(cfg.Aliases) > 0 {
+		return cfg.Aliases[0]
+	}
+	return strings.ToLower(cfg.Name)
+}
+
+// HighlightCode colors code for the terminal with the active theme. Code in
+// an unknown language is returned in the theme's plain code color.
+func HighlightCode(code, lang string) string {
+	code = strings.TrimRight(code, "\n")
+
+	lexer := lexers.Get(lang)
+	profile := lipgloss.ColorProfile()
+	if lexer == nil || profile == termenv.Ascii {
+		return lipgloss.NewStyle().Foreground(theme.Current.Code).Render(code)
+	}
+
+	formatter := formatters.Get("terminal256")
+	switch profile {
+	case termenv.TrueColor:
+		formatter = formatters.Get("terminal16m")
+	case termenv.ANSI:
+		formatter = formatters.Get("terminal16")
+	}
+
+	it, err := chroma.Coalesce(lexer).Tokenise(nil, code)
+	if err != nil {
+		return code
+	}
+
+	// Format line by line so every line carries its own escape sequences and
+	// stays colored when the viewport shows it without the lines above.
+	style := chromaStyle(theme.Current)
+	var lines []string
+	for _, tokens := range chroma.SplitTokensIntoLines(it.Tokens()) {
+		if n := len(tokens); n > 0 {
+			tokens[n-1].Value = strings.TrimSuffix(tokens[n-1].Value, "\n")
+		}
+		var buf bytes.Buffer
+		if err := formatter.Format(&buf, style, chroma.Literator(tokens...)); err != nil {
+			return code
+		}
+		lines = append(lines, buf.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// chromaStyle returns the chroma style for a theme, registering it under
+// ChromaStyleName so glamour can find it too.
+func chromaStyle(t theme.Theme) *chroma.Style {
+	name := ChromaStyleName(t)
+
+	chromaMu.Lock()
+	defer chromaMu.Unlock()
+	if s, ok := styles.Registry[name]; ok {
+		return s
+	}
+
+	c := func(color lipgloss.Color) string { return string(color) }
+	style, err := chroma.NewStyle(name, chroma.StyleEntries{
+		chroma.Text:                c(t.Code),
+		chroma.Error:               c(t.Error),
+		chroma.Comment:             "italic " + c(t.TextDim),
+		chroma.CommentPreproc:      c(t.Info),
+		chroma.Keyword:             "bold " + c(t.Heading),
+		chroma.KeywordType:         c(t.Info),
+		chroma.Operator:            c(t.Quote),
+		chroma.Punctuation:         c(t.Quote),
+		chroma.Name:                c(t.Text),
+		chroma.NameBuiltin:         c(t.Info),
+		chroma.NameTag:             c(t.Heading),
+		chroma.NameAttribute:       c(t.Secondary),
+		chroma.NameClass:           "bold " + c(t.Link),
+		chroma.NameConstant:        c(t.Accent),
+		chroma.NameDecorator:       c(t.Warning),
+		chroma.NameException:       c(t.Error),
+		chroma.NameFunction:        c(t.Secondary),
+		chroma.Literal:             c(t.Accent),
+		chroma.LiteralNumber:       c(t.Accent),
+		chroma.LiteralString:       c(t.Success),
+		chroma.LiteralStringEscape: c(t.Warning),
+		chroma.GenericDeleted:      c(t.Error),
+		chroma.GenericInserted:     c(t.Success),
+		chroma.GenericEmph:         "italic",
+		chroma.GenericStrong:       "bold",
+		chroma.GenericSubheading:   c(t.Heading),
+	})
+	if err != nil {
+		return styles.Fallback
+	}
+	return styles.Register(style)
+}
+
+// ChromaStyleName is the chroma style registry name for a theme.
+func ChromaStyleName(t theme.Theme) string {
+	return "tsurf-" + t.Name
+}
+
+// fenceRe matches the opening line of a fenced code block without a language.
+var fenceRe = regexp.MustCompile("^\\s*```[ \\t]*$")
+
+// AnnotateCodeFences adds a detected language to fenced code blocks in
+// Markdown that don't name one, so glamour can highlight them.
+func AnnotateCodeFences(md string) string {
+	lines := strings.Split(md, "\n")
+	inFence := false
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, "```") {
+			continue
+		}
+		if inFence {
+			inFence = false
+			continue
+		}
+		inFence = true
+		if !fenceRe.MatchString(lines[i]) {
+			continue // already has a language
+		}
+
+		// Find the closing fence and guess from the block's content.
+		end := i + 1
+		for end < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[end]), "```") {
+			end++
+		}
+		if lang := DetectLanguage("", strings.Join(lines[i+1:end], "\n")); lang != "" {
+			lines[i] = strings.TrimRight(lines[i], " \t") + lang
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,55 @@
+package browser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vidyasagar/tsurf/internal/theme"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		classes string
+		code    string
+		want    string
+	}{
+		{"language-go", "", "go"},
+		{"highlight highlight-source-python", "", "python"},
+		{"lang-js hljs", "", "js"},
+		{"sourceCode rust", "", "rust"},
+		{"", "#!/bin/bash\necho hi\n", "bash"},
+		{"", "just some text", ""},
+	}
+
+	for _, tt := range tests {
+		if got := DetectLanguage(tt.classes, tt.code); got != tt.want {
+			t.Errorf("DetectLanguage(%q, %q) = %q, want %q", tt.classes, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestAnnotateCodeFences(t *testing.T) {
+	md := "Intro\n\n```\n#!/bin/sh\nls -la\n```\n\n```go\nfunc main() {}\n```\n"
+	got := AnnotateCodeFences(md)
+
+	if !strings.Contains(got, "```bash\n#!/bin/sh") {
+		t.Errorf("bare fence not annotated:\n%s", got)
+	}
+	if !strings.Contains(got, "```go\nfunc main") || strings.Count(got, "```") != 4 {
+		t.Errorf("fences changed unexpectedly:\n%s", got)
+	}
+}
+
+func TestStyleConfigUsesTheme(t *testing.T) {
+	cfg := StyleConfig(theme.Gruvbox)
+
+	if got := *cfg.Heading.Color; got != string(theme.Gruvbox.Heading) {
+		t.Errorf("heading color = %s, want %s", got, theme.Gruvbox.Heading)
+	}
+	if got := *cfg.Code.BackgroundColor; got != string(theme.Gruvbox.CodeBg) {
+		t.Errorf("code background = %s, want %s", got, theme.Gruvbox.CodeBg)
+	}
+	if cfg.CodeBlock.Theme != ChromaStyleName(theme.Gruvbox) {
+		t.Errorf("code block theme = %q", cfg.CodeBlock.Theme)
+	}
+}
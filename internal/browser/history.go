@@ -14,6 +14,20 @@ func NewHistory() *History {
 	}
 }
 
+// NewHistoryFrom recreates a history from saved entries and position.
+func NewHistoryFrom(entries []string, pos int) *History {
+	h := &History{entries: append([]string(nil), entries...)}
+	switch {
+	case len(h.entries) == 0:
+		h.pos = -1
+	case pos < 0 || pos >= len(h.entries):
+		h.pos = len(h.entries) - 1
+	default:
+		h.pos = pos
+	}
+	return h
+}
+
 // Push adds a new URL to the history, truncating any forward entries.
 func (h *History) Push(url string) {
 	// If we're not at the end, truncate forward history.
@@ -65,6 +79,26 @@ func (h *History) Len() int {
 	return len(h.entries)
 }
 
+// Entries returns a copy of all URLs in the stack, oldest first.
+func (h *History) Entries() []string {
+	return append([]string(nil), h.entries...)
+}
+
+// Position returns the index of the current entry, or -1 if empty.
+func (h *History) Position() int {
+	return h.pos
+}
+
+// Clone returns an independent copy of the history, including its position.
+func (h *History) Clone() *History {
+	entries := make([]string, len(h.entries))
+	copy(entries, h.entries)
+	return &History{
+		entries: entries,
+		pos:     h.pos,
+	}
+}
+
 // Clear resets the history.
 func (h *History) Clear() {
 	h.entries = nil
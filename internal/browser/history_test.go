@@ -0,0 +1,34 @@
+package browser
+
+import "testing"
+
+func TestNewHistoryFrom(t *testing.T) {
+	entries := []string{"https://a.test/", "https://b.test/", "https://c.test/"}
+	tests := []struct {
+		pos     int
+		current string
+	}{
+		{1, "https://b.test/"},
+		{-1, "https://c.test/"}, // out of range: the newest entry
+		{7, "https://c.test/"},
+	}
+	for _, tt := range tests {
+		h := NewHistoryFrom(entries, tt.pos)
+		if got := h.Current(); got != tt.current {
+			t.Errorf("NewHistoryFrom(pos %d).Current() = %q, want %q", tt.pos, got, tt.current)
+		}
+	}
+
+	h := NewHistoryFrom(entries, 1)
+	entries[0] = "https://changed.test/"
+	if url, ok := h.Back(); !ok || url != "https://a.test/" {
+		t.Errorf("Back = %q, %v; want the saved entry, not the caller's slice", url, ok)
+	}
+	if url, ok := h.Forward(); !ok || url != "https://b.test/" {
+		t.Errorf("Forward = %q, %v", url, ok)
+	}
+
+	if h := NewHistoryFrom(nil, 0); h.Current() != "" || h.CanGoBack() {
+		t.Errorf("empty history = %+v", h)
+	}
+}
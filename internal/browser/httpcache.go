@@ -0,0 +1,539 @@
+package browser
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxCacheSize    = 200 * 1024 * 1024 // 200 MB on disk
+	pruneDivisor    = 20                // prune after storing a 20th of the limit
+	maxHeuristicTTL = 24 * time.Hour
+
+	// CacheStatusHeader is set on responses served from the HTTP cache:
+	// "hit" when served without contacting the server, "revalidated" after a
+	// 304, and "offline" when served in offline mode.
+	CacheStatusHeader = "X-Tsurf-Cache"
+)
+
+// ErrNotCached is returned in offline mode for requests with no stored response.
+var ErrNotCached = errors.New("not available offline")
+
+// HTTPCache is a private, disk-backed HTTP cache. Each URL is stored as a
+// JSON metadata file and a raw body file named by the URL's SHA-256.
+type HTTPCache struct {
+	dir   string
+	limit int64 // bytes of bodies kept on disk
+
+	mu      sync.Mutex
+	written int64 // body bytes stored since the last prune
+}
+
+// cacheEntry is the metadata stored alongside a cached body.
+type cacheEntry struct {
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status"`
+	Header     http.Header `json:"header"`
+	StoredAt   time.Time   `json:"stored_at"`
+}
+
+// NewHTTPCache opens (creating if needed) a cache in dir and trims it to
+// the maximum size, dropping the least recently stored entries.
+func NewHTTPCache(dir string) (*HTTPCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating cache dir: %w", err)
+	}
+	c := &HTTPCache{dir: dir, limit: maxCacheSize}
+	c.prune(c.limit)
+	return c, nil
+}
+
+// Clear removes every cached response.
+func (c *HTTPCache) Clear() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("reading cache dir: %w", err)
+	}
+	for _, e := range entries {
+		os.Remove(filepath.Join(c.dir, e.Name()))
+	}
+	return nil
+}
+
+// StoredAt returns when the response for a URL was stored.
+func (c *HTTPCache) StoredAt(rawURL string) (time.Time, bool) {
+	metaPath, _ := c.paths(rawURL)
+	data, err := os.ReadFile(metaPath)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != rawURL {
+		return time.Time{}, false
+	}
+	return entry.StoredAt, true
+}
+
+// paths returns the metadata and body file paths for a URL.
+func (c *HTTPCache) paths(rawURL string) (meta, body string) {
+	sum := sha256.Sum256([]byte(rawURL))
+	name := filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+	return name + ".json", name + ".body"
+}
+
+// load returns the stored entry and body for a URL.
+func (c *HTTPCache) load(rawURL string) (*cacheEntry, []byte, bool) {
+	metaPath, bodyPath := c.paths(rawURL)
+
+	data, err := os.ReadFile(metaPath)
+	if err != nil {
+		return nil, nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != rawURL {
+		return nil, nil, false
+	}
+
+	body, err := os.ReadFile(bodyPath)
+	if err != nil {
+		return nil, nil, false
+	}
+	return &entry, body, true
+}
+
+// store writes an entry and its body. The body is written first so a
+// metadata file never points at a missing body.
+func (c *HTTPCache) store(entry *cacheEntry, body []byte) error {
+	metaPath, bodyPath := c.paths(entry.URL)
+
+	if body != nil {
+		if err := writeFileAtomic(bodyPath, body); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshaling cache entry: %w", err)
+	}
+	if err := writeFileAtomic(metaPath, data); err != nil {
+		return err
+	}
+	if body != nil {
+		c.stored(len(body))
+	}
+	return nil
+}
+
+// remove deletes the entry for a URL.
+func (c *HTTPCache) remove(rawURL string) {
+	metaPath, bodyPath := c.paths(rawURL)
+	os.Remove(metaPath)
+	os.Remove(bodyPath)
+}
+
+// stored counts n bytes of body written, and prunes the cache once a
+// twentieth of its limit has been written since the last time. Listing the
+// directory after every response would be wasteful.
+func (c *HTTPCache) stored(n int) {
+	c.mu.Lock()
+	c.written += int64(n)
+	due := c.written >= c.limit/pruneDivisor
+	if due {
+		c.written = 0
+	}
+	c.mu.Unlock()
+
+	if due {
+		c.prune(c.limit)
+	}
+}
+
+// prune deletes the oldest entries until the bodies fit within limit bytes.
+func (c *HTTPCache) prune(limit int64) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	type bodyFile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []bodyFile
+	var total int64
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".body") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, bodyFile{filepath.Join(c.dir, e.Name()), info.Size(), info.ModTime()})
+		total += info.Size()
+	}
+	if total <= limit {
+		return
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		if total <= limit {
+			break
+		}
+		os.Remove(f.path)
+		os.Remove(strings.TrimSuffix(f.path, ".body") + ".json")
+		total -= f.size
+	}
+}
+
+// writeFileAtomic writes data to a temp file and renames it into place.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("renaming cache file: %w", err)
+	}
+	return nil
+}
+
+// freshness returns how long the entry may be served without revalidation,
+// from Cache-Control max-age, Expires, or a Last-Modified heuristic.
+func (e *cacheEntry) freshness() time.Duration {
+	cc := parseCacheControl(e.Header)
+	if cc.has("no-cache") {
+		return 0
+	}
+	if v, ok := cc["max-age"]; ok {
+		secs, err := strconv.Atoi(v)
+		if err != nil {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+
+	date := e.StoredAt
+	if d, err := http.ParseTime(e.Header.Get("Date")); err == nil {
+		date = d
+	}
+	if exp := e.Header.Get("Expires"); exp != "" {
+		t, err := http.ParseTime(exp)
+		if err != nil {
+			return 0 // invalid Expires means already expired
+		}
+		return t.Sub(date)
+	}
+
+	// Heuristic: 10% of the time since the last modification, capped.
+	if lm, err := http.ParseTime(e.Header.Get("Last-Modified")); err == nil {
+		ttl := date.Sub(lm) / 10
+		if ttl > maxHeuristicTTL {
+			ttl = maxHeuristicTTL
+		}
+		return ttl
+	}
+	return 0
+}
+
+// age returns the current age of the entry, including any upstream Age.
+func (e *cacheEntry) age(now time.Time) time.Duration {
+	age := now.Sub(e.StoredAt)
+	if secs, err := strconv.Atoi(e.Header.Get("Age")); err == nil {
+		age += time.Duration(secs) * time.Second
+	}
+	return age
+}
+
+// fresh reports whether the entry can be served without revalidation.
+func (e *cacheEntry) fresh(now time.Time) bool {
+	return e.age(now) < e.freshness()
+}
+
+// update merges headers from a 304 response into the stored headers.
+func (e *cacheEntry) update(h http.Header) {
+	for k, v := range h {
+		switch k {
+		case "Content-Length", "Content-Encoding", "Transfer-Encoding", "Set-Cookie":
+			continue
+		}
+		e.Header[k] = v
+	}
+}
+
+// response builds an http.Response for req from the stored entry.
+func (e *cacheEntry) response(req *http.Request, body []byte, status string) *http.Response {
+	h := e.Header.Clone()
+	h.Set(CacheStatusHeader, status)
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// cacheControl holds parsed Cache-Control directives (lowercased names).
+type cacheControl map[string]string
+
+// parseCacheControl parses all Cache-Control headers in h.
+func parseCacheControl(h http.Header) cacheControl {
+	cc := cacheControl{}
+	for _, line := range h.Values("Cache-Control") {
+		for _, part := range strings.Split(line, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			name, val, _ := strings.Cut(part, "=")
+			cc[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(val), `"`)
+		}
+	}
+	return cc
+}
+
+// has reports whether the directive is present.
+func (cc cacheControl) has(directive string) bool {
+	_, ok := cc[directive]
+	return ok
+}
+
+// cacheable reports whether a response may be stored.
+func cacheable(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNonAuthoritativeInfo,
+		http.StatusMovedPermanently, http.StatusPermanentRedirect:
+	default:
+		return false
+	}
+	if parseCacheControl(resp.Header).has("no-store") {
+		return false
+	}
+	return resp.Header.Get("Vary") != "*"
+}
+
+// CachingTransport wraps a RoundTripper with an HTTP cache. Without a cache
+// set it passes requests straight through. In offline mode it never touches
+// the network and serves every GET from the cache, however stale.
+type CachingTransport struct {
+	Transport http.RoundTripper
+
+	mu      sync.RWMutex
+	cache   *HTTPCache
+	offline bool
+}
+
+// SetCache sets (or, with nil, removes) the cache used by the transport.
+func (t *CachingTransport) SetCache(c *HTTPCache) {
+	t.mu.Lock()
+	t.cache = c
+	t.mu.Unlock()
+}
+
+// Cache returns the current cache, or nil if caching is disabled.
+func (t *CachingTransport) Cache() *HTTPCache {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.cache
+}
+
+// SetOffline switches offline mode on or off.
+func (t *CachingTransport) SetOffline(offline bool) {
+	t.mu.Lock()
+	t.offline = offline
+	t.mu.Unlock()
+}
+
+// Offline reports whether offline mode is on.
+func (t *CachingTransport) Offline() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.offline
+}
+
+// RoundTrip implements http.RoundTripper. Fresh entries are served from disk;
+// stale ones are revalidated with If-None-Match/If-Modified-Since and reused
+// on 304.
+func (t *CachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cache := t.Cache()
+	if t.Offline() {
+		return t.offlineRoundTrip(cache, req)
+	}
+	if cache == nil || req.Method != http.MethodGet ||
+		req.Header.Get("Range") != "" || req.Header.Get("Authorization") != "" {
+		return t.Transport.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	reqCC := parseCacheControl(req.Header)
+	if reqCC.has("no-store") {
+		return t.Transport.RoundTrip(req)
+	}
+
+	entry, body, ok := cache.load(key)
+	if ok && !reqCC.has("no-cache") && entry.fresh(time.Now()) {
+		return entry.response(req, body, "hit"), nil
+	}
+
+	// Revalidate, unless the caller is already making a conditional request.
+	revalidating := false
+	if ok && req.Header.Get("If-None-Match") == "" && req.Header.Get("If-Modified-Since") == "" {
+		etag := entry.Header.Get("ETag")
+		lastModified := entry.Header.Get("Last-Modified")
+		if etag != "" || lastModified != "" {
+			req = req.Clone(req.Context())
+			if etag != "" {
+				req.Header.Set("If-None-Match", etag)
+			}
+			if lastModified != "" {
+				req.Header.Set("If-Modified-Since", lastModified)
+			}
+			revalidating = true
+		}
+	}
+
+	resp, err := t.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if revalidating && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		entry.update(resp.Header)
+		entry.StoredAt = time.Now()
+		cache.store(entry, nil)
+		return entry.response(req, body, "revalidated"), nil
+	}
+
+	if !cacheable(resp) {
+		if ok && parseCacheControl(resp.Header).has("no-store") {
+			cache.remove(key)
+		}
+		return resp, nil
+	}
+	return cache.tee(key, resp)
+}
+
+// offlineRoundTrip serves a request from the cache without using the network.
+func (t *CachingTransport) offlineRoundTrip(cache *HTTPCache, req *http.Request) (*http.Response, error) {
+	if cache != nil && req.Method == http.MethodGet {
+		if entry, body, ok := cache.load(req.URL.String()); ok {
+			if age, ok := req.Context().Value(offlineAgeKey{}).(*OfflineAge); ok {
+				age.served(entry.StoredAt)
+			}
+			return entry.response(req, body, "offline"), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotCached, req.URL)
+}
+
+// offlineAgeKey is the context key of an *OfflineAge.
+type offlineAgeKey struct{}
+
+// OfflineAge records when the oldest response served in offline mode to
+// the requests of one context was stored. Clients that make several
+// requests for a page (e.g. feed clients) use it as the page's age.
+type OfflineAge struct {
+	mu     sync.Mutex
+	oldest time.Time
+}
+
+// WithOfflineAge returns a context whose requests record their age in the
+// returned OfflineAge.
+func WithOfflineAge(ctx context.Context) (context.Context, *OfflineAge) {
+	age := &OfflineAge{}
+	return context.WithValue(ctx, offlineAgeKey{}, age), age
+}
+
+// Oldest returns when the oldest response served offline was stored, or
+// the zero time if none was.
+func (a *OfflineAge) Oldest() time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.oldest
+}
+
+// served records a response stored at storedAt.
+func (a *OfflineAge) served(storedAt time.Time) {
+	a.mu.Lock()
+	if a.oldest.IsZero() || storedAt.Before(a.oldest) {
+		a.oldest = storedAt
+	}
+	a.mu.Unlock()
+}
+
+// tee reads the response body, stores it, and returns the response with a
+// fresh reader over the same bytes. Bodies over maxBodySize are not stored.
+func (c *HTTPCache) tee(key string, resp *http.Response) (*http.Response, error) {
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	if len(data) > maxBodySize {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	h := resp.Header.Clone()
+	h.Del("Set-Cookie")
+	c.store(&cacheEntry{
+		URL:        key,
+		StatusCode: resp.StatusCode,
+		Header:     h,
+		StoredAt:   time.Now(),
+	}, data)
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	return resp, nil
+}
+
+// EnableHTTPCache turns on the disk cache in dir for every client using
+// SharedTransport.
+func EnableHTTPCache(dir string) error {
+	c, err := NewHTTPCache(dir)
+	if err != nil {
+		return err
+	}
+	SharedTransport.SetCache(c)
+	return nil
+}
@@ -0,0 +1,219 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// newCachingClient returns a client with its own cache in a temp dir.
+func newCachingClient(t *testing.T) *http.Client {
+	cache, err := NewHTTPCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := &CachingTransport{Transport: http.DefaultTransport}
+	tr.SetCache(cache)
+	return &http.Client{Transport: tr}
+}
+
+// get fetches url and returns the body and cache status header.
+func get(t *testing.T, client *http.Client, url string) (string, string) {
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body), resp.Header.Get(CacheStatusHeader)
+}
+
+func TestHTTPCacheMaxAge(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+
+	client := newCachingClient(t)
+	get(t, client, srv.URL)
+	body, status := get(t, client, srv.URL)
+
+	if hits != 1 {
+		t.Errorf("expected 1 server hit, got %d", hits)
+	}
+	if body != "hello" || status != "hit" {
+		t.Errorf("got body %q status %q, want %q %q", body, status, "hello", "hit")
+	}
+}
+
+func TestHTTPCacheRevalidatesWithETag(t *testing.T) {
+	hits, notModified := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte("body v1"))
+	}))
+	defer srv.Close()
+
+	client := newCachingClient(t)
+	get(t, client, srv.URL)
+	body, status := get(t, client, srv.URL)
+
+	if hits != 2 || notModified != 1 {
+		t.Errorf("expected 2 hits with 1 revalidation, got %d hits, %d 304s", hits, notModified)
+	}
+	if body != "body v1" || status != "revalidated" {
+		t.Errorf("got body %q status %q, want %q %q", body, status, "body v1", "revalidated")
+	}
+}
+
+func TestHTTPCacheNoStore(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Cache-Control", "no-store, max-age=60")
+		w.Write([]byte("secret"))
+	}))
+	defer srv.Close()
+
+	client := newCachingClient(t)
+	get(t, client, srv.URL)
+	get(t, client, srv.URL)
+
+	if hits != 2 {
+		t.Errorf("no-store response should not be cached, got %d hits", hits)
+	}
+}
+
+func TestCacheEntryFreshness(t *testing.T) {
+	tests := []struct {
+		header http.Header
+		fresh  bool
+	}{
+		{http.Header{"Cache-Control": {"max-age=3600"}}, true},
+		{http.Header{"Cache-Control": {"max-age=0"}}, false},
+		{http.Header{"Cache-Control": {"no-cache, max-age=3600"}}, false},
+		{http.Header{"Expires": {"Thu, 01 Jan 1970 00:00:00 GMT"}}, false},
+		{http.Header{"Last-Modified": {"Thu, 01 Jan 1970 00:00:00 GMT"}}, true},
+		{http.Header{}, false},
+	}
+
+	for _, tt := range tests {
+		e := &cacheEntry{Header: tt.header, StoredAt: time.Now()}
+		if got := e.fresh(e.StoredAt); got != tt.fresh {
+			t.Errorf("fresh(%v) = %v, want %v", tt.header, got, tt.fresh)
+		}
+	}
+}
+
+func TestHTTPCacheOffline(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Cache-Control", "max-age=0")
+		w.Write([]byte("stale but stored"))
+	}))
+	defer srv.Close()
+
+	client := newCachingClient(t)
+	get(t, client, srv.URL+"/page")
+	client.Transport.(*CachingTransport).SetOffline(true)
+
+	body, status := get(t, client, srv.URL+"/page")
+	if hits != 1 {
+		t.Errorf("offline request reached the server (%d hits)", hits)
+	}
+	if body != "stale but stored" || status != "offline" {
+		t.Errorf("got body %q status %q, want stored body with status %q", body, status, "offline")
+	}
+
+	_, err := client.Get(srv.URL + "/missing")
+	if !errors.Is(err, ErrNotCached) {
+		t.Errorf("expected ErrNotCached for uncached URL, got %v", err)
+	}
+}
+
+func TestOfflineAge(t *testing.T) {
+	cache, err := NewHTTPCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := &CachingTransport{Transport: http.DefaultTransport}
+	tr.SetCache(cache)
+	tr.SetOffline(true)
+	client := &http.Client{Transport: tr}
+
+	older := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	for url, storedAt := range map[string]time.Time{
+		"https://example.test/a": older,
+		"https://example.test/b": time.Now().Add(-time.Hour),
+	} {
+		entry := &cacheEntry{URL: url, StatusCode: http.StatusOK, Header: http.Header{}, StoredAt: storedAt}
+		if err := cache.store(entry, []byte("x")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, age := WithOfflineAge(context.Background())
+	for _, url := range []string{"https://example.test/b", "https://example.test/a"} {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if got := age.Oldest(); !got.Equal(older) {
+		t.Errorf("Oldest() = %v, want %v", got, older)
+	}
+
+	// Requests without the context leave it alone.
+	_, other := WithOfflineAge(context.Background())
+	get(t, client, "https://example.test/a")
+	if !other.Oldest().IsZero() {
+		t.Errorf("unrelated OfflineAge recorded %v", other.Oldest())
+	}
+}
+
+func TestHTTPCachePrunesAfterWrites(t *testing.T) {
+	cache, err := NewHTTPCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.limit = 100 * pruneDivisor
+
+	// Each body is half the limit: the first two fit, the third pushes the
+	// oldest out once enough has been written to prune.
+	body := make([]byte, cache.limit/2)
+	for i, url := range []string{"https://a.test/", "https://b.test/", "https://c.test/"} {
+		entry := &cacheEntry{URL: url, StatusCode: http.StatusOK, Header: http.Header{}, StoredAt: time.Now()}
+		if err := cache.store(entry, body); err != nil {
+			t.Fatal(err)
+		}
+		// Keep the modification times apart so the oldest is clear.
+		_, bodyPath := cache.paths(url)
+		old := time.Now().Add(time.Duration(i-3) * time.Minute)
+		os.Chtimes(bodyPath, old, old)
+	}
+
+	if _, _, ok := cache.load("https://a.test/"); ok {
+		t.Error("oldest entry kept over the limit")
+	}
+	if _, _, ok := cache.load("https://c.test/"); !ok {
+		t.Error("newest entry pruned")
+	}
+}
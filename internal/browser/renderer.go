@@ -2,12 +2,15 @@ package browser
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/vidyasagar/tsurf/internal/theme"
 )
 
@@ -15,6 +18,7 @@ import (
 var (
 	cachedRenderer      *glamour.TermRenderer
 	cachedRendererWidth int
+	cachedRendererTheme string
 	rendererMu          sync.Mutex
 )
 
@@ -23,6 +27,135 @@ type RenderedPage struct {
 	Title   string
 	Content string // styled terminal text
 	Links   []Link
+	Refs    []LinkRef // where the links' [N] references are in Content
+	Forms   []*Form   // this page's own copy, filled in by the user
+
+	body   string // Content with the forms' marks in place of their fields
+	width  int
+	forms  []*Form // the article's forms shown on the page, as they came
+	edited bool
+}
+
+// LinkRef is where a link's [N] reference is on a rendered page.
+type LinkRef struct {
+	Link  int // the link number N
+	Line  int
+	Start int // first cell of the reference
+	End   int // one past its last cell
+}
+
+// linkRefMark stands in for the "[" of the link references while a page is
+// rendered, so that they can be found afterwards without mistaking "[N]" in
+// the page's text, like a footnote mark, for one.
+const linkRefMark = "\U0010FFFD"
+
+var linkRefMarkRe = regexp.MustCompile(linkRefMark + `(\d+)\]`)
+
+// formMark, followed by the form's mark number, takes the line of a form
+// while a page is rendered. The line is then replaced by the form's fields.
+const formMark = "\U0010FFFC"
+
+var formMarkRe = regexp.MustCompile(formMark + `(\d+)`)
+
+// locateLinkRefs finds the marked link references in rendered text and
+// turns them back into plain [N]. Returns the text and where they are.
+func locateLinkRefs(rendered string) (string, []LinkRef) {
+	var refs []LinkRef
+	for i, line := range strings.Split(rendered, "\n") {
+		if !strings.Contains(line, linkRefMark) {
+			continue
+		}
+		plain := ansi.Strip(line)
+		for _, loc := range linkRefMarkRe.FindAllStringSubmatchIndex(plain, -1) {
+			digits := plain[loc[2]:loc[3]]
+			n, err := strconv.Atoi(digits)
+			if err != nil {
+				continue
+			}
+			start := ansi.StringWidth(plain[:loc[0]])
+			refs = append(refs, LinkRef{
+				Link:  n,
+				Line:  i,
+				Start: start,
+				End:   start + len("["+digits+"]"),
+			})
+		}
+	}
+	return strings.ReplaceAll(rendered, linkRefMark, "["), refs
+}
+
+// RefreshForms re-renders the page's forms after their values change.
+func (p *RenderedPage) RefreshForms() {
+	p.edited = true
+	p.Content = placeForms(p.body, p.Forms, p.width)
+}
+
+// Edited reports whether any of the page's form values have been changed.
+func (p *RenderedPage) Edited() bool {
+	return p.edited
+}
+
+// Clone returns a copy of the page as it was rendered, with its own copy of
+// the forms. Values typed into p's fields are not carried over, so a page
+// shared through the cache or a split never shows another tab's input.
+func (p *RenderedPage) Clone() *RenderedPage {
+	c := *p
+	c.Forms = cloneForms(p.forms)
+	c.edited = false
+	if len(c.Forms) > 0 {
+		c.Content = placeForms(c.body, c.Forms, c.width)
+	}
+	return &c
+}
+
+// withForms fills in a page from its rendered text: the article's forms
+// marked in it are shown in place, with a copy of their own, and the link
+// references are located in the result. Forms the text does not show are
+// left out.
+func withForms(p *RenderedPage, rendered string, article *Article, width int) *RenderedPage {
+	marked := make(map[int]bool)
+	for _, m := range formMarkRe.FindAllStringSubmatch(ansi.Strip(rendered), -1) {
+		n, _ := strconv.Atoi(m[1])
+		marked[n] = true
+	}
+	for _, f := range article.Forms {
+		if marked[f.mark] {
+			p.forms = append(p.forms, f)
+		}
+	}
+
+	p.Forms = cloneForms(p.forms)
+	p.width = width
+	p.Content, p.Refs = locateLinkRefs(placeForms(rendered, p.Forms, width))
+	p.body = strings.ReplaceAll(rendered, linkRefMark, "[")
+	return p
+}
+
+// placeForms replaces each form mark line in body by the form's fields. The
+// fields take one line each, so the lines below move by the same amount
+// whatever the forms' values.
+func placeForms(body string, forms []*Form, width int) string {
+	if !strings.Contains(body, formMark) {
+		return body
+	}
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if !strings.Contains(line, formMark) {
+			continue
+		}
+		lines[i] = ""
+		m := formMarkRe.FindStringSubmatch(ansi.Strip(line))
+		if m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+		for _, f := range forms {
+			if f.mark == n {
+				lines[i] = strings.TrimSuffix(RenderForms([]*Form{f}, width), "\n")
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
 }
 
 // Render converts an Article's HTML content into styled terminal text.
@@ -37,7 +170,7 @@ func Render(article *Article, width int) *RenderedPage {
 		contentWidth = 100
 	}
 
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(article.Content))
+	md, links, err := convertMarkdown(article, true)
 	if err != nil {
 		return &RenderedPage{
 			Title:   article.Title,
@@ -46,10 +179,42 @@ func Render(article *Article, width int) *RenderedPage {
 		}
 	}
 
+	// Render markdown with glamour.
+	rendered, glamErr := renderWithGlamour(md, contentWidth)
+	if glamErr != nil {
+		// Fallback: use the raw markdown.
+		rendered = md
+	}
+
+	return withForms(&RenderedPage{
+		Title: article.Title,
+		Links: links,
+	}, rendered, article, contentWidth)
+}
+
+// Markdown converts an Article's HTML content to Markdown, headed by its
+// title and byline, with each link followed by its reference number.
+func Markdown(article *Article) (string, []Link, error) {
+	return convertMarkdown(article, false)
+}
+
+// convertMarkdown converts an Article to Markdown. For rendering, the link
+// references start with linkRefMark and each form is marked with formMark.
+func convertMarkdown(article *Article, rendering bool) (string, []Link, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(article.Content))
+	if err != nil {
+		return "", nil, err
+	}
+
 	// Convert HTML to markdown, collecting links along the way.
 	conv := &mdConverter{
 		linkIndex: 0,
 		links:     nil,
+		refOpen:   "[",
+		markForms: rendering,
+	}
+	if rendering {
+		conv.refOpen = linkRefMark
 	}
 
 	var md strings.Builder
@@ -71,18 +236,7 @@ func Render(article *Article, width int) *RenderedPage {
 		md.WriteString(conv.convertNode(s, 0))
 	})
 
-	// Render markdown with glamour.
-	rendered, glamErr := renderWithGlamour(md.String(), contentWidth)
-	if glamErr != nil {
-		// Fallback: use the raw markdown.
-		rendered = md.String()
-	}
-
-	return &RenderedPage{
-		Title:   article.Title,
-		Content: rendered,
-		Links:   conv.links,
-	}
+	return md.String(), conv.links, nil
 }
 
 // renderWithGlamour uses glamour to render markdown into styled terminal output.
@@ -91,10 +245,10 @@ func renderWithGlamour(markdown string, width int) (string, error) {
 	rendererMu.Lock()
 	defer rendererMu.Unlock()
 
-	// Recreate renderer only if width changed or not initialized.
-	if cachedRenderer == nil || cachedRendererWidth != width {
+	// Recreate renderer only if width or theme changed or not initialized.
+	if cachedRenderer == nil || cachedRendererWidth != width || cachedRendererTheme != theme.Current.Name {
 		renderer, err := glamour.NewTermRenderer(
-			glamour.WithAutoStyle(),
+			glamour.WithStyles(GlamourStyle()),
 			glamour.WithWordWrap(width),
 		)
 		if err != nil {
@@ -102,6 +256,7 @@ func renderWithGlamour(markdown string, width int) (string, error) {
 		}
 		cachedRenderer = renderer
 		cachedRendererWidth = width
+		cachedRendererTheme = theme.Current.Name
 	}
 
 	out, err := cachedRenderer.Render(markdown)
@@ -116,11 +271,22 @@ func renderWithGlamour(markdown string, width int) (string, error) {
 type mdConverter struct {
 	linkIndex int
 	links     []Link
+	refOpen   string // "[", or linkRefMark while rendering
+	markForms bool   // put formMark lines where the forms are
 }
 
 func (c *mdConverter) convertNode(s *goquery.Selection, depth int) string {
 	var sb strings.Builder
 
+	// Readability may have renamed a form, but its mark stays. Its fields are
+	// shown in place of its content.
+	if mark, ok := s.Attr(formAttr); ok {
+		if c.markForms {
+			return formMark + mark + "\n\n"
+		}
+		return ""
+	}
+
 	tagName := goquery.NodeName(s)
 
 	switch tagName {
@@ -166,7 +332,7 @@ func (c *mdConverter) convertNode(s *goquery.Selection, depth int) string {
 		sb.WriteString("*")
 		c.convertInlineChildren(s, &sb)
 		sb.WriteString("*")
-	case "div", "article", "section", "main", "header", "footer", "figure", "span":
+	case "div", "article", "section", "main", "header", "footer", "figure", "span", "nav", "aside":
 		s.Children().Each(func(i int, child *goquery.Selection) {
 			sb.WriteString(c.convertNode(child, depth))
 		})
@@ -251,7 +417,7 @@ func (c *mdConverter) convertLink(s *goquery.Selection) string {
 	})
 
 	// Return markdown link with numbered reference.
-	return fmt.Sprintf("[%s](%s) **[%d]**", text, href, c.linkIndex)
+	return fmt.Sprintf("[%s](%s) **%s%d]**", text, href, c.refOpen, c.linkIndex)
 }
 
 func (c *mdConverter) convertList(s *goquery.Selection, ordered bool, depth int) string {
@@ -304,18 +470,6 @@ func (c *mdConverter) convertBlockquote(s *goquery.Selection) string {
 func (c *mdConverter) convertCodeBlock(s *goquery.Selection) string {
 	code := s.Find("code")
 
-	// Try to detect language from class.
-	lang := ""
-	if code.Length() > 0 {
-		class, _ := code.Attr("class")
-		if strings.Contains(class, "language-") {
-			parts := strings.Split(class, "language-")
-			if len(parts) > 1 {
-				lang = strings.Fields(parts[1])[0]
-			}
-		}
-	}
-
 	text := ""
 	if code.Length() > 0 {
 		text = code.Text()
@@ -323,9 +477,24 @@ func (c *mdConverter) convertCodeBlock(s *goquery.Selection) string {
 		text = s.Text()
 	}
 
+	// Detect the language from the class names, or guess it from the code.
+	lang := DetectLanguage(codeClasses(s), text)
+
 	return "```" + lang + "\n" + text + "\n```\n\n"
 }
 
+// codeClasses collects the class names of a <pre>, its <code> and its parent,
+// where sites put the language of a code block.
+func codeClasses(pre *goquery.Selection) string {
+	var classes []string
+	for _, s := range []*goquery.Selection{pre, pre.Find("code").First(), pre.Parent()} {
+		if class, ok := s.Attr("class"); ok {
+			classes = append(classes, class)
+		}
+	}
+	return strings.Join(classes, " ")
+}
+
 func (c *mdConverter) convertInlineCode(s *goquery.Selection) string {
 	return "`" + s.Text() + "`"
 }
@@ -461,11 +630,10 @@ func RenderFallback(article *Article, width int) *RenderedPage {
 		sb.WriteString(r.renderNode(s))
 	})
 
-	return &RenderedPage{
-		Title:   article.Title,
-		Content: sb.String(),
-		Links:   r.links,
-	}
+	return withForms(&RenderedPage{
+		Title: article.Title,
+		Links: r.links,
+	}, sb.String(), article, contentWidth)
 }
 
 type fallbackRenderer struct {
@@ -477,6 +645,10 @@ type fallbackRenderer struct {
 func (r *fallbackRenderer) renderNode(s *goquery.Selection) string {
 	var sb strings.Builder
 
+	if mark, ok := s.Attr(formAttr); ok {
+		return formMark + mark + "\n\n"
+	}
+
 	tagName := goquery.NodeName(s)
 
 	switch tagName {
@@ -623,7 +795,7 @@ func (r *fallbackRenderer) renderLink(s *goquery.Selection) string {
 		Foreground(theme.Current.LinkIndex).
 		Bold(true)
 
-	return linkStyle.Render(text) + indexStyle.Render(fmt.Sprintf(" [%d]", r.linkIndex))
+	return linkStyle.Render(text) + indexStyle.Render(fmt.Sprintf(" %s%d]", linkRefMark, r.linkIndex))
 }
 
 func (r *fallbackRenderer) renderList(s *goquery.Selection, ordered bool) string {
@@ -677,6 +849,14 @@ func (r *fallbackRenderer) renderCodeBlock(s *goquery.Selection) string {
 		code = s.Text()
 	}
 
+	if lang := DetectLanguage(codeClasses(s), code); lang != "" {
+		var sb strings.Builder
+		for _, line := range strings.Split(HighlightCode(code, lang), "\n") {
+			sb.WriteString("  " + line + "\n")
+		}
+		return sb.String() + "\n"
+	}
+
 	codeStyle := lipgloss.NewStyle().
 		Foreground(theme.Current.Code).
 		Background(theme.Current.CodeBg).
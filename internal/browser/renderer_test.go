@@ -2,7 +2,10 @@ package browser
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/charmbracelet/x/ansi"
 )
 
 func TestRenderBasicHTML(t *testing.T) {
@@ -100,3 +103,55 @@ func TestRenderWithTable(t *testing.T) {
 		t.Error("Content should not be empty")
 	}
 }
+
+func TestExtractFullKeepsPage(t *testing.T) {
+	result := &FetchResult{
+		URL:         "https://example.com/docs/",
+		FinalURL:    "https://example.com/docs/",
+		ContentType: "text/html",
+		Body: []byte(`<html><head><title>Docs</title><script>track()</script></head>
+<body><nav><a href="../">Home</a></nav><p>See <a href="intro">the intro</a>.</p></body></html>`),
+	}
+
+	article, err := ExtractFull(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md, links, err := Markdown(article)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Title != "Docs" || strings.Contains(md, "track()") {
+		t.Errorf("title %q, markdown:\n%s", article.Title, md)
+	}
+	if len(links) != 2 || links[0].URL != "https://example.com/" || links[1].URL != "https://example.com/docs/intro" {
+		t.Errorf("links = %+v, want the nav link and the resolved intro link", links)
+	}
+}
+
+func TestRenderRecordsLinkRefs(t *testing.T) {
+	article := &Article{
+		Content: `<p>As noted [1] and [2], see <a href="https://example.com/a">the notes</a>.</p>
+<p>Also <a href="https://example.com/b">this</a>.</p>`,
+	}
+
+	for name, page := range map[string]*RenderedPage{
+		"glamour":  Render(article, 80),
+		"fallback": RenderFallback(article, 80),
+	} {
+		if strings.Contains(page.Content, linkRefMark) {
+			t.Errorf("%s: mark left in the content", name)
+		}
+		if len(page.Refs) != 2 {
+			t.Fatalf("%s: Refs = %+v, want 2", name, page.Refs)
+		}
+		lines := strings.Split(ansi.Strip(page.Content), "\n")
+		for i, ref := range page.Refs {
+			line := lines[ref.Line]
+			want := fmt.Sprintf("[%d]", i+1)
+			if ref.Link != i+1 || ansi.Cut(line, ref.Start, ref.End) != want {
+				t.Errorf("%s: ref %+v points at %q in %q, want %s", name, ref, ansi.Cut(line, ref.Start, ref.End), line, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package browser
+
+import (
+	"github.com/charmbracelet/glamour/ansi"
+	glamourstyles "github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vidyasagar/tsurf/internal/theme"
+)
+
+// GlamourStyle returns the glamour style for the active theme.
+func GlamourStyle() ansi.StyleConfig {
+	return StyleConfig(theme.Current)
+}
+
+// StyleConfig generates a glamour style from a theme. Layout (margins, list
+// bullets, heading prefixes) follows glamour's dark style; every color comes
+// from the theme, and code blocks are highlighted with its chroma style.
+func StyleConfig(t theme.Theme) ansi.StyleConfig {
+	cfg := glamourstyles.DarkStyleConfig
+
+	cfg.Document.Color = color(t.Text)
+
+	cfg.Heading.Color = color(t.Heading)
+	cfg.Heading.Bold = boolPtr(true)
+	cfg.H1.Color = color(t.Background)
+	cfg.H1.BackgroundColor = color(t.Heading)
+	cfg.H6.Color = color(t.TextDim)
+
+	cfg.Strong.Color = color(t.TextBright)
+	cfg.HorizontalRule.Color = color(t.Border)
+
+	cfg.BlockQuote.Color = color(t.Quote)
+	cfg.BlockQuote.Italic = boolPtr(true)
+
+	cfg.Link.Color = color(t.Link)
+	cfg.LinkText.Color = color(t.Link)
+	cfg.Image.Color = color(t.Secondary)
+	cfg.ImageText.Color = color(t.TextDim)
+
+	cfg.Code.Color = color(t.Code)
+	cfg.Code.BackgroundColor = color(t.CodeBg)
+
+	chromaStyle(t)
+	cfg.CodeBlock.Color = color(t.Code)
+	cfg.CodeBlock.Chroma = nil
+	cfg.CodeBlock.Theme = ChromaStyleName(t)
+
+	cfg.Table.Color = color(t.Text)
+	cfg.DefinitionTerm.Color = color(t.Heading)
+
+	return cfg
+}
+
+// color converts a theme color for a glamour style.
+func color(c lipgloss.Color) *string {
+	s := string(c)
+	return &s
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
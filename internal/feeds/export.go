@@ -0,0 +1,34 @@
+package feeds
+
+import "github.com/vidyasagar/tsurf/internal/browser"
+
+// Page is a feed page rendered for the viewport, along with the items it
+// was rendered from.
+type Page struct {
+	Content string
+	Title   string
+	Links   []browser.Link
+	Data    *Data
+}
+
+// Data is the JSON export of a feed page: the items as fetched from the
+// site's API rather than their rendered text. Kind tells tools how to read
+// Items, e.g. "hn" holds []HNStory and "github_issue" a GitHubIssue.
+type Data struct {
+	Kind  string `json:"kind"`
+	Title string `json:"title"`
+	URL   string `json:"url,omitempty"`
+	Items any    `json:"items"`
+}
+
+// GitHubRepoPage is the data behind a rendered repository page.
+type GitHubRepoPage struct {
+	Repo   *GitHubRepo `json:"repo"`
+	Readme string      `json:"readme,omitempty"`
+}
+
+// GitHubUserPage is the data behind a rendered user or organization page.
+type GitHubUserPage struct {
+	User  *GitHubUser  `json:"user"`
+	Repos []GitHubRepo `json:"repos"`
+}
@@ -1,6 +1,7 @@
 package feeds
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/alecthomas/chroma/v2/lexers"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/vidyasagar/tsurf/internal/browser"
@@ -304,8 +306,8 @@ func NewGitHubClient() *GitHubClient {
 }
 
 // doRequest performs an authenticated GitHub API request.
-func (g *GitHubClient) doRequest(url string) ([]byte, error) {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+func (g *GitHubClient) doRequest(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
@@ -334,9 +336,9 @@ func (g *GitHubClient) doRequest(url string) ([]byte, error) {
 }
 
 // FetchRepo fetches repository information.
-func (g *GitHubClient) FetchRepo(owner, repo string) (*GitHubRepo, error) {
+func (g *GitHubClient) FetchRepo(ctx context.Context, owner, repo string) (*GitHubRepo, error) {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, repo)
-	body, err := g.doRequest(url)
+	body, err := g.doRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -349,9 +351,9 @@ func (g *GitHubClient) FetchRepo(owner, repo string) (*GitHubRepo, error) {
 }
 
 // FetchReadme fetches and decodes the repository README.
-func (g *GitHubClient) FetchReadme(owner, repo string) (string, error) {
+func (g *GitHubClient) FetchReadme(ctx context.Context, owner, repo string) (string, error) {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/readme", owner, repo)
-	body, err := g.doRequest(url)
+	body, err := g.doRequest(ctx, url)
 	if err != nil {
 		// README not found is not an error, just return empty
 		return "", nil
@@ -374,9 +376,9 @@ func (g *GitHubClient) FetchReadme(owner, repo string) (string, error) {
 }
 
 // FetchIssue fetches an issue.
-func (g *GitHubClient) FetchIssue(owner, repo string, number int) (*GitHubIssue, error) {
+func (g *GitHubClient) FetchIssue(ctx context.Context, owner, repo string, number int) (*GitHubIssue, error) {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d", owner, repo, number)
-	body, err := g.doRequest(url)
+	body, err := g.doRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -389,9 +391,9 @@ func (g *GitHubClient) FetchIssue(owner, repo string, number int) (*GitHubIssue,
 }
 
 // FetchPR fetches a pull request.
-func (g *GitHubClient) FetchPR(owner, repo string, number int) (*GitHubPR, error) {
+func (g *GitHubClient) FetchPR(ctx context.Context, owner, repo string, number int) (*GitHubPR, error) {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", owner, repo, number)
-	body, err := g.doRequest(url)
+	body, err := g.doRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -404,9 +406,9 @@ func (g *GitHubClient) FetchPR(owner, repo string, number int) (*GitHubPR, error
 }
 
 // FetchGist fetches a gist.
-func (g *GitHubClient) FetchGist(id string) (*GitHubGist, error) {
+func (g *GitHubClient) FetchGist(ctx context.Context, id string) (*GitHubGist, error) {
 	url := fmt.Sprintf("https://api.github.com/gists/%s", id)
-	body, err := g.doRequest(url)
+	body, err := g.doRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -419,9 +421,9 @@ func (g *GitHubClient) FetchGist(id string) (*GitHubGist, error) {
 }
 
 // FetchUser fetches a user profile.
-func (g *GitHubClient) FetchUser(username string) (*GitHubUser, error) {
+func (g *GitHubClient) FetchUser(ctx context.Context, username string) (*GitHubUser, error) {
 	url := fmt.Sprintf("https://api.github.com/users/%s", username)
-	body, err := g.doRequest(url)
+	body, err := g.doRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -434,12 +436,12 @@ func (g *GitHubClient) FetchUser(username string) (*GitHubUser, error) {
 }
 
 // FetchUserRepos fetches a user's public repositories.
-func (g *GitHubClient) FetchUserRepos(username string, limit int) ([]GitHubRepo, error) {
+func (g *GitHubClient) FetchUserRepos(ctx context.Context, username string, limit int) ([]GitHubRepo, error) {
 	if limit <= 0 || limit > 30 {
 		limit = 10
 	}
 	url := fmt.Sprintf("https://api.github.com/users/%s/repos?sort=updated&per_page=%d", username, limit)
-	body, err := g.doRequest(url)
+	body, err := g.doRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -743,8 +745,16 @@ func RenderGist(gist *GitHubGist, width int) (string, []browser.Link) {
 		sb.WriteString("\n\n")
 
 		if file.Content != "" {
-			// Render content (could be code or markdown)
-			lines := strings.Split(file.Content, "\n")
+			// Highlight code by the language GitHub detected, or guess it.
+			content := file.Content
+			lang := strings.ToLower(file.Language)
+			if lexers.Get(lang) == nil {
+				lang = browser.DetectLanguage("", content)
+			}
+			if lang != "" && lang != "markdown" && lang != "text" {
+				content = browser.HighlightCode(content, lang)
+			}
+			lines := strings.Split(content, "\n")
 			maxLines := 50 // Limit displayed lines
 			for i, line := range lines {
 				if i >= maxLines {
@@ -866,40 +876,44 @@ func RenderUser(user *GitHubUser, repos []GitHubRepo, width int) (string, []brow
 }
 
 // FetchURL auto-detects a GitHub URL type and fetches/renders it.
-func (g *GitHubClient) FetchURL(info *GitHubURLInfo, width int) (string, string, []browser.Link, error) {
+func (g *GitHubClient) FetchURL(ctx context.Context, info *GitHubURLInfo, width int) (*Page, error) {
+	data := func(kind, title string, items any) *Data {
+		return &Data{Kind: kind, Title: title, URL: info.OrigURL, Items: items}
+	}
+
 	switch info.Type {
 	case GitHubURLRepo:
-		repo, err := g.FetchRepo(info.Owner, info.Repo)
+		repo, err := g.FetchRepo(ctx, info.Owner, info.Repo)
 		if err != nil {
-			return "", "", nil, err
+			return nil, err
 		}
-		readme, _ := g.FetchReadme(info.Owner, info.Repo) // Ignore readme errors
+		readme, _ := g.FetchReadme(ctx, info.Owner, info.Repo) // Ignore readme errors
 		content, links := RenderRepo(repo, readme, width)
 		title := fmt.Sprintf("%s/%s - GitHub", repo.Owner.Login, repo.Name)
-		return content, title, links, nil
+		return &Page{content, title, links, data("github_repo", repo.FullName, GitHubRepoPage{repo, readme})}, nil
 
 	case GitHubURLIssue:
-		issue, err := g.FetchIssue(info.Owner, info.Repo, info.Number)
+		issue, err := g.FetchIssue(ctx, info.Owner, info.Repo, info.Number)
 		if err != nil {
-			return "", "", nil, err
+			return nil, err
 		}
 		content, links := RenderIssue(issue, info.Owner, info.Repo, width)
 		title := fmt.Sprintf("#%d: %s", issue.Number, truncate(issue.Title, 40))
-		return content, title, links, nil
+		return &Page{content, title, links, data("github_issue", issue.Title, issue)}, nil
 
 	case GitHubURLPR:
-		pr, err := g.FetchPR(info.Owner, info.Repo, info.Number)
+		pr, err := g.FetchPR(ctx, info.Owner, info.Repo, info.Number)
 		if err != nil {
-			return "", "", nil, err
+			return nil, err
 		}
 		content, links := RenderPR(pr, info.Owner, info.Repo, width)
 		title := fmt.Sprintf("PR #%d: %s", pr.Number, truncate(pr.Title, 40))
-		return content, title, links, nil
+		return &Page{content, title, links, data("github_pr", pr.Title, pr)}, nil
 
 	case GitHubURLGist:
-		gist, err := g.FetchGist(info.GistID)
+		gist, err := g.FetchGist(ctx, info.GistID)
 		if err != nil {
-			return "", "", nil, err
+			return nil, err
 		}
 		content, links := RenderGist(gist, width)
 		desc := gist.Description
@@ -907,24 +921,24 @@ func (g *GitHubClient) FetchURL(info *GitHubURLInfo, width int) (string, string,
 			desc = "Gist"
 		}
 		title := fmt.Sprintf("Gist: %s", truncate(desc, 40))
-		return content, title, links, nil
+		return &Page{content, title, links, data("github_gist", desc, gist)}, nil
 
 	case GitHubURLUser:
-		user, err := g.FetchUser(info.User)
+		user, err := g.FetchUser(ctx, info.User)
 		if err != nil {
-			return "", "", nil, err
+			return nil, err
 		}
-		repos, _ := g.FetchUserRepos(info.User, 10) // Ignore repo fetch errors
+		repos, _ := g.FetchUserRepos(ctx, info.User, 10) // Ignore repo fetch errors
 		content, links := RenderUser(user, repos, width)
 		displayName := user.Login
 		if user.Name != "" {
 			displayName = user.Name
 		}
 		title := fmt.Sprintf("%s - GitHub", displayName)
-		return content, title, links, nil
+		return &Page{content, title, links, data("github_user", displayName, GitHubUserPage{user, repos})}, nil
 
 	default:
-		return "", "", nil, fmt.Errorf("unsupported GitHub URL type")
+		return nil, fmt.Errorf("unsupported GitHub URL type")
 	}
 }
 
@@ -936,13 +950,13 @@ func renderMarkdown(content string, width int) (string, error) {
 		width = 40
 	}
 	r, err := glamour.NewTermRenderer(
-		glamour.WithAutoStyle(),
+		glamour.WithStyles(browser.GlamourStyle()),
 		glamour.WithWordWrap(width),
 	)
 	if err != nil {
 		return "", err
 	}
-	return r.Render(content)
+	return r.Render(browser.AnnotateCodeFences(content))
 }
 
 // repoIcon returns an icon for a repository.
@@ -4,9 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -64,32 +68,32 @@ func NewHNClient() *HNClient {
 }
 
 // TopStories fetches the top stories.
-func (h *HNClient) TopStories(limit int) ([]HNStory, error) {
-	return h.fetchStories("topstories", limit)
+func (h *HNClient) TopStories(ctx context.Context, limit int) ([]HNStory, error) {
+	return h.fetchStories(ctx, "topstories", limit)
 }
 
 // NewStories fetches the newest stories.
-func (h *HNClient) NewStories(limit int) ([]HNStory, error) {
-	return h.fetchStories("newstories", limit)
+func (h *HNClient) NewStories(ctx context.Context, limit int) ([]HNStory, error) {
+	return h.fetchStories(ctx, "newstories", limit)
 }
 
 // BestStories fetches the best stories.
-func (h *HNClient) BestStories(limit int) ([]HNStory, error) {
-	return h.fetchStories("beststories", limit)
+func (h *HNClient) BestStories(ctx context.Context, limit int) ([]HNStory, error) {
+	return h.fetchStories(ctx, "beststories", limit)
 }
 
 // AskStories fetches Ask HN stories.
-func (h *HNClient) AskStories(limit int) ([]HNStory, error) {
-	return h.fetchStories("askstories", limit)
+func (h *HNClient) AskStories(ctx context.Context, limit int) ([]HNStory, error) {
+	return h.fetchStories(ctx, "askstories", limit)
 }
 
 // ShowStories fetches Show HN stories.
-func (h *HNClient) ShowStories(limit int) ([]HNStory, error) {
-	return h.fetchStories("showstories", limit)
+func (h *HNClient) ShowStories(ctx context.Context, limit int) ([]HNStory, error) {
+	return h.fetchStories(ctx, "showstories", limit)
 }
 
 // FetchComments fetches comments for a story (top-level only) in parallel.
-func (h *HNClient) FetchComments(story *HNStory, limit int) ([]HNComment, error) {
+func (h *HNClient) FetchComments(ctx context.Context, story *HNStory, limit int) ([]HNComment, error) {
 	if limit <= 0 || limit > 50 {
 		limit = 20
 	}
@@ -99,18 +103,25 @@ func (h *HNClient) FetchComments(story *HNStory, limit int) ([]HNComment, error)
 		kids = kids[:limit]
 	}
 
-	// Fetch comments in parallel
+	comments := h.fetchComments(ctx, kids)
+	comments = slices.DeleteFunc(comments, func(c HNComment) bool { return c.Deleted || c.Dead })
+	return comments, nil
+}
+
+// fetchComments fetches items in parallel with bounded concurrency, keeping
+// the order of ids. Items that fail to load are left out.
+func (h *HNClient) fetchComments(ctx context.Context, ids []int) []HNComment {
 	type result struct {
 		idx     int
 		comment HNComment
 		ok      bool
 	}
 
-	results := make(chan result, len(kids))
+	results := make(chan result, len(ids))
 	sem := make(chan struct{}, hnConcurrency)
 
 	var wg sync.WaitGroup
-	for i, id := range kids {
+	for i, id := range ids {
 		wg.Add(1)
 		go func(idx, commentID int) {
 			defer wg.Done()
@@ -118,11 +129,7 @@ func (h *HNClient) FetchComments(story *HNStory, limit int) ([]HNComment, error)
 			defer func() { <-sem }()
 
 			var comment HNComment
-			if err := h.fetchItem(commentID, &comment); err != nil {
-				results <- result{idx: idx, ok: false}
-				return
-			}
-			if comment.Deleted || comment.Dead {
+			if err := h.fetchItem(ctx, commentID, &comment); err != nil || comment.ID == 0 {
 				results <- result{idx: idx, ok: false}
 				return
 			}
@@ -136,29 +143,56 @@ func (h *HNClient) FetchComments(story *HNStory, limit int) ([]HNComment, error)
 	}()
 
 	// Collect and sort by original order
-	comments := make([]HNComment, 0, len(kids))
 	collected := make(map[int]HNComment)
 	for r := range results {
 		if r.ok {
 			collected[r.idx] = r.comment
 		}
 	}
-	for i := 0; i < len(kids); i++ {
+	comments := make([]HNComment, 0, len(ids))
+	for i := 0; i < len(ids); i++ {
 		if c, ok := collected[i]; ok {
 			comments = append(comments, c)
 		}
 	}
 
-	return comments, nil
+	return comments
+}
+
+// ParseHNItemURL returns the item ID of a news.ycombinator.com/item?id=
+// URL.
+func ParseHNItemURL(rawURL string) (int, bool) {
+	u := strings.TrimSpace(rawURL)
+	if !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+		u = "https://" + u
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return 0, false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if host != "news.ycombinator.com" || parsed.Path != "/item" {
+		return 0, false
+	}
+	id, err := strconv.Atoi(parsed.Query().Get("id"))
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
 }
 
-func (h *HNClient) fetchStories(endpoint string, limit int) ([]HNStory, error) {
+func (h *HNClient) fetchStories(ctx context.Context, endpoint string, limit int) ([]HNStory, error) {
 	if limit <= 0 || limit > hnMaxItems {
 		limit = hnMaxItems
 	}
 
 	url := fmt.Sprintf("%s/%s.json", hnBaseURL, endpoint)
-	resp, err := h.client.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("fetching %s: %w", endpoint, err)
 	}
@@ -197,7 +231,7 @@ func (h *HNClient) fetchStories(endpoint string, limit int) ([]HNStory, error) {
 			defer func() { <-sem }()
 
 			var story HNStory
-			if err := h.fetchItem(storyID, &story); err != nil {
+			if err := h.fetchItem(ctx, storyID, &story); err != nil {
 				results <- storyResult{idx: idx, ok: false}
 				return
 			}
@@ -228,8 +262,8 @@ func (h *HNClient) fetchStories(endpoint string, limit int) ([]HNStory, error) {
 	return stories, nil
 }
 
-func (h *HNClient) fetchItem(id int, v interface{}) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (h *HNClient) fetchItem(ctx context.Context, id int, v interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	url := fmt.Sprintf("%s/item/%d.json", hnBaseURL, id)
@@ -272,28 +306,41 @@ func (r *storyRenderer) render() (string, []browser.Link) {
 		return sorted[i].Score > sorted[j].Score
 	})
 
-	for i, story := range sorted {
+	idx := 0
+	for _, story := range sorted {
 		ago := timeAgo(time.Unix(story.Time, 0))
+		itemURL := fmt.Sprintf("https://news.ycombinator.com/item?id=%d", story.ID)
+
+		// Stories link to the article, then to their comment thread; text
+		// posts like Ask HN only to the thread.
+		idx++
+		storyIdx := idx
 		url := story.URL
 		if url == "" {
-			url = fmt.Sprintf("https://news.ycombinator.com/item?id=%d", story.ID)
+			url = itemURL
+		}
+		links = append(links, browser.Link{Index: storyIdx, Text: story.Title, URL: url})
+		comments := fmt.Sprintf("%d comments", story.Descendants)
+		if story.URL != "" {
+			idx++
+			links = append(links, browser.Link{Index: idx, Text: story.Title + " (comments)", URL: itemURL})
+			comments = fmt.Sprintf("[%d] %s", idx, comments)
 		}
 
-		idx := i + 1
-		sb.WriteString(fmt.Sprintf("  [%d] %s\n", idx, story.Title))
-		sb.WriteString(fmt.Sprintf("       %d points | %s | %d comments\n", story.Score, ago, story.Descendants))
+		sb.WriteString(fmt.Sprintf("  [%d] %s\n", storyIdx, story.Title))
+		sb.WriteString(fmt.Sprintf("       %d points | %s | %s\n", story.Score, ago, comments))
 		sb.WriteString(fmt.Sprintf("       %s\n\n", url))
-
-		links = append(links, browser.Link{
-			Index: idx,
-			Text:  story.Title,
-			URL:   url,
-		})
 	}
 
 	return sb.String(), links
 }
 
+// hnText turns the HTML of an item's text into plain paragraphs.
+func hnText(s string) string {
+	s = strings.ReplaceAll(s, "<p>", "\n\n")
+	return html.UnescapeString(stripHTML(s))
+}
+
 func timeAgo(t time.Time) string {
 	d := time.Since(t)
 	switch {
@@ -0,0 +1,270 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vidyasagar/tsurf/internal/browser"
+)
+
+// hnThreadBudget bounds the replies fetched in one go below the comments
+// being loaded. Deeper replies are fetched when their subthread is opened.
+const hnThreadBudget = 200
+
+// HNThread is an item with its comment tree.
+type HNThread struct {
+	Story    HNStory          `json:"story"`
+	Comments []*HNCommentNode `json:"comments"`
+}
+
+// HNCommentNode is a comment with its replies. Replies are fetched down the
+// tree until the budget runs out; Loaded is false for comments whose
+// replies were left for later.
+type HNCommentNode struct {
+	HNComment
+	Replies []*HNCommentNode `json:"replies,omitempty"`
+	Loaded  bool             `json:"-"`
+}
+
+// Pending returns how many replies of the comment are not fetched yet.
+func (n *HNCommentNode) Pending() int {
+	if n.Loaded {
+		return 0
+	}
+	return len(n.Kids)
+}
+
+// size returns the number of replies below the comment, fetched or not.
+func (n *HNCommentNode) size() int {
+	total := n.Pending()
+	for _, r := range n.Replies {
+		total += 1 + r.size()
+	}
+	return total
+}
+
+// Find returns the comment with the given ID, or nil.
+func (t *HNThread) Find(id int) *HNCommentNode {
+	var find func([]*HNCommentNode) *HNCommentNode
+	find = func(nodes []*HNCommentNode) *HNCommentNode {
+		for _, n := range nodes {
+			if n.ID == id {
+				return n
+			}
+			if found := find(n.Replies); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	return find(t.Comments)
+}
+
+// FetchThread fetches an item with all its top-level comments, and their
+// replies level by level as far as the budget allows.
+func (h *HNClient) FetchThread(ctx context.Context, id int) (*HNThread, error) {
+	var story HNStory
+	if err := h.fetchItem(ctx, id, &story); err != nil {
+		return nil, fmt.Errorf("fetching item %d: %w", id, err)
+	}
+	if story.ID == 0 {
+		return nil, fmt.Errorf("item %d not found", id)
+	}
+
+	comments := h.fetchNodes(ctx, story.Kids)
+	h.fetchReplies(ctx, comments, hnThreadBudget)
+	return &HNThread{Story: story, Comments: comments}, nil
+}
+
+// FetchReplies fetches the replies of a comment whose replies were left
+// out, and theirs while the budget lasts.
+func (h *HNClient) FetchReplies(ctx context.Context, kids []int) []*HNCommentNode {
+	replies := h.fetchNodes(ctx, kids)
+	h.fetchReplies(ctx, replies, hnThreadBudget)
+	return replies
+}
+
+// fetchReplies fills in the replies of level breadth first. The replies of
+// a comment are fetched all together or not at all, so fetching stops at
+// the first comment that would go over the budget.
+func (h *HNClient) fetchReplies(ctx context.Context, level []*HNCommentNode, budget int) {
+	for len(level) > 0 {
+		var ids []int
+		var parents []*HNCommentNode
+		for _, n := range level {
+			if len(ids)+len(n.Kids) > budget {
+				break
+			}
+			ids = append(ids, n.Kids...)
+			parents = append(parents, n)
+		}
+		if len(parents) == 0 {
+			return
+		}
+		budget -= len(ids)
+
+		byParent := make(map[int][]*HNCommentNode)
+		for _, n := range h.fetchNodes(ctx, ids) {
+			byParent[n.Parent] = append(byParent[n.Parent], n)
+		}
+		var next []*HNCommentNode
+		for _, p := range parents {
+			p.Replies = byParent[p.ID]
+			p.Loaded = true
+			next = append(next, p.Replies...)
+		}
+		level = next
+	}
+}
+
+// fetchNodes fetches comments as tree nodes. Deleted and dead comments are
+// kept only when they have replies, to hold their place in the tree.
+func (h *HNClient) fetchNodes(ctx context.Context, ids []int) []*HNCommentNode {
+	var nodes []*HNCommentNode
+	for _, c := range h.fetchComments(ctx, ids) {
+		if (c.Deleted || c.Dead) && len(c.Kids) == 0 {
+			continue
+		}
+		nodes = append(nodes, &HNCommentNode{HNComment: c})
+	}
+	return nodes
+}
+
+// FetchItemPage fetches an item with its comment tree and renders it.
+func (h *HNClient) FetchItemPage(ctx context.Context, id, width int) (*Page, error) {
+	thread, err := h.FetchThread(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	content, links, _ := RenderHNThread(thread, nil, width)
+	pageURL := fmt.Sprintf("https://news.ycombinator.com/item?id=%d", id)
+	title := thread.Title()
+	data := &Data{Kind: "hn_thread", Title: title, URL: pageURL, Items: thread}
+	return &Page{content, truncate(title, 40) + " - HN", links, data}, nil
+}
+
+// Title returns the story title, or names the author of a comment's page.
+func (t *HNThread) Title() string {
+	if t.Story.Title != "" {
+		return t.Story.Title
+	}
+	return fmt.Sprintf("Comment by %s", t.Story.By)
+}
+
+// HNThreadRow locates a comment shown in a rendered thread.
+type HNThreadRow struct {
+	Line  int // line of the comment's header
+	ID    int
+	Depth int
+}
+
+// RenderHNThread formats an item page with its comment tree, each reply
+// indented below its parent. Comments whose IDs are in folded show only
+// their header. The rows give the line of every comment shown.
+func RenderHNThread(t *HNThread, folded map[int]bool, width int) (string, []browser.Link, []HNThreadRow) {
+	r := &threadRenderer{folded: folded, wrap: 76}
+	if width > 0 && width-4 < r.wrap {
+		r.wrap = width - 4
+	}
+	story := t.Story
+
+	r.line("  🔥 Hacker News")
+	r.line("  ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	r.line("")
+	if story.Title != "" {
+		r.line("  " + story.Title)
+	}
+	ago := timeAgo(time.Unix(story.Time, 0))
+	r.line(fmt.Sprintf("  %d points by %s | %s | %d comments", story.Score, story.By, ago, story.Descendants))
+	if story.URL != "" {
+		r.line(fmt.Sprintf("  [1] 🔗 %s", story.URL))
+		r.links = append(r.links, browser.Link{Index: 1, Text: story.Title, URL: story.URL})
+	}
+	r.line("")
+	if story.Text != "" {
+		for _, l := range strings.Split(wordWrap(hnText(story.Text), r.wrap), "\n") {
+			r.line("  " + l)
+		}
+		r.line("")
+	}
+
+	r.line("  ── Comments ────────────────────────────")
+	r.line("")
+	if len(t.Comments) == 0 {
+		r.line("  No comments yet.")
+	}
+	for _, c := range t.Comments {
+		r.comment(c, 0)
+	}
+
+	return strings.Join(r.lines, "\n") + "\n", r.links, r.rows
+}
+
+type threadRenderer struct {
+	folded map[int]bool
+	wrap   int
+	lines  []string
+	links  []browser.Link
+	rows   []HNThreadRow
+}
+
+func (r *threadRenderer) line(s string) {
+	r.lines = append(r.lines, s)
+}
+
+// comment writes a comment and, unless it is folded, its replies.
+func (r *threadRenderer) comment(c *HNCommentNode, depth int) {
+	indent := "  " + strings.Repeat("  ", depth)
+	r.rows = append(r.rows, HNThreadRow{Line: len(r.lines), ID: c.ID, Depth: depth})
+
+	author := c.By
+	if c.Deleted || c.Dead {
+		author = "[deleted]"
+	}
+	header := fmt.Sprintf("%s%s %s | %s", indent, r.marker(c), author, timeAgo(time.Unix(c.Time, 0)))
+	if r.folded[c.ID] {
+		if n := c.size(); n > 0 {
+			header += fmt.Sprintf(" | +%d hidden", n)
+		}
+		r.line(header)
+		return
+	}
+	r.line(header)
+
+	wrap := r.wrap - 2*depth - 2
+	if wrap < 30 {
+		wrap = 30
+	}
+	for _, l := range strings.Split(wordWrap(hnText(c.Text), wrap), "\n") {
+		r.line(strings.TrimRight(indent+"│ "+l, " "))
+	}
+	if n := c.Pending(); n > 0 {
+		r.line(fmt.Sprintf("%s└ %d more %s", indent, n, plural(n, "reply", "replies")))
+	}
+	r.line("")
+
+	for _, reply := range c.Replies {
+		r.comment(reply, depth+1)
+	}
+}
+
+// marker shows whether a comment's replies are open, folded or absent.
+func (r *threadRenderer) marker(c *HNCommentNode) string {
+	switch {
+	case len(c.Kids) == 0:
+		return "•"
+	case r.folded[c.ID] || len(c.Replies) == 0:
+		return "▸"
+	default:
+		return "▾"
+	}
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
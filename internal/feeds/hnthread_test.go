@@ -0,0 +1,153 @@
+package feeds
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeHN serves items from the map as the Firebase API would.
+type fakeHN struct {
+	items   map[int]any
+	fetched atomic.Int32
+}
+
+func (f *fakeHN) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	f.fetched.Add(1)
+	var id int
+	fmt.Sscanf(req.URL.Path, "/v0/item/%d.json", &id)
+	body, _ := json.Marshal(f.items[id])
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(string(body))),
+		Request:    req,
+	}, nil
+}
+
+func newFakeHN(items map[int]any) (*HNClient, *fakeHN) {
+	f := &fakeHN{items: items}
+	return &HNClient{client: &http.Client{Transport: f}}, f
+}
+
+// threadItems is a story (1) with two top-level comments; 10 has a reply
+// with a reply of its own, and 20 was deleted but keeps its reply.
+func threadItems() map[int]any {
+	return map[int]any{
+		1:   HNStory{ID: 1, Title: "Show HN: tsurf", By: "alice", Kids: []int{10, 20, 30}},
+		10:  HNComment{ID: 10, By: "bob", Text: "Nice<p>Two &amp; more", Parent: 1, Kids: []int{11}},
+		11:  HNComment{ID: 11, By: "carol", Text: "Agreed", Parent: 10, Kids: []int{12}},
+		12:  HNComment{ID: 12, By: "dave", Text: "Deep", Parent: 11},
+		20:  HNComment{ID: 20, Deleted: true, Parent: 1, Kids: []int{21}},
+		21:  HNComment{ID: 21, By: "erin", Text: "Orphan", Parent: 20},
+		30:  HNComment{ID: 30, Dead: true, Parent: 1},
+		999: nil,
+	}
+}
+
+func TestFetchThread(t *testing.T) {
+	client, _ := newFakeHN(threadItems())
+	thread, err := client.FetchThread(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(thread.Comments) != 2 {
+		t.Fatalf("top-level comments = %d, want 2 (dead leaf dropped)", len(thread.Comments))
+	}
+	deep := thread.Find(12)
+	if deep == nil || deep.By != "dave" {
+		t.Fatalf("Find(12) = %+v", deep)
+	}
+	if n := thread.Find(20); n == nil || len(n.Replies) != 1 {
+		t.Errorf("deleted comment with replies = %+v, want kept with its reply", n)
+	}
+	if n := thread.Find(10); !n.Loaded || n.Pending() != 0 || n.size() != 2 {
+		t.Errorf("comment 10: loaded %v, pending %d, size %d", n.Loaded, n.Pending(), n.size())
+	}
+
+	if _, err := client.FetchThread(context.Background(), 999); err == nil {
+		t.Error("FetchThread of a missing item succeeded")
+	}
+}
+
+func TestHNSiteFetchCanceled(t *testing.T) {
+	client, fake := newFakeHN(threadItems())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	site := &HNSite{Client: client}
+	_, err := site.Fetch(ctx, "https://news.ycombinator.com/item?id=1", 80)
+	if !errors.Is(err, context.Canceled) || fake.fetched.Load() != 0 {
+		t.Errorf("Fetch after cancel = %v after %d fetches, want context.Canceled", err, fake.fetched.Load())
+	}
+}
+
+func TestFetchRepliesBudget(t *testing.T) {
+	client, fake := newFakeHN(threadItems())
+	top := client.fetchNodes(context.Background(), []int{10, 20})
+
+	// Room for the replies of 10 but not for theirs.
+	client.fetchReplies(context.Background(), top, 1)
+	if !top[0].Loaded || len(top[0].Replies) != 1 {
+		t.Fatalf("comment 10 = %+v, want its reply loaded", top[0])
+	}
+	if top[1].Loaded || top[1].Pending() != 1 {
+		t.Errorf("comment 20 loaded over the budget")
+	}
+	if reply := top[0].Replies[0]; reply.Loaded || reply.Pending() != 1 {
+		t.Errorf("reply 11 loaded over the budget")
+	}
+
+	fake.fetched.Store(0)
+	replies := client.FetchReplies(context.Background(), top[1].Kids)
+	if len(replies) != 1 || replies[0].By != "erin" || fake.fetched.Load() != 1 {
+		t.Errorf("FetchReplies = %+v after %d fetches", replies, fake.fetched.Load())
+	}
+}
+
+func TestRenderHNThread(t *testing.T) {
+	client, _ := newFakeHN(threadItems())
+	thread, err := client.FetchThread(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, _, rows := RenderHNThread(thread, nil, 80)
+	lines := strings.Split(content, "\n")
+	var ids []int
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+		if !strings.Contains(lines[row.Line], "|") {
+			t.Errorf("row %d points at %q, not a comment header", row.ID, lines[row.Line])
+		}
+	}
+	if fmt.Sprint(ids) != "[10 11 12 20 21]" {
+		t.Errorf("rows = %v, want the comments depth first", ids)
+	}
+	if got := lines[rows[2].Line]; !strings.HasPrefix(got, "      • dave") {
+		t.Errorf("depth 2 header = %q, want indented leaf", got)
+	}
+	if !strings.Contains(content, "│ Two & more") {
+		t.Errorf("comment HTML not turned into text:\n%s", content)
+	}
+	if !strings.Contains(content, "▸ [deleted]") && !strings.Contains(content, "▾ [deleted]") {
+		t.Errorf("deleted comment not shown:\n%s", content)
+	}
+
+	content, _, rows = RenderHNThread(thread, map[int]bool{10: true}, 80)
+	if strings.Contains(content, "carol") || !strings.Contains(content, "▸ bob") || !strings.Contains(content, "+2 hidden") {
+		t.Errorf("folded comment 10 still shows its replies:\n%s", content)
+	}
+	if len(rows) != 3 {
+		t.Errorf("rows with 10 folded = %d, want 3", len(rows))
+	}
+}
@@ -0,0 +1,254 @@
+package feeds
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/vidyasagar/tsurf/internal/browser"
+)
+
+const (
+	// PluginProtocolVersion is sent in every request, for plugins to reject
+	// requests they do not understand.
+	PluginProtocolVersion = 1
+
+	defaultPluginTimeout = 10 * time.Second
+	maxPluginBytes       = 8 * 1024 * 1024 // 8MB limit for a plugin's reply
+	maxPluginStderr      = 2048            // bytes of stderr kept for errors
+)
+
+// PluginRequest is written as JSON to a plugin's stdin.
+type PluginRequest struct {
+	Version int    `json:"version"`
+	URL     string `json:"url"`
+	Width   int    `json:"width"` // columns available to the page
+}
+
+// PluginLink is a link of a plugin page. Links are numbered from 1 in the
+// order given, and the page refers to them as [1], [2], ...
+type PluginLink struct {
+	Text string `json:"text"`
+	URL  string `json:"url"`
+}
+
+// PluginResponse is read as JSON from a plugin's stdout. Markdown is
+// rendered like a README; Content is shown as is and used when Markdown is
+// empty. A non-empty Error is shown as an error page.
+type PluginResponse struct {
+	Title    string          `json:"title"`
+	Content  string          `json:"content,omitempty"`
+	Markdown string          `json:"markdown,omitempty"`
+	Links    []PluginLink    `json:"links,omitempty"`
+	Data     json.RawMessage `json:"data,omitempty"` // exported by :export json
+	Error    string          `json:"error,omitempty"`
+}
+
+// PluginError is a failed plugin run, with what the plugin wrote to stderr.
+type PluginError struct {
+	Plugin string
+	Err    error
+	Stderr string
+}
+
+func (e *PluginError) Error() string {
+	return fmt.Sprintf("plugin %s: %s", e.Plugin, e.Err)
+}
+
+func (e *PluginError) Unwrap() error { return e.Err }
+
+// PluginSite is a site handler that runs an external command for the URLs
+// matching its pattern. The command reads a PluginRequest from stdin and
+// writes a PluginResponse to stdout.
+type PluginSite struct {
+	name    string
+	pattern *regexp.Regexp
+	command []string
+	timeout time.Duration
+}
+
+// NewPluginSite creates a plugin handler for URLs matching pattern, a
+// regular expression. A zero timeout means the default of 10s.
+func NewPluginSite(name, pattern string, command []string, timeout time.Duration) (*PluginSite, error) {
+	if name == "" {
+		return nil, fmt.Errorf("plugin without a name")
+	}
+	if len(command) == 0 || command[0] == "" {
+		return nil, fmt.Errorf("plugin %s: no command", name)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: bad match pattern: %w", name, err)
+	}
+	if timeout <= 0 {
+		timeout = defaultPluginTimeout
+	}
+	return &PluginSite{name: name, pattern: re, command: command, timeout: timeout}, nil
+}
+
+// Name implements SiteHandler.
+func (p *PluginSite) Name() string { return p.name }
+
+// Match implements SiteHandler.
+func (p *PluginSite) Match(url string) bool { return p.pattern.MatchString(url) }
+
+// Pattern returns the regular expression matched against URLs.
+func (p *PluginSite) Pattern() string { return p.pattern.String() }
+
+// Command returns the executable and its arguments.
+func (p *PluginSite) Command() []string { return p.command }
+
+// Timeout returns how long a run may take before it is killed.
+func (p *PluginSite) Timeout() time.Duration { return p.timeout }
+
+// Fetch implements SiteHandler by running the plugin.
+func (p *PluginSite) Fetch(ctx context.Context, pageURL string, width int) (*Page, error) {
+	resp, err := p.run(ctx, PluginRequest{Version: PluginProtocolVersion, URL: pageURL, Width: width})
+	if err != nil {
+		return nil, err
+	}
+
+	content := resp.Content
+	if resp.Markdown != "" {
+		content, err = renderMarkdown(resp.Markdown, width)
+		if err != nil {
+			return nil, &PluginError{Plugin: p.name, Err: fmt.Errorf("rendering markdown: %w", err)}
+		}
+	}
+
+	links := make([]browser.Link, 0, len(resp.Links))
+	for i, l := range resp.Links {
+		links = append(links, browser.Link{Index: i + 1, Text: l.Text, URL: l.URL})
+	}
+
+	title := resp.Title
+	if title == "" {
+		title = pageURL
+		if u, err := url.Parse(pageURL); err == nil && u.Host != "" {
+			title = u.Host
+		}
+	}
+
+	var items any
+	if len(resp.Data) > 0 {
+		items = resp.Data
+	}
+	data := &Data{Kind: "plugin/" + p.name, Title: title, URL: pageURL, Items: items}
+	return &Page{content, title, links, data}, nil
+}
+
+// run executes the plugin with req on stdin and decodes its reply.
+func (p *PluginSite) run(ctx context.Context, req PluginRequest) (*PluginResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, &PluginError{Plugin: p.name, Err: err}
+	}
+
+	stdout := &cappedBuffer{max: maxPluginBytes}
+	stderr := &tailBuffer{max: maxPluginStderr}
+	cmd := exec.CommandContext(ctx, p.command[0], p.command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Don't wait on pipes held open by the plugin's children once it is killed.
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Run()
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		return nil, &PluginError{Plugin: p.name, Err: fmt.Errorf("timed out after %s", p.timeout), Stderr: stderr.String()}
+	case ctx.Err() != nil:
+		return nil, ctx.Err()
+	case err != nil:
+		return nil, &PluginError{Plugin: p.name, Err: err, Stderr: stderr.String()}
+	case stdout.over:
+		return nil, &PluginError{Plugin: p.name, Err: fmt.Errorf("reply larger than %d bytes", maxPluginBytes)}
+	}
+
+	var resp PluginResponse
+	if err := json.Unmarshal(stdout.buf.Bytes(), &resp); err != nil {
+		return nil, &PluginError{Plugin: p.name, Err: fmt.Errorf("reading reply: %w", err), Stderr: stderr.String()}
+	}
+	if resp.Error != "" {
+		return nil, &PluginError{Plugin: p.name, Err: errors.New(resp.Error), Stderr: stderr.String()}
+	}
+	return &resp, nil
+}
+
+// cappedBuffer keeps the first max bytes written to it and drops the rest,
+// so that a runaway plugin is not left blocked on a full pipe.
+type cappedBuffer struct {
+	buf  bytes.Buffer
+	max  int
+	over bool
+}
+
+func (c *cappedBuffer) Write(p []byte) (int, error) {
+	if room := c.max - c.buf.Len(); len(p) > room {
+		c.over = true
+		c.buf.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	return c.buf.Write(p)
+}
+
+// tailBuffer keeps the last max bytes written to it.
+type tailBuffer struct {
+	buf []byte
+	max int
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > t.max {
+		t.buf = t.buf[len(t.buf)-t.max:]
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	return strings.TrimSpace(string(t.buf))
+}
+
+// RenderSites formats the site handlers in the order they are tried, with
+// each plugin's pattern, command and timeout.
+func RenderSites(r *SiteRegistry) string {
+	var sb strings.Builder
+
+	sb.WriteString("  🔌 Site handlers\n")
+	sb.WriteString("  ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	plugins := 0
+	for _, h := range r.Handlers() {
+		state := ""
+		if !r.Enabled(h.Name()) {
+			state = " (disabled)"
+		}
+		p, ok := h.(*PluginSite)
+		if !ok {
+			sb.WriteString(fmt.Sprintf("  %-12s built-in%s\n", h.Name(), state))
+			continue
+		}
+		plugins++
+		sb.WriteString(fmt.Sprintf("  %-12s plugin%s\n", p.Name(), state))
+		sb.WriteString(fmt.Sprintf("               match    %s\n", p.Pattern()))
+		sb.WriteString(fmt.Sprintf("               command  %s\n", strings.Join(p.Command(), " ")))
+		sb.WriteString(fmt.Sprintf("               timeout  %s\n", p.Timeout()))
+	}
+
+	if plugins == 0 {
+		sb.WriteString("\n  No plugins configured. Add them under \"plugins\" in config.json.\n")
+	}
+	sb.WriteString("\n  Handlers are tried from the top; the first whose pattern matches renders the page.\n")
+	return sb.String()
+}
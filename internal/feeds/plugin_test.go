@@ -0,0 +1,106 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// shPlugin returns a plugin running script with sh.
+func shPlugin(t *testing.T, script string, timeout time.Duration) *PluginSite {
+	t.Helper()
+	p, err := NewPluginSite("wiki", `^https://wiki\.test/`, []string{"sh", "-c", script}, timeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestPluginFetch(t *testing.T) {
+	// Echo the request back so the test sees what the plugin was sent.
+	p := shPlugin(t, `req=$(cat); printf '{"title":"Home","content":"%s","links":[{"text":"A","url":"https://wiki.test/a"}],"data":{"id":7}}' "$(echo "$req" | tr '"' "'")"`, 0)
+
+	if !p.Match("https://wiki.test/Home") || p.Match("https://example.com/") {
+		t.Error("Match does not follow the pattern")
+	}
+
+	page, err := p.Fetch(context.Background(), "https://wiki.test/Home", 72)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Title != "Home" {
+		t.Errorf("Title = %q", page.Title)
+	}
+	want := "{'version':1,'url':'https://wiki.test/Home','width':72}"
+	if page.Content != want {
+		t.Errorf("Content = %q, want the request %q", page.Content, want)
+	}
+	if len(page.Links) != 1 || page.Links[0].Index != 1 || page.Links[0].URL != "https://wiki.test/a" {
+		t.Errorf("Links = %+v", page.Links)
+	}
+	if page.Data.Kind != "plugin/wiki" || page.Data.Items == nil {
+		t.Errorf("Data = %+v", page.Data)
+	}
+}
+
+func TestPluginErrors(t *testing.T) {
+	tests := []struct {
+		name, script string
+		timeout      time.Duration
+		err, stderr  string
+	}{
+		{"reply error", `echo '{"error":"no such page"}'`, 0, "no such page", ""},
+		{"exit status", `echo 'wiki is down' >&2; exit 3`, 0, "exit status 3", "wiki is down"},
+		{"bad json", `echo 'not json'`, 0, "reading reply", ""},
+		{"timeout", `sleep 5`, 100 * time.Millisecond, "timed out after 100ms", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := shPlugin(t, tt.script, tt.timeout)
+			_, err := p.Fetch(context.Background(), "https://wiki.test/x", 80)
+
+			var perr *PluginError
+			if !errors.As(err, &perr) {
+				t.Fatalf("err = %v, want a PluginError", err)
+			}
+			if perr.Plugin != "wiki" || !strings.Contains(perr.Err.Error(), tt.err) {
+				t.Errorf("err = %v, want %q", err, tt.err)
+			}
+			if perr.Stderr != tt.stderr {
+				t.Errorf("Stderr = %q, want %q", perr.Stderr, tt.stderr)
+			}
+		})
+	}
+}
+
+func TestNewPluginSiteValidates(t *testing.T) {
+	if _, err := NewPluginSite("", ".", []string{"true"}, 0); err == nil {
+		t.Error("plugin without a name accepted")
+	}
+	if _, err := NewPluginSite("x", ".", nil, 0); err == nil {
+		t.Error("plugin without a command accepted")
+	}
+	if _, err := NewPluginSite("x", "(", []string{"true"}, 0); err == nil {
+		t.Error("bad pattern accepted")
+	}
+	p, err := NewPluginSite("x", ".", []string{"true"}, 0)
+	if err != nil || p.Timeout() != defaultPluginTimeout {
+		t.Errorf("NewPluginSite = %v, %v; want the default timeout", p, err)
+	}
+}
+
+func TestDefaultSitesPluginReplacesBuiltin(t *testing.T) {
+	p, err := NewPluginSite("github", `^https://github\.com/`, []string{"true"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := DefaultSites(nil, nil, nil, p)
+	if got := strings.Join(siteNames(r), ","); got != "github,reddit,hn" {
+		t.Errorf("handlers = %s, want github,reddit,hn", got)
+	}
+	if h := r.Match("https://github.com/golang/go"); h != p {
+		t.Errorf("Match = %v, want the plugin", h)
+	}
+}
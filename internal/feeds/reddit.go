@@ -1,8 +1,10 @@
 package feeds
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
 	"net/url"
@@ -116,7 +118,7 @@ type RedditComment struct {
 	CreatedUTC float64               `json:"created_utc"`
 	Depth      int                   `json:"depth"`
 	Replies    *RedditCommentListing `json:"-"`
-	RepliesRaw json.RawMessage       `json:"replies"`
+	RepliesRaw json.RawMessage       `json:"-"`
 }
 
 // RedditCommentListing wraps the comment listing structure.
@@ -131,8 +133,8 @@ type RedditCommentListing struct {
 
 // RedditPostDetail holds a post with its comments.
 type RedditPostDetail struct {
-	Post     RedditPost
-	Comments []RedditComment
+	Post     RedditPost      `json:"post"`
+	Comments []RedditComment `json:"comments"`
 }
 
 // RedditPost represents a Reddit post.
@@ -169,7 +171,7 @@ func NewRedditClient() *RedditClient {
 
 // FetchSubreddit fetches posts from a subreddit.
 // sort can be "hot", "new", "top", "rising".
-func (r *RedditClient) FetchSubreddit(subreddit string, sort string, limit int) ([]RedditPost, error) {
+func (r *RedditClient) FetchSubreddit(ctx context.Context, subreddit string, sort string, limit int) ([]RedditPost, error) {
 	if limit <= 0 || limit > 50 {
 		limit = 25
 	}
@@ -178,21 +180,21 @@ func (r *RedditClient) FetchSubreddit(subreddit string, sort string, limit int)
 	}
 
 	url := fmt.Sprintf("https://www.reddit.com/r/%s/%s.json?limit=%d&raw_json=1", subreddit, sort, limit)
-	return r.fetchPosts(url)
+	return r.fetchPosts(ctx, url)
 }
 
 // FetchFrontpage fetches Reddit frontpage.
-func (r *RedditClient) FetchFrontpage(limit int) ([]RedditPost, error) {
+func (r *RedditClient) FetchFrontpage(ctx context.Context, limit int) ([]RedditPost, error) {
 	if limit <= 0 || limit > 50 {
 		limit = 25
 	}
 
 	url := fmt.Sprintf("https://www.reddit.com/.json?limit=%d&raw_json=1", limit)
-	return r.fetchPosts(url)
+	return r.fetchPosts(ctx, url)
 }
 
-func (r *RedditClient) fetchPosts(url string) ([]RedditPost, error) {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+func (r *RedditClient) fetchPosts(ctx context.Context, url string) ([]RedditPost, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
@@ -255,10 +257,10 @@ func RenderRedditPosts(posts []RedditPost, title string) (string, []browser.Link
 }
 
 // FetchPostDetail fetches a Reddit post with comments using the .json API.
-func (r *RedditClient) FetchPostDetail(subreddit, postID string) (*RedditPostDetail, error) {
+func (r *RedditClient) FetchPostDetail(ctx context.Context, subreddit, postID string) (*RedditPostDetail, error) {
 	jsonURL := fmt.Sprintf("https://www.reddit.com/r/%s/comments/%s.json?raw_json=1&limit=100", subreddit, postID)
 
-	req, err := http.NewRequest(http.MethodGet, jsonURL, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jsonURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
@@ -391,7 +393,7 @@ func RenderPostDetail(detail *RedditPostDetail) (string, []browser.Link) {
 	// Self text.
 	if post.Selftext != "" {
 		// Word wrap the self text.
-		wrapped := wordWrap(post.Selftext, 76)
+		wrapped := wrapMarkdown(post.Selftext, 76)
 		for _, line := range strings.Split(wrapped, "\n") {
 			sb.WriteString(fmt.Sprintf("  %s\n", line))
 		}
@@ -421,7 +423,7 @@ func RenderPostDetail(detail *RedditPostDetail) (string, []browser.Link) {
 		if maxWidth < 30 {
 			maxWidth = 30
 		}
-		wrapped := wordWrap(comment.Body, maxWidth)
+		wrapped := wrapMarkdown(comment.Body, maxWidth)
 		for _, line := range strings.Split(wrapped, "\n") {
 			sb.WriteString(fmt.Sprintf("  %s%s\n", indent, line))
 		}
@@ -432,38 +434,37 @@ func RenderPostDetail(detail *RedditPostDetail) (string, []browser.Link) {
 }
 
 // FetchURL auto-detects a Reddit URL type and fetches/renders it.
-// Returns content, title, links, and any error.
-func (r *RedditClient) FetchURL(info *RedditURLInfo) (string, string, []browser.Link, error) {
+func (r *RedditClient) FetchURL(ctx context.Context, info *RedditURLInfo) (*Page, error) {
 	switch info.Type {
 	case RedditURLPost:
-		detail, err := r.FetchPostDetail(info.Subreddit, info.PostID)
+		detail, err := r.FetchPostDetail(ctx, info.Subreddit, info.PostID)
 		if err != nil {
-			return "", "", nil, err
+			return nil, err
 		}
 		content, links := RenderPostDetail(detail)
 		title := fmt.Sprintf("r/%s - %s", detail.Post.Subreddit, truncate(detail.Post.Title, 40))
-		return content, title, links, nil
+		return &Page{content, title, links, &Data{Kind: "reddit_post", Title: detail.Post.Title, URL: info.OrigURL, Items: detail}}, nil
 
 	case RedditURLSubreddit:
-		posts, err := r.FetchSubreddit(info.Subreddit, "hot", 25)
+		posts, err := r.FetchSubreddit(ctx, info.Subreddit, "hot", 25)
 		if err != nil {
-			return "", "", nil, err
+			return nil, err
 		}
 		title := fmt.Sprintf("r/%s - Hot", info.Subreddit)
 		content, links := RenderRedditPosts(posts, title)
-		return content, title, links, nil
+		return &Page{content, title, links, &Data{Kind: "reddit", Title: title, URL: info.OrigURL, Items: posts}}, nil
 
 	case RedditURLFrontpage:
-		posts, err := r.FetchFrontpage(25)
+		posts, err := r.FetchFrontpage(ctx, 25)
 		if err != nil {
-			return "", "", nil, err
+			return nil, err
 		}
 		title := "Reddit - Front Page"
 		content, links := RenderRedditPosts(posts, title)
-		return content, title, links, nil
+		return &Page{content, title, links, &Data{Kind: "reddit", Title: title, URL: info.OrigURL, Items: posts}}, nil
 
 	default:
-		return "", "", nil, fmt.Errorf("unsupported Reddit URL type")
+		return nil, fmt.Errorf("unsupported Reddit URL type")
 	}
 }
 
@@ -505,6 +506,70 @@ func wordWrap(text string, width int) string {
 	return strings.TrimRight(result.String(), "\n")
 }
 
+// wrapMarkdown word-wraps Reddit markdown text, keeping code blocks (fenced
+// or indented by four spaces) intact and syntax highlighted.
+func wrapMarkdown(text string, width int) string {
+	var out, para, code []string
+	lang := ""
+	inFence := false
+
+	flushPara := func() {
+		if len(para) > 0 {
+			out = append(out, wordWrap(strings.Join(para, "\n"), width))
+			para = nil
+		}
+	}
+	flushCode := func() {
+		if len(code) > 0 {
+			src := html.UnescapeString(strings.Join(code, "\n"))
+			if lang == "" {
+				lang = browser.DetectLanguage("", src)
+			}
+			out = append(out, browser.HighlightCode(src, lang))
+			code, lang = nil, ""
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			if inFence {
+				flushCode()
+			} else {
+				flushPara()
+				lang = strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			}
+			inFence = !inFence
+		case inFence:
+			code = append(code, line)
+		case (strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")) &&
+			(len(code) > 0 || len(para) == 0 || strings.TrimSpace(para[len(para)-1]) == ""):
+			// Indented code must follow a blank line or start the text.
+			if len(code) == 0 {
+				flushPara()
+			}
+			code = append(code, strings.TrimPrefix(strings.TrimPrefix(line, "    "), "\t"))
+		case trimmed == "" && len(code) > 0:
+			code = append(code, "")
+		default:
+			if len(code) > 0 {
+				// Drop the blank lines that separated the block from this text.
+				for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+					code = code[:len(code)-1]
+				}
+				flushCode()
+				out = append(out, "")
+			}
+			para = append(para, line)
+		}
+	}
+	flushPara()
+	flushCode()
+
+	return strings.Join(out, "\n")
+}
+
 // truncate shortens a string to max length with "..." suffix.
 func truncate(s string, max int) string {
 	if len(s) <= max {
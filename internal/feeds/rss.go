@@ -1,6 +1,7 @@
 package feeds
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 	"io"
@@ -18,20 +19,20 @@ const (
 
 // Feed represents a parsed RSS/Atom feed.
 type Feed struct {
-	Title       string
-	Description string
-	Link        string
-	Items       []FeedItem
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Link        string     `json:"link"`
+	Items       []FeedItem `json:"items"`
 }
 
 // FeedItem represents a single item from a feed.
 type FeedItem struct {
-	Title       string
-	Link        string
-	Description string
-	Published   time.Time
-	Author      string
-	GUID        string
+	Title       string    `json:"title"`
+	Link        string    `json:"link"`
+	Description string    `json:"description"`
+	Published   time.Time `json:"published,omitzero"`
+	Author      string    `json:"author,omitempty"`
+	GUID        string    `json:"guid,omitempty"`
 }
 
 // RSSClient fetches and parses RSS/Atom feeds.
@@ -50,8 +51,8 @@ func NewRSSClient() *RSSClient {
 }
 
 // Fetch retrieves and parses an RSS or Atom feed.
-func (r *RSSClient) Fetch(url string) (*Feed, error) {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+func (r *RSSClient) Fetch(ctx context.Context, url string) (*Feed, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
@@ -1,6 +1,7 @@
 package feeds
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strings"
@@ -11,19 +12,19 @@ import (
 
 // SearchResult represents a single search result.
 type SearchResult struct {
-	Title   string
-	URL     string
-	Snippet string
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Snippet string `json:"snippet"`
 }
 
 // SearchDDG performs a search on DuckDuckGo HTML version and parses results.
 // Uses the shared HTTP transport for connection reuse.
-func SearchDDG(query string) ([]SearchResult, error) {
+func SearchDDG(ctx context.Context, query string) ([]SearchResult, error) {
 	searchURL := "https://html.duckduckgo.com/html/?q=" + url.QueryEscape(query)
 
 	// Use a fetcher with shared transport for connection pooling.
 	fetcher := browser.NewFetcher()
-	result, err := fetcher.Fetch(searchURL)
+	result, err := fetcher.FetchWithContext(ctx, searchURL)
 	if err != nil {
 		return nil, fmt.Errorf("searching DuckDuckGo: %w", err)
 	}
@@ -0,0 +1,181 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SiteHandler renders the pages of a site from its API instead of its HTML.
+type SiteHandler interface {
+	// Name identifies the handler in the config's site_order and
+	// sites_disabled lists.
+	Name() string
+	// Match reports whether the handler renders url.
+	Match(url string) bool
+	// Fetch fetches url and renders it width columns wide.
+	Fetch(ctx context.Context, url string, width int) (*Page, error)
+}
+
+// SiteRegistry picks the handler for a URL. Handlers are tried in order and
+// the first match wins; disabled handlers are skipped.
+type SiteRegistry struct {
+	handlers []SiteHandler
+	disabled map[string]bool
+}
+
+// NewSiteRegistry creates a registry trying handlers in the given order.
+func NewSiteRegistry(handlers ...SiteHandler) *SiteRegistry {
+	return &SiteRegistry{handlers: handlers, disabled: make(map[string]bool)}
+}
+
+// DefaultSites returns the registry of the built-in site handlers, with
+// plugins tried before them. A plugin named like a built-in replaces it.
+func DefaultSites(hn *HNClient, reddit *RedditClient, github *GitHubClient, plugins ...SiteHandler) *SiteRegistry {
+	r := NewSiteRegistry(plugins...)
+	for _, h := range []SiteHandler{&RedditSite{Client: reddit}, &GitHubSite{Client: github}, &HNSite{Client: hn}} {
+		if r.index(h.Name()) < 0 {
+			r.Register(h)
+		}
+	}
+	return r
+}
+
+// Register adds a handler, tried after those already registered. A handler
+// with the same name is replaced in place.
+func (r *SiteRegistry) Register(h SiteHandler) {
+	for i, existing := range r.handlers {
+		if existing.Name() == h.Name() {
+			r.handlers[i] = h
+			return
+		}
+	}
+	r.handlers = append(r.handlers, h)
+}
+
+// Configure applies the config's handler settings: the handlers named in
+// order move to the front in that order, and those in disabled are turned
+// off. Unknown names are reported, the known ones still applied.
+func (r *SiteRegistry) Configure(order, disabled []string) error {
+	var unknown []string
+
+	front := make([]SiteHandler, 0, len(order))
+	for _, name := range order {
+		i := r.index(name)
+		if i < 0 {
+			unknown = append(unknown, name)
+			continue
+		}
+		if !slices.Contains(front, r.handlers[i]) {
+			front = append(front, r.handlers[i])
+		}
+	}
+	rest := slices.DeleteFunc(slices.Clone(r.handlers), func(h SiteHandler) bool {
+		return slices.Contains(front, h)
+	})
+	r.handlers = append(front, rest...)
+
+	r.disabled = make(map[string]bool)
+	for _, name := range disabled {
+		if r.index(name) < 0 {
+			unknown = append(unknown, name)
+			continue
+		}
+		r.disabled[name] = true
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown site handlers: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// Match returns the first enabled handler matching url, or nil when the
+// page should be fetched as HTML.
+func (r *SiteRegistry) Match(url string) SiteHandler {
+	if r == nil {
+		return nil
+	}
+	for _, h := range r.handlers {
+		if !r.disabled[h.Name()] && h.Match(url) {
+			return h
+		}
+	}
+	return nil
+}
+
+// Handlers returns the handlers in the order they are tried.
+func (r *SiteRegistry) Handlers() []SiteHandler {
+	return slices.Clone(r.handlers)
+}
+
+// Enabled reports whether the named handler is in use.
+func (r *SiteRegistry) Enabled(name string) bool {
+	return r.index(name) >= 0 && !r.disabled[name]
+}
+
+func (r *SiteRegistry) index(name string) int {
+	return slices.IndexFunc(r.handlers, func(h SiteHandler) bool { return h.Name() == name })
+}
+
+// RedditSite renders Reddit front pages, subreddits and posts from the
+// .json API.
+type RedditSite struct {
+	Client *RedditClient
+}
+
+// Name implements SiteHandler.
+func (s *RedditSite) Name() string { return "reddit" }
+
+// Match implements SiteHandler.
+func (s *RedditSite) Match(url string) bool {
+	info := ParseRedditURL(url)
+	return info != nil && info.Type != RedditURLNone
+}
+
+// Fetch implements SiteHandler.
+func (s *RedditSite) Fetch(ctx context.Context, url string, width int) (*Page, error) {
+	return s.Client.FetchURL(ctx, ParseRedditURL(url))
+}
+
+// GitHubSite renders GitHub repositories, issues, pull requests, gists and
+// profiles from the GitHub API.
+type GitHubSite struct {
+	Client *GitHubClient
+}
+
+// Name implements SiteHandler.
+func (s *GitHubSite) Name() string { return "github" }
+
+// Match implements SiteHandler.
+func (s *GitHubSite) Match(url string) bool {
+	info := ParseGitHubURL(url)
+	return info != nil && info.Type != GitHubURLNone
+}
+
+// Fetch implements SiteHandler.
+func (s *GitHubSite) Fetch(ctx context.Context, url string, width int) (*Page, error) {
+	return s.Client.FetchURL(ctx, ParseGitHubURL(url), width)
+}
+
+// HNSite renders Hacker News item pages with their comments from the
+// Firebase API.
+type HNSite struct {
+	Client *HNClient
+}
+
+// Name implements SiteHandler.
+func (s *HNSite) Name() string { return "hn" }
+
+// Match implements SiteHandler.
+func (s *HNSite) Match(url string) bool {
+	_, ok := ParseHNItemURL(url)
+	return ok
+}
+
+// Fetch implements SiteHandler.
+func (s *HNSite) Fetch(ctx context.Context, url string, width int) (*Page, error) {
+	id, _ := ParseHNItemURL(url)
+	return s.Client.FetchItemPage(ctx, id, width)
+}
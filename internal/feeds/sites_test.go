@@ -0,0 +1,84 @@
+package feeds
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// stubSite matches URLs containing its name.
+type stubSite struct{ name string }
+
+func (s stubSite) Name() string          { return s.name }
+func (s stubSite) Match(url string) bool { return strings.Contains(url, s.name) }
+func (s stubSite) Fetch(ctx context.Context, url string, width int) (*Page, error) {
+	return &Page{Title: s.name}, nil
+}
+
+func siteNames(r *SiteRegistry) []string {
+	var names []string
+	for _, h := range r.Handlers() {
+		names = append(names, h.Name())
+	}
+	return names
+}
+
+func TestSiteRegistryMatch(t *testing.T) {
+	r := NewSiteRegistry(stubSite{"wiki"}, stubSite{"wikitracker"})
+
+	if h := r.Match("https://example.com/wikitracker/1"); h == nil || h.Name() != "wiki" {
+		t.Errorf("Match = %v, want the first registered handler", h)
+	}
+	if h := r.Match("https://example.com/"); h != nil {
+		t.Errorf("Match = %v, want nil", h)
+	}
+}
+
+func TestSiteRegistryConfigure(t *testing.T) {
+	r := NewSiteRegistry(stubSite{"a"}, stubSite{"b"}, stubSite{"c"})
+
+	err := r.Configure([]string{"c", "nope", "b"}, []string{"a", "gone"})
+	if err == nil || !strings.Contains(err.Error(), "nope, gone") {
+		t.Errorf("Configure error = %v, want unknown names reported", err)
+	}
+	if got := strings.Join(siteNames(r), ","); got != "c,b,a" {
+		t.Errorf("order = %s, want c,b,a", got)
+	}
+	if r.Enabled("a") || !r.Enabled("b") {
+		t.Errorf("Enabled(a) = %v, Enabled(b) = %v", r.Enabled("a"), r.Enabled("b"))
+	}
+	if h := r.Match("https://a.example/"); h != nil {
+		t.Errorf("disabled handler matched: %v", h.Name())
+	}
+
+	// Settings are replaced, not accumulated.
+	if err := r.Configure(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if h := r.Match("https://a.example/"); h == nil {
+		t.Error("handler still disabled after Configure(nil, nil)")
+	}
+}
+
+func TestDefaultSitesMatch(t *testing.T) {
+	r := DefaultSites(nil, nil, nil)
+	tests := map[string]string{
+		"https://www.reddit.com/r/golang":                 "reddit",
+		"https://github.com/golang/go/issues/1":           "github",
+		"https://news.ycombinator.com/item?id=8863":       "hn",
+		"news.ycombinator.com/item?id=8863":               "hn",
+		"https://news.ycombinator.com/news":               "",
+		"https://news.ycombinator.com/item?id=abc":        "",
+		"https://example.com/item?id=8863":                "",
+		"https://www.reddit.com/user/someone/submitted/x": "",
+	}
+	for url, want := range tests {
+		got := ""
+		if h := r.Match(url); h != nil {
+			got = h.Name()
+		}
+		if got != want {
+			t.Errorf("Match(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// ParseBookmarkFile reads bookmarks exported by a browser: the Netscape
+// bookmark HTML format (every browser's "Export bookmarks"), a Firefox JSON
+// backup, or Chrome's Bookmarks file. Folders become tags, nested ones
+// joined with "/", and the browsers' own top-level folders ("Bookmarks bar",
+// "Bookmarks Menu", ...) are left out. Only http and https bookmarks are
+// kept, and duplicates are merged by URL.
+func ParseBookmarkFile(data []byte) ([]Bookmark, error) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("empty bookmark file")
+	}
+
+	var (
+		bookmarks []Bookmark
+		err       error
+	)
+	if trimmed[0] == '{' {
+		bookmarks, err = parseBookmarkJSON(trimmed)
+	} else {
+		bookmarks, err = parseNetscape(trimmed)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dedupeBookmarks(bookmarks), nil
+}
+
+// parseBookmarkJSON parses Chrome's Bookmarks file or a Firefox backup.
+func parseBookmarkJSON(data []byte) ([]Bookmark, error) {
+	var probe struct {
+		Roots map[string]json.RawMessage `json:"roots"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("parsing bookmark JSON: %w", err)
+	}
+	if probe.Roots != nil {
+		return parseChrome(probe.Roots)
+	}
+	return parseFirefox(data)
+}
+
+// chromeNode is a folder or bookmark in Chrome's Bookmarks file.
+type chromeNode struct {
+	Type      string       `json:"type"` // "folder" or "url"
+	Name      string       `json:"name"`
+	URL       string       `json:"url"`
+	DateAdded string       `json:"date_added"` // microseconds since 1601-01-01
+	Children  []chromeNode `json:"children"`
+}
+
+// chromeEpochOffset is the number of microseconds from 1601-01-01, the
+// Windows FILETIME epoch Chrome counts from, to the Unix epoch.
+const chromeEpochOffset = 11644473600 * 1000000
+
+// parseChrome walks the roots of Chrome's Bookmarks file. The roots
+// themselves (bookmark bar, other, mobile) aren't folders the user made.
+func parseChrome(roots map[string]json.RawMessage) ([]Bookmark, error) {
+	var bookmarks []Bookmark
+	var walk func(n chromeNode, folder string)
+	walk = func(n chromeNode, folder string) {
+		switch n.Type {
+		case "url":
+			b := Bookmark{URL: n.URL, Title: n.Name, Tags: folderTags(folder)}
+			if us, err := strconv.ParseInt(n.DateAdded, 10, 64); err == nil && us > 0 {
+				b.CreatedAt = time.UnixMicro(us - chromeEpochOffset).UTC()
+			}
+			bookmarks = append(bookmarks, b)
+		case "folder":
+			for _, c := range n.Children {
+				walk(c, joinFolder(folder, n.Name))
+			}
+		}
+	}
+
+	for _, name := range []string{"bookmark_bar", "other", "synced"} {
+		raw, ok := roots[name]
+		if !ok {
+			continue
+		}
+		var root chromeNode
+		if err := json.Unmarshal(raw, &root); err != nil {
+			return nil, fmt.Errorf("parsing Chrome bookmarks: %w", err)
+		}
+		for _, c := range root.Children {
+			walk(c, "")
+		}
+	}
+	return bookmarks, nil
+}
+
+// firefoxNode is a container, bookmark or separator in a Firefox backup.
+type firefoxNode struct {
+	Type      string        `json:"type"` // text/x-moz-place, -container or -separator
+	Title     string        `json:"title"`
+	URI       string        `json:"uri"`
+	Root      string        `json:"root"`      // set on Firefox's own top-level folders
+	DateAdded int64         `json:"dateAdded"` // microseconds since the Unix epoch
+	Tags      string        `json:"tags"`      // comma-separated
+	Children  []firefoxNode `json:"children"`
+}
+
+// parseFirefox walks a Firefox JSON backup (bookmarks-<date>.json).
+func parseFirefox(data []byte) ([]Bookmark, error) {
+	var root firefoxNode
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parsing Firefox bookmarks: %w", err)
+	}
+	if root.Type != "text/x-moz-place-container" {
+		return nil, fmt.Errorf("not a Chrome or Firefox bookmark file")
+	}
+
+	var bookmarks []Bookmark
+	var walk func(n firefoxNode, folder string)
+	walk = func(n firefoxNode, folder string) {
+		switch n.Type {
+		case "text/x-moz-place":
+			b := Bookmark{URL: n.URI, Title: n.Title, Tags: folderTags(folder)}
+			if n.Tags != "" {
+				b.Tags = append(b.Tags, strings.Split(n.Tags, ",")...)
+			}
+			if n.DateAdded > 0 {
+				b.CreatedAt = time.UnixMicro(n.DateAdded).UTC()
+			}
+			bookmarks = append(bookmarks, b)
+		case "text/x-moz-place-container":
+			// Firefox keeps tags as a folder of folders; the bookmarks in
+			// it carry their tags already.
+			if n.Root == "tagsFolder" {
+				return
+			}
+			if n.Root == "" {
+				folder = joinFolder(folder, n.Title)
+			}
+			for _, c := range n.Children {
+				walk(c, folder)
+			}
+		}
+	}
+	walk(root, "")
+	return bookmarks, nil
+}
+
+// parseNetscape reads the Netscape bookmark HTML format: folders are <H3>
+// headings followed by a <DL> list of <A> links and nested folders. The
+// file is tokenized rather than parsed into a tree, since the format's
+// unclosed <DT> and <p> tags nest unpredictably.
+func parseNetscape(data []byte) ([]Bookmark, error) {
+	z := xhtml.NewTokenizer(bytes.NewReader(data))
+
+	var (
+		bookmarks []Bookmark
+		folders   []string // one entry per open <DL>, "" for skipped ones
+		heading   *string  // text of the <H3> being read
+		pending   string   // last folder heading, opened by the next <DL>
+		skip      bool     // the last heading was a browser's own folder
+		link      *Bookmark
+		sawList   bool
+	)
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case xhtml.ErrorToken:
+			if z.Err() == io.EOF {
+				if !sawList {
+					return nil, fmt.Errorf("not a bookmark file")
+				}
+				return bookmarks, nil
+			}
+			return nil, fmt.Errorf("parsing bookmark HTML: %w", z.Err())
+
+		case xhtml.StartTagToken:
+			tok := z.Token()
+			switch tok.Data {
+			case "h3":
+				s := ""
+				heading = &s
+				skip = attr(tok, "personal_toolbar_folder") == "true" ||
+					attr(tok, "unfiled_bookmarks_folder") == "true"
+			case "dl":
+				sawList = true
+				name := pending
+				if skip {
+					name = ""
+				}
+				folders = append(folders, name)
+				pending, skip = "", false
+			case "a":
+				b := Bookmark{URL: attr(tok, "href")}
+				if tags := attr(tok, "tags"); tags != "" {
+					b.Tags = strings.Split(tags, ",")
+				}
+				if secs, err := strconv.ParseInt(attr(tok, "add_date"), 10, 64); err == nil && secs > 0 {
+					b.CreatedAt = time.Unix(secs, 0).UTC()
+				}
+				link = &b
+			}
+
+		case xhtml.TextToken:
+			text := string(z.Text())
+			if heading != nil {
+				*heading += text
+			} else if link != nil {
+				link.Title += text
+			}
+
+		case xhtml.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "h3":
+				if heading != nil {
+					pending = strings.TrimSpace(*heading)
+					heading = nil
+				}
+			case "dl":
+				if len(folders) > 0 {
+					folders = folders[:len(folders)-1]
+				}
+			case "a":
+				if link != nil {
+					link.Title = strings.TrimSpace(link.Title)
+					link.Tags = append(folderTags(strings.Join(folders, "/")), link.Tags...)
+					bookmarks = append(bookmarks, *link)
+					link = nil
+				}
+			}
+		}
+	}
+}
+
+// attr returns the value of a tag's attribute, or "".
+func attr(tok xhtml.Token, name string) string {
+	for _, a := range tok.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// joinFolder returns the folder path of name inside folder.
+func joinFolder(folder, name string) string {
+	name = strings.ReplaceAll(strings.TrimSpace(name), "/", "-")
+	switch {
+	case name == "":
+		return folder
+	case folder == "":
+		return name
+	}
+	return folder + "/" + name
+}
+
+// folderTags returns the tag for a folder path, dropping the empty names of
+// skipped folders.
+func folderTags(path string) []string {
+	var names []string
+	for _, name := range strings.Split(path, "/") {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return []string{strings.Join(names, "/")}
+}
+
+// dedupeBookmarks keeps http(s) bookmarks, merging duplicates by URL: the
+// first title and the earliest date are kept, and the tags are combined.
+func dedupeBookmarks(bookmarks []Bookmark) []Bookmark {
+	var out []Bookmark
+	index := make(map[string]int)
+	for _, b := range bookmarks {
+		b.URL = strings.TrimSpace(b.URL)
+		if !strings.HasPrefix(b.URL, "http://") && !strings.HasPrefix(b.URL, "https://") {
+			continue
+		}
+		b.Tags = NormalizeTags(b.Tags)
+		i, seen := index[b.URL]
+		if !seen {
+			index[b.URL] = len(out)
+			out = append(out, b)
+			continue
+		}
+		d := &out[i]
+		if d.Title == "" {
+			d.Title = b.Title
+		}
+		if !b.CreatedAt.IsZero() && (d.CreatedAt.IsZero() || b.CreatedAt.Before(d.CreatedAt)) {
+			d.CreatedAt = b.CreatedAt
+		}
+		d.Tags = NormalizeTags(append(d.Tags, b.Tags...))
+	}
+	return out
+}
+
+// Import adds bookmarks read by ParseBookmarkFile, keeping their dates.
+// Bookmarks already in the store get the imported tags added. It returns
+// how many were added and how many existing ones were updated.
+func (bs *BookmarkStore) Import(bookmarks []Bookmark) (added, updated int) {
+	for _, b := range bookmarks {
+		if existing := bs.Get(b.URL); existing != nil {
+			if len(NormalizeTags(append(existing.Tags, b.Tags...))) > len(existing.Tags) {
+				bs.AddTags(b.URL, b.Tags...)
+				updated++
+			}
+			continue
+		}
+
+		title := b.Title
+		if title == "" {
+			title = b.URL
+		}
+		created := b.CreatedAt
+		if created.IsZero() {
+			created = time.Now()
+		}
+		_, err := bs.db.Exec(
+			`INSERT OR IGNORE INTO bookmarks (url, title, tags, created_at) VALUES (?, ?, ?, ?)`,
+			b.URL, title, strings.Join(NormalizeTags(b.Tags), ","), created.UTC().Format("2006-01-02 15:04:05"),
+		)
+		if err == nil {
+			added++
+		}
+	}
+	return added, updated
+}
+
+// ExportNetscape writes bookmarks in the Netscape bookmark HTML format that
+// browsers import. Each bookmark is filed in the folder of its first tag,
+// and all its tags are kept in the TAGS attribute, as Firefox does.
+func ExportNetscape(w io.Writer, bookmarks []Bookmark) error {
+	root := &BookmarkFolder{}
+	for _, b := range bookmarks {
+		f := root
+		if len(b.Tags) > 0 {
+			for _, name := range strings.Split(b.Tags[0], "/") {
+				if name != "" {
+					f = f.folder(name)
+				}
+			}
+		}
+		f.Bookmarks = append(f.Bookmarks, b)
+	}
+	root.sort()
+
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE NETSCAPE-Bookmark-file-1>\n")
+	sb.WriteString("<!-- This is an automatically generated file.\n     It will be read and overwritten.\n     DO NOT EDIT! -->\n")
+	sb.WriteString(`<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">` + "\n")
+	sb.WriteString("<TITLE>Bookmarks</TITLE>\n<H1>Bookmarks</H1>\n")
+
+	var write func(f *BookmarkFolder, indent string)
+	write = func(f *BookmarkFolder, indent string) {
+		sb.WriteString(indent + "<DL><p>\n")
+		inner := indent + "    "
+		for _, sub := range f.Folders {
+			sb.WriteString(fmt.Sprintf("%s<DT><H3>%s</H3>\n", inner, html.EscapeString(sub.Name)))
+			write(sub, inner)
+		}
+		for _, b := range f.Bookmarks {
+			sb.WriteString(fmt.Sprintf("%s<DT><A HREF=\"%s\"", inner, html.EscapeString(b.URL)))
+			if !b.CreatedAt.IsZero() {
+				sb.WriteString(fmt.Sprintf(" ADD_DATE=\"%d\"", b.CreatedAt.Unix()))
+			}
+			if len(b.Tags) > 0 {
+				sb.WriteString(fmt.Sprintf(" TAGS=\"%s\"", html.EscapeString(strings.Join(b.Tags, ","))))
+			}
+			sb.WriteString(fmt.Sprintf(">%s</A>\n", html.EscapeString(b.Title)))
+		}
+		sb.WriteString(indent + "</DL><p>\n")
+	}
+	write(root, "")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
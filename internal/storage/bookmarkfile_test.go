@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+const netscapeFile = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+    <DT><H3 ADD_DATE="1700000000" PERSONAL_TOOLBAR_FOLDER="true">Bookmarks bar</H3>
+    <DL><p>
+        <DT><H3>Go</H3>
+        <DL><p>
+            <DT><A HREF="https://go.dev/" ADD_DATE="1700000000">The Go &amp; Gophers</A>
+            <DT><H3>Blogs</H3>
+            <DL><p>
+                <DT><A HREF="https://go.dev/blog/" TAGS="news">Go Blog</A>
+            </DL><p>
+        </DL><p>
+        <DT><A HREF="https://example.com/">Example</A>
+        <DT><A HREF="javascript:alert(1)">Bookmarklet</A>
+    </DL><p>
+    <DT><H3>Reading</H3>
+    <DL><p>
+        <DT><A HREF="https://go.dev/">Go again</A>
+    </DL><p>
+</DL><p>
+`
+
+func TestParseNetscape(t *testing.T) {
+	bookmarks, err := ParseBookmarkFile([]byte(netscapeFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bookmarks) != 3 {
+		t.Fatalf("got %d bookmarks, want 3: %+v", len(bookmarks), bookmarks)
+	}
+
+	goDev := bookmarks[0]
+	if goDev.Title != "The Go & Gophers" || !slices.Equal(goDev.Tags, []string{"Go", "Reading"}) || goDev.CreatedAt.Unix() != 1700000000 {
+		t.Errorf("merged duplicate = %+v", goDev)
+	}
+	if blog := bookmarks[1]; !slices.Equal(blog.Tags, []string{"Go/Blogs", "news"}) {
+		t.Errorf("nested folder tags = %v", blog.Tags)
+	}
+	if ex := bookmarks[2]; len(ex.Tags) != 0 {
+		t.Errorf("bookmark in the toolbar root has tags %v", ex.Tags)
+	}
+}
+
+func TestParseChromeAndFirefox(t *testing.T) {
+	chrome := `{"roots": {
+		"bookmark_bar": {"type": "folder", "name": "Bookmarks bar", "children": [
+			{"type": "folder", "name": "Dev", "children": [
+				{"type": "url", "name": "Go", "url": "https://go.dev/", "date_added": "13345000000000000"}
+			]}
+		]},
+		"other": {"type": "folder", "name": "Other bookmarks", "children": [
+			{"type": "url", "name": "Example", "url": "https://example.com/"}
+		]}
+	}, "version": 1}`
+	bookmarks, err := ParseBookmarkFile([]byte(chrome))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bookmarks) != 2 || !slices.Equal(bookmarks[0].Tags, []string{"Dev"}) || bookmarks[0].CreatedAt.Year() != 2023 {
+		t.Errorf("Chrome bookmarks = %+v", bookmarks)
+	}
+
+	firefox := `{"type": "text/x-moz-place-container", "root": "placesRoot", "children": [
+		{"type": "text/x-moz-place-container", "title": "menu", "root": "bookmarksMenuFolder", "children": [
+			{"type": "text/x-moz-place-container", "title": "Rust", "children": [
+				{"type": "text/x-moz-place", "title": "Rust", "uri": "https://www.rust-lang.org/", "tags": "lang,systems", "dateAdded": 1700000000000000}
+			]},
+			{"type": "text/x-moz-place-separator"},
+			{"type": "text/x-moz-place", "title": "Recent", "uri": "place:sort=8"}
+		]},
+		{"type": "text/x-moz-place-container", "title": "tags", "root": "tagsFolder", "children": [
+			{"type": "text/x-moz-place-container", "title": "lang", "children": [
+				{"type": "text/x-moz-place", "title": "Rust", "uri": "https://www.rust-lang.org/"}
+			]}
+		]}
+	]}`
+	bookmarks, err = ParseBookmarkFile([]byte(firefox))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bookmarks) != 1 || !slices.Equal(bookmarks[0].Tags, []string{"Rust", "lang", "systems"}) || bookmarks[0].CreatedAt.Unix() != 1700000000 {
+		t.Errorf("Firefox bookmarks = %+v", bookmarks)
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	db, err := OpenDB(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	bs := NewBookmarkStore(db)
+
+	parsed, _ := ParseBookmarkFile([]byte(netscapeFile))
+	if added, _ := bs.Import(parsed); added != 3 {
+		t.Fatalf("Import added %d, want 3", added)
+	}
+	if added, updated := bs.Import(parsed); added != 0 || updated != 0 {
+		t.Errorf("re-import added %d, updated %d; want 0, 0", added, updated)
+	}
+	if b := bs.Get("https://go.dev/"); b == nil || b.CreatedAt.Unix() != 1700000000 {
+		t.Errorf("imported date not kept: %+v", b)
+	}
+
+	var buf bytes.Buffer
+	if err := ExportNetscape(&buf, bs.List()); err != nil {
+		t.Fatal(err)
+	}
+	again, err := ParseBookmarkFile(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != 3 {
+		t.Fatalf("round trip gave %d bookmarks:\n%s", len(again), buf.String())
+	}
+	for _, b := range again {
+		orig := bs.Get(b.URL)
+		if orig == nil || b.Title != orig.Title || !slices.Equal(b.Tags, orig.Tags) {
+			t.Errorf("round trip changed %+v to %+v", orig, b)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package storage
 import (
 	"database/sql"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,7 +16,7 @@ type Bookmark struct {
 	ID        int64
 	URL       string
 	Title     string
-	Tags      []string
+	Tags      []string // a "/" in a tag nests it in folders: "lang/go"
 	CreatedAt time.Time
 }
 
@@ -30,8 +32,8 @@ func NewBookmarkStore(db *DB) *BookmarkStore {
 
 // Add adds a bookmark. Returns false if already bookmarked.
 func (bs *BookmarkStore) Add(url, title string, tags ...string) bool {
-	tagStr := strings.Join(tags, ",")
-	_, err := bs.db.Exec(
+	tagStr := strings.Join(NormalizeTags(tags), ",")
+	res, err := bs.db.Exec(
 		`INSERT OR IGNORE INTO bookmarks (url, title, tags) VALUES (?, ?, ?)`,
 		url, title, tagStr,
 	)
@@ -39,7 +41,56 @@ func (bs *BookmarkStore) Add(url, title string, tags ...string) bool {
 		return false
 	}
 	// INSERT OR IGNORE returns RowsAffected=0 if it was a duplicate.
-	return true
+	n, _ := res.RowsAffected()
+	return n > 0
+}
+
+// Get returns the bookmark for a URL, or nil if it isn't bookmarked.
+func (bs *BookmarkStore) Get(url string) *Bookmark {
+	rows, err := bs.db.Query(
+		`SELECT id, url, title, tags, created_at FROM bookmarks WHERE url = ?`, url,
+	)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	if bms := scanBookmarks(rows); len(bms) > 0 {
+		return &bms[0]
+	}
+	return nil
+}
+
+// AddTags adds tags to a bookmark, keeping the ones it has. Returns false if
+// the URL isn't bookmarked.
+func (bs *BookmarkStore) AddTags(url string, tags ...string) bool {
+	b := bs.Get(url)
+	if b == nil {
+		return false
+	}
+	return bs.SetTags(url, append(b.Tags, tags...))
+}
+
+// SetTags replaces a bookmark's tags. Returns false if not found.
+func (bs *BookmarkStore) SetTags(url string, tags []string) bool {
+	res, err := bs.db.Exec(
+		`UPDATE bookmarks SET tags = ? WHERE url = ?`,
+		strings.Join(NormalizeTags(tags), ","), url,
+	)
+	if err != nil {
+		return false
+	}
+	n, _ := res.RowsAffected()
+	return n > 0
+}
+
+// Rename changes a bookmark's title. Returns false if not found.
+func (bs *BookmarkStore) Rename(url, title string) bool {
+	res, err := bs.db.Exec(`UPDATE bookmarks SET title = ? WHERE url = ?`, title, url)
+	if err != nil {
+		return false
+	}
+	n, _ := res.RowsAffected()
+	return n > 0
 }
 
 // Remove removes a bookmark by URL. Returns false if not found.
@@ -87,6 +138,20 @@ func (bs *BookmarkStore) Search(query string) []Bookmark {
 	return scanBookmarks(rows)
 }
 
+// Tags returns every tag in use, sorted.
+func (bs *BookmarkStore) Tags() []string {
+	var tags []string
+	for _, b := range bs.List() {
+		for _, t := range b.Tags {
+			if !slices.Contains(tags, t) {
+				tags = append(tags, t)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
 // Count returns the number of bookmarks.
 func (bs *BookmarkStore) Count() int {
 	var count int
@@ -106,40 +171,173 @@ func scanBookmarks(rows *sql.Rows) []Bookmark {
 		if tagStr != "" {
 			b.Tags = strings.Split(tagStr, ",")
 		}
-		b.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+		b.CreatedAt = parseDBTime(createdAt)
 		bookmarks = append(bookmarks, b)
 	}
 	return bookmarks
 }
 
-// RenderBookmarks formats bookmarks for the viewport.
-func RenderBookmarks(bookmarks []Bookmark) (string, []browser.Link) {
+// NormalizeTags cleans up tags as typed: "+go" and "go" are the same tag,
+// surrounding slashes and spaces are dropped, commas (the separator in the
+// database) split tags, and duplicates are removed.
+func NormalizeTags(tags []string) []string {
+	var out []string
+	for _, tag := range tags {
+		for _, t := range strings.Split(tag, ",") {
+			t = strings.Trim(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(t), "+")), "/")
+			if t != "" && !slices.Contains(out, t) {
+				out = append(out, t)
+			}
+		}
+	}
+	return out
+}
+
+// HasTag reports whether the bookmark has tag, or a tag in the folder of
+// that name: "lang" matches "lang/go". Case is ignored.
+func (b Bookmark) HasTag(tag string) bool {
+	tag = strings.ToLower(strings.Trim(tag, "/"))
+	for _, t := range b.Tags {
+		t = strings.ToLower(t)
+		if t == tag || strings.HasPrefix(t, tag+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterTag returns the bookmarks having tag (see Bookmark.HasTag).
+func FilterTag(bookmarks []Bookmark, tag string) []Bookmark {
+	var out []Bookmark
+	for _, b := range bookmarks {
+		if b.HasTag(tag) {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// BookmarkFolder is a node of the bookmark tree: each tag is a folder, and
+// a tag containing "/" is nested in the folders it names.
+type BookmarkFolder struct {
+	Name      string
+	Path      string // full tag, "" for the root
+	Folders   []*BookmarkFolder
+	Bookmarks []Bookmark
+}
+
+// Count returns the number of bookmarks in the folder and below it. A
+// bookmark filed under several tags is counted once per tag.
+func (f *BookmarkFolder) Count() int {
+	n := len(f.Bookmarks)
+	for _, sub := range f.Folders {
+		n += sub.Count()
+	}
+	return n
+}
+
+// BookmarkTree files bookmarks into folders by tag, sorted by name, with
+// bookmarks in their original order. A bookmark appears under each of its
+// tags; untagged ones stay at the root.
+func BookmarkTree(bookmarks []Bookmark) *BookmarkFolder {
+	root := &BookmarkFolder{}
+	for _, b := range bookmarks {
+		if len(b.Tags) == 0 {
+			root.Bookmarks = append(root.Bookmarks, b)
+			continue
+		}
+		for _, tag := range b.Tags {
+			f := root
+			for _, name := range strings.Split(tag, "/") {
+				if name == "" {
+					continue
+				}
+				f = f.folder(name)
+			}
+			f.Bookmarks = append(f.Bookmarks, b)
+		}
+	}
+	root.sort()
+	return root
+}
+
+// folder returns the subfolder called name, creating it if needed.
+func (f *BookmarkFolder) folder(name string) *BookmarkFolder {
+	for _, sub := range f.Folders {
+		if sub.Name == name {
+			return sub
+		}
+	}
+	path := name
+	if f.Path != "" {
+		path = f.Path + "/" + name
+	}
+	sub := &BookmarkFolder{Name: name, Path: path}
+	f.Folders = append(f.Folders, sub)
+	return sub
+}
+
+// sort orders subfolders by name, recursively.
+func (f *BookmarkFolder) sort() {
+	sort.Slice(f.Folders, func(i, j int) bool {
+		return strings.ToLower(f.Folders[i].Name) < strings.ToLower(f.Folders[j].Name)
+	})
+	for _, sub := range f.Folders {
+		sub.sort()
+	}
+}
+
+// RenderBookmarks formats bookmarks for the viewport as a tree of tag
+// folders. A non-empty tag limits the page to that tag (see
+// Bookmark.HasTag). A bookmark filed under several tags keeps one number.
+func RenderBookmarks(bookmarks []Bookmark, tag string) (string, []browser.Link) {
 	var sb strings.Builder
 	var links []browser.Link
 
-	sb.WriteString("  🔖 Bookmarks\n")
+	if tag != "" {
+		bookmarks = FilterTag(bookmarks, tag)
+		sb.WriteString(fmt.Sprintf("  🔖 Bookmarks tagged %q\n", tag))
+	} else {
+		sb.WriteString("  🔖 Bookmarks\n")
+	}
 	sb.WriteString("  ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
 
 	if len(bookmarks) == 0 {
-		sb.WriteString("  No bookmarks yet. Press 'B' to bookmark a page.\n")
+		if tag != "" {
+			sb.WriteString("  No bookmarks with this tag.\n")
+		} else {
+			sb.WriteString("  No bookmarks yet. Press 'B' to bookmark a page.\n")
+		}
 		return sb.String(), links
 	}
 
-	for i, b := range bookmarks {
-		idx := i + 1
-		sb.WriteString(fmt.Sprintf("  [%d] %s\n", idx, b.Title))
-		sb.WriteString(fmt.Sprintf("       %s\n", b.URL))
-		if len(b.Tags) > 0 {
-			sb.WriteString(fmt.Sprintf("       tags: %s\n", strings.Join(b.Tags, ", ")))
+	numbers := make(map[string]int)
+	var walk func(f *BookmarkFolder, indent string)
+	walk = func(f *BookmarkFolder, indent string) {
+		for _, b := range f.Bookmarks {
+			idx, seen := numbers[b.URL]
+			if !seen {
+				idx = len(links) + 1
+				numbers[b.URL] = idx
+				links = append(links, browser.Link{
+					Index: idx,
+					Text:  b.Title,
+					URL:   b.URL,
+				})
+			}
+			sb.WriteString(fmt.Sprintf("%s  [%d] %s\n", indent, idx, b.Title))
+			sb.WriteString(fmt.Sprintf("%s       %s\n", indent, b.URL))
+			if len(b.Tags) > 1 {
+				sb.WriteString(fmt.Sprintf("%s       tags: %s\n", indent, strings.Join(b.Tags, ", ")))
+			}
+			sb.WriteString(fmt.Sprintf("%s       saved %s\n\n", indent, timeAgoStore(b.CreatedAt)))
+		}
+		for _, sub := range f.Folders {
+			sb.WriteString(fmt.Sprintf("%s  📁 %s (%d)\n\n", indent, sub.Name, sub.Count()))
+			walk(sub, indent+"    ")
 		}
-		sb.WriteString(fmt.Sprintf("       saved %s\n\n", timeAgoStore(b.CreatedAt)))
-
-		links = append(links, browser.Link{
-			Index: idx,
-			Text:  b.Title,
-			URL:   b.URL,
-		})
 	}
+	walk(BookmarkTree(bookmarks), "")
 
 	return sb.String(), links
 }
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBookmarkTags(t *testing.T) {
+	db, err := OpenDB(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	bs := NewBookmarkStore(db)
+	const url = "https://go.dev/blog/pipelines"
+	if !bs.Add(url, "Pipelines", "+go", "concurrency", "go") {
+		t.Fatal("Add of a new bookmark returned false")
+	}
+	if bs.Add(url, "Pipelines") {
+		t.Error("Add of a duplicate returned true")
+	}
+	if b := bs.Get(url); b == nil || !slices.Equal(b.Tags, []string{"go", "concurrency"}) {
+		t.Fatalf("Get = %+v, want tags [go concurrency]", b)
+	}
+
+	bs.AddTags(url, "+lang/go")
+	bs.Rename(url, "Go Concurrency Patterns")
+	b := bs.Get(url)
+	if b.Title != "Go Concurrency Patterns" || !slices.Equal(b.Tags, []string{"go", "concurrency", "lang/go"}) {
+		t.Errorf("after AddTags and Rename: %+v", b)
+	}
+	if !b.HasTag("lang") || !b.HasTag("LANG/go") || b.HasTag("lan") {
+		t.Errorf("HasTag folder matching wrong for %v", b.Tags)
+	}
+
+	bs.SetTags(url, nil)
+	if b := bs.Get(url); len(b.Tags) != 0 {
+		t.Errorf("SetTags(nil) left %v", b.Tags)
+	}
+}
+
+func TestBookmarkTree(t *testing.T) {
+	bookmarks := []Bookmark{
+		{URL: "https://a.example", Title: "A", Tags: []string{"lang/go", "web"}},
+		{URL: "https://b.example", Title: "B", Tags: []string{"lang/rust"}},
+		{URL: "https://c.example", Title: "C"},
+	}
+
+	root := BookmarkTree(bookmarks)
+	if len(root.Bookmarks) != 1 || root.Bookmarks[0].Title != "C" {
+		t.Errorf("root bookmarks = %+v, want only C", root.Bookmarks)
+	}
+	if len(root.Folders) != 2 || root.Folders[0].Name != "lang" || root.Folders[1].Name != "web" {
+		t.Fatalf("root folders = %+v", root.Folders)
+	}
+	lang := root.Folders[0]
+	if lang.Count() != 2 || len(lang.Folders) != 2 || lang.Folders[0].Path != "lang/go" {
+		t.Errorf("lang folder = %+v", lang)
+	}
+
+	content, links := RenderBookmarks(bookmarks, "lang")
+	if len(links) != 2 || strings.Contains(content, "https://c.example") {
+		t.Errorf("RenderBookmarks(tag lang) links = %+v", links)
+	}
+	// A bookmark under two tags keeps one number.
+	if _, links := RenderBookmarks(bookmarks, ""); len(links) != 3 {
+		t.Errorf("RenderBookmarks links = %+v, want 3", links)
+	}
+}
@@ -0,0 +1,52 @@
+package storage
+
+import "database/sql"
+
+// CommandHistoryStore persists the : command line history in SQLite.
+type CommandHistoryStore struct {
+	db      *sql.DB
+	maxSize int
+}
+
+// NewCommandHistoryStore creates a command history store using the given database.
+func NewCommandHistoryStore(db *DB) *CommandHistoryStore {
+	return &CommandHistoryStore{
+		db:      db.Conn(),
+		maxSize: 500,
+	}
+}
+
+// Add records a command line. Repeating a command moves it to the end
+// instead of storing it twice.
+func (cs *CommandHistoryStore) Add(line string) {
+	if line == "" {
+		return
+	}
+	cs.db.Exec(`DELETE FROM command_history WHERE line = ?`, line)
+	cs.db.Exec(`INSERT INTO command_history (line) VALUES (?)`, line)
+	cs.db.Exec(
+		`DELETE FROM command_history WHERE id NOT IN (
+			SELECT id FROM command_history ORDER BY id DESC LIMIT ?
+		)`,
+		cs.maxSize,
+	)
+}
+
+// List returns the command history, oldest first.
+func (cs *CommandHistoryStore) List() []string {
+	rows, err := cs.db.Query(`SELECT line FROM command_history ORDER BY id`)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -10,12 +10,35 @@ import (
 
 // Config holds tsurf user configuration.
 type Config struct {
-	Theme       string   `json:"theme"`
-	Homepage    string   `json:"homepage"`
-	SearchEngine string  `json:"search_engine"` // "duckduckgo" (only option for now)
-	RSSFeeds    []string `json:"rss_feeds"`
-	Subreddits  []string `json:"subreddits"`
-	path        string
+	Theme          string   `json:"theme"`
+	Homepage       string   `json:"homepage"`
+	SearchEngine   string   `json:"search_engine"` // "duckduckgo" (only option for now)
+	RSSFeeds       []string `json:"rss_feeds"`
+	Subreddits     []string `json:"subreddits"`
+	RestoreSession bool     `json:"restore_session"` // reopen last session's tabs on launch
+	CookiePolicy   string   `json:"cookie_policy"`   // "all", "first-party", "allowlist" or "block"
+	CookieAllow    []string `json:"cookie_allow"`    // domains allowed under the "allowlist" policy
+
+	// SiteOrder lists site handlers ("reddit", "github", "hn") to try first;
+	// SitesDisabled turns handlers off so their pages load as plain HTML.
+	SiteOrder     []string `json:"site_order,omitempty"`
+	SitesDisabled []string `json:"sites_disabled,omitempty"`
+
+	// Plugins render the URLs matching a pattern with external commands.
+	Plugins []PluginConfig `json:"plugins,omitempty"`
+
+	// Keys remaps actions per mode: {"normal": {"scroll_down": ["j", "ctrl+n"]}}.
+	Keys map[string]map[string][]string `json:"keys,omitempty"`
+
+	path string
+}
+
+// PluginConfig configures an external-command site handler.
+type PluginConfig struct {
+	Name    string   `json:"name"`
+	Match   string   `json:"match"`             // regular expression for the URLs it renders
+	Command []string `json:"command"`           // executable and arguments
+	Timeout string   `json:"timeout,omitempty"` // e.g. "5s"; default 10s
 }
 
 // DefaultConfig returns the default configuration.
@@ -33,12 +56,14 @@ func DefaultConfig() Config {
 			"golang",
 			"linux",
 		},
+		RestoreSession: true,
+		CookiePolicy:   CookiePolicyFirstParty,
 	}
 }
 
 // LoadConfig loads configuration from the standard config directory.
 func LoadConfig() (*Config, error) {
-	dir, err := configDir()
+	dir, err := ConfigDir()
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +93,7 @@ func LoadConfig() (*Config, error) {
 // Save writes the configuration to disk.
 func (c *Config) Save() error {
 	if c.path == "" {
-		dir, err := configDir()
+		dir, err := ConfigDir()
 		if err != nil {
 			return err
 		}
@@ -117,7 +142,8 @@ func DataDir() (string, error) {
 	return dir, nil
 }
 
-func configDir() (string, error) {
+// ConfigDir returns the directory holding config.json and custom themes.
+func ConfigDir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("getting home dir: %w", err)
@@ -0,0 +1,457 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vidyasagar/tsurf/internal/browser"
+	"golang.org/x/net/publicsuffix"
+)
+
+// Cookie policies, set with "cookie_policy" in the config.
+const (
+	CookiePolicyAll        = "all"         // accept every cookie
+	CookiePolicyFirstParty = "first-party" // only cookies for the site being loaded
+	CookiePolicyAllowList  = "allowlist"   // only cookies for domains in "cookie_allow"
+	CookiePolicyBlock      = "block"       // no cookies at all
+)
+
+// CookiePolicies lists the valid cookie policies.
+var CookiePolicies = []string{
+	CookiePolicyAll,
+	CookiePolicyFirstParty,
+	CookiePolicyAllowList,
+	CookiePolicyBlock,
+}
+
+// Cookie is a stored HTTP cookie.
+type Cookie struct {
+	Domain   string // without leading dot
+	Path     string
+	Name     string
+	Value    string
+	Expires  time.Time // zero for session cookies
+	Secure   bool
+	HTTPOnly bool
+	HostOnly bool // sent to Domain only, not its subdomains
+}
+
+// CookieJar is an http.CookieJar persisted in SQLite. The policy decides
+// which sites may set and receive cookies.
+type CookieJar struct {
+	db *sql.DB
+
+	mu     sync.RWMutex
+	policy string
+	allow  []string
+}
+
+// NewCookieJar creates a cookie jar using the given database, dropping any
+// cookies that expired since the last run. Session cookies from the last
+// run are dropped too: they end with the browser session.
+func NewCookieJar(db *DB, policy string, allow []string) *CookieJar {
+	j := &CookieJar{db: db.Conn()}
+	j.SetPolicy(policy, allow)
+	j.db.Exec(`DELETE FROM cookies WHERE expires <= ?`, time.Now().Unix())
+	return j
+}
+
+// SetPolicy changes the cookie policy. Unknown policies fall back to
+// first-party.
+func (j *CookieJar) SetPolicy(policy string, allow []string) {
+	if !ValidCookiePolicy(policy) {
+		policy = CookiePolicyFirstParty
+	}
+
+	var domains []string
+	for _, d := range allow {
+		d = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(d)), ".")
+		if d != "" {
+			domains = append(domains, d)
+		}
+	}
+
+	j.mu.Lock()
+	j.policy = policy
+	j.allow = domains
+	j.mu.Unlock()
+}
+
+// Policy returns the current cookie policy.
+func (j *CookieJar) Policy() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.policy
+}
+
+// ValidCookiePolicy reports whether policy is a known cookie policy.
+func ValidCookiePolicy(policy string) bool {
+	for _, p := range CookiePolicies {
+		if p == policy {
+			return true
+		}
+	}
+	return false
+}
+
+// ForSite returns a view of the jar for requests made while loading site,
+// so redirects to other sites count as third-party under the first-party
+// policy.
+func (j *CookieJar) ForSite(site *url.URL) http.CookieJar {
+	return &siteJar{jar: j, site: strings.ToLower(site.Hostname())}
+}
+
+// SetCookies implements http.CookieJar, treating each URL as its own site.
+func (j *CookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.setCookies(strings.ToLower(u.Hostname()), u, cookies)
+}
+
+// Cookies implements http.CookieJar, treating each URL as its own site.
+func (j *CookieJar) Cookies(u *url.URL) []*http.Cookie {
+	return j.cookies(strings.ToLower(u.Hostname()), u)
+}
+
+// siteJar is a CookieJar scoped to a top-level site.
+type siteJar struct {
+	jar  *CookieJar
+	site string
+}
+
+// SetCookies implements http.CookieJar.
+func (s *siteJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	s.jar.setCookies(s.site, u, cookies)
+}
+
+// Cookies implements http.CookieJar.
+func (s *siteJar) Cookies(u *url.URL) []*http.Cookie {
+	return s.jar.cookies(s.site, u)
+}
+
+// allowed reports whether host may use cookies while site is being loaded.
+func (j *CookieJar) allowed(site, host string) bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	switch j.policy {
+	case CookiePolicyBlock:
+		return false
+	case CookiePolicyFirstParty:
+		return registrableDomain(site) == registrableDomain(host)
+	case CookiePolicyAllowList:
+		for _, d := range j.allow {
+			if domainMatch(host, d) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// setCookies validates and stores cookies received from u.
+func (j *CookieJar) setCookies(site string, u *url.URL, cookies []*http.Cookie) {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return
+	}
+	host := strings.ToLower(u.Hostname())
+	if !j.allowed(site, host) {
+		return
+	}
+
+	now := time.Now()
+	for _, c := range cookies {
+		sc := Cookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HTTPOnly: c.HttpOnly,
+		}
+		if sc.Path == "" || sc.Path[0] != '/' {
+			sc.Path = defaultPath(u.Path)
+		}
+
+		domain := strings.TrimPrefix(strings.ToLower(c.Domain), ".")
+		switch {
+		case domain == "" || domain == host:
+			sc.Domain = host
+			sc.HostOnly = domain == ""
+		case !domainMatch(host, domain):
+			continue // cookie for an unrelated domain
+		default:
+			if suffix, _ := publicsuffix.PublicSuffix(domain); suffix == domain {
+				continue // cookie for a whole public suffix such as "co.uk"
+			}
+			sc.Domain = domain
+		}
+
+		switch {
+		case c.MaxAge < 0:
+			j.delete(sc.Domain, sc.Name, sc.Path)
+			continue
+		case c.MaxAge > 0:
+			sc.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+		case !c.Expires.IsZero():
+			if !c.Expires.After(now) {
+				j.delete(sc.Domain, sc.Name, sc.Path)
+				continue
+			}
+			sc.Expires = c.Expires
+		}
+
+		j.save(sc)
+	}
+}
+
+// cookies returns the stored cookies to send to u.
+func (j *CookieJar) cookies(site string, u *url.URL) []*http.Cookie {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil
+	}
+	host := strings.ToLower(u.Hostname())
+	if !j.allowed(site, host) {
+		return nil
+	}
+
+	// Candidate domains: the host and each parent domain.
+	var domains []any
+	for d := host; d != ""; {
+		domains = append(domains, d)
+		i := strings.IndexByte(d, '.')
+		if i < 0 {
+			break
+		}
+		d = d[i+1:]
+	}
+
+	query := fmt.Sprintf(
+		`SELECT domain, path, name, value, expires, secure, http_only, host_only
+		 FROM cookies WHERE domain IN (?%s) AND (expires = 0 OR expires > ?)`,
+		strings.Repeat(", ?", len(domains)-1),
+	)
+	rows, err := j.db.Query(query, append(domains, time.Now().Unix())...)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	reqPath := u.Path
+	if reqPath == "" {
+		reqPath = "/"
+	}
+
+	var matched []Cookie
+	for _, c := range scanCookies(rows) {
+		if c.HostOnly && c.Domain != host {
+			continue
+		}
+		if c.Secure && u.Scheme != "https" {
+			continue
+		}
+		if !pathMatch(reqPath, c.Path) {
+			continue
+		}
+		matched = append(matched, c)
+	}
+
+	// More specific paths first, as browsers do.
+	sort.SliceStable(matched, func(a, b int) bool {
+		return len(matched[a].Path) > len(matched[b].Path)
+	})
+
+	cookies := make([]*http.Cookie, len(matched))
+	for i, c := range matched {
+		cookies[i] = &http.Cookie{Name: c.Name, Value: c.Value}
+	}
+	return cookies
+}
+
+// save inserts or replaces a cookie.
+func (j *CookieJar) save(c Cookie) {
+	var expires int64
+	if !c.Expires.IsZero() {
+		expires = c.Expires.Unix()
+	}
+	j.db.Exec(
+		`INSERT INTO cookies (domain, path, name, value, expires, secure, http_only, host_only)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(domain, path, name) DO UPDATE SET
+		   value = excluded.value, expires = excluded.expires, secure = excluded.secure,
+		   http_only = excluded.http_only, host_only = excluded.host_only`,
+		c.Domain, c.Path, c.Name, c.Value, expires, c.Secure, c.HTTPOnly, c.HostOnly,
+	)
+}
+
+// delete removes a single cookie. Returns false if not found.
+func (j *CookieJar) delete(domain, name, path string) bool {
+	res, err := j.db.Exec(
+		`DELETE FROM cookies WHERE domain = ? AND name = ? AND path = ?`,
+		domain, name, path,
+	)
+	if err != nil {
+		return false
+	}
+	n, _ := res.RowsAffected()
+	return n > 0
+}
+
+// All returns every unexpired cookie, grouped by domain.
+func (j *CookieJar) All() []Cookie {
+	rows, err := j.db.Query(
+		`SELECT domain, path, name, value, expires, secure, http_only, host_only
+		 FROM cookies WHERE expires = 0 OR expires > ?
+		 ORDER BY domain, name, path`,
+		time.Now().Unix(),
+	)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	return scanCookies(rows)
+}
+
+// Delete removes a cookie. Returns false if not found.
+func (j *CookieJar) Delete(c Cookie) bool {
+	return j.delete(c.Domain, c.Name, c.Path)
+}
+
+// DeleteDomain removes all cookies for a domain and its subdomains.
+// Returns the number of cookies removed.
+func (j *CookieJar) DeleteDomain(domain string) int {
+	domain = strings.TrimPrefix(strings.ToLower(domain), ".")
+	res, err := j.db.Exec(
+		`DELETE FROM cookies WHERE domain = ? OR domain LIKE ?`,
+		domain, "%."+domain,
+	)
+	if err != nil {
+		return 0
+	}
+	n, _ := res.RowsAffected()
+	return int(n)
+}
+
+// Clear removes all cookies.
+func (j *CookieJar) Clear() {
+	j.db.Exec(`DELETE FROM cookies`)
+}
+
+// scanCookies reads cookie rows.
+func scanCookies(rows *sql.Rows) []Cookie {
+	var cookies []Cookie
+	for rows.Next() {
+		var c Cookie
+		var expires int64
+		if err := rows.Scan(&c.Domain, &c.Path, &c.Name, &c.Value, &expires,
+			&c.Secure, &c.HTTPOnly, &c.HostOnly); err != nil {
+			continue
+		}
+		if expires != 0 {
+			c.Expires = time.Unix(expires, 0)
+		}
+		cookies = append(cookies, c)
+	}
+	return cookies
+}
+
+// registrableDomain returns the eTLD+1 of host, e.g. "example.co.uk".
+func registrableDomain(host string) string {
+	d, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return host
+	}
+	return d
+}
+
+// domainMatch reports whether host is domain or one of its subdomains.
+func domainMatch(host, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// pathMatch implements RFC 6265 path matching.
+func pathMatch(reqPath, cookiePath string) bool {
+	if reqPath == cookiePath {
+		return true
+	}
+	if !strings.HasPrefix(reqPath, cookiePath) {
+		return false
+	}
+	return strings.HasSuffix(cookiePath, "/") || reqPath[len(cookiePath)] == '/'
+}
+
+// defaultPath returns the default cookie path for a request path.
+func defaultPath(p string) string {
+	i := strings.LastIndex(p, "/")
+	if i <= 0 {
+		return "/"
+	}
+	return p[:i]
+}
+
+// RenderCookies formats cookies for the viewport, grouped by domain and
+// numbered for deletion with :cookies rm.
+func RenderCookies(cookies []Cookie, policy string) (string, []browser.Link) {
+	var sb strings.Builder
+	var links []browser.Link
+
+	sb.WriteString(fmt.Sprintf("  🍪 Cookies (policy: %s)\n", policy))
+	sb.WriteString("  ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	if len(cookies) == 0 {
+		sb.WriteString("  No cookies stored.\n")
+		return sb.String(), links
+	}
+
+	domain := ""
+	for i, c := range cookies {
+		idx := i + 1
+		if c.Domain != domain {
+			if domain != "" {
+				sb.WriteString("\n")
+			}
+			domain = c.Domain
+			sb.WriteString(fmt.Sprintf("  %s\n", domain))
+		}
+
+		value := c.Value
+		if len(value) > 32 {
+			value = value[:29] + "..."
+		}
+		sb.WriteString(fmt.Sprintf("    [%d] %s = %s\n", idx, c.Name, value))
+
+		var attrs []string
+		if c.Expires.IsZero() {
+			attrs = append(attrs, "session")
+		} else {
+			attrs = append(attrs, "expires "+c.Expires.Format("2006-01-02"))
+		}
+		if c.Path != "/" {
+			attrs = append(attrs, "path "+c.Path)
+		}
+		if c.Secure {
+			attrs = append(attrs, "secure")
+		}
+		if c.HTTPOnly {
+			attrs = append(attrs, "httponly")
+		}
+		if !c.HostOnly {
+			attrs = append(attrs, "subdomains")
+		}
+		sb.WriteString(fmt.Sprintf("         %s\n", strings.Join(attrs, ", ")))
+
+		links = append(links, browser.Link{
+			Index: idx,
+			Text:  c.Domain + " " + c.Name,
+			URL:   "https://" + c.Domain + "/",
+		})
+	}
+
+	sb.WriteString("\n  Delete with :cookies rm <n>[,<n>|<n>-<m>] or :cookies rm <domain>\n")
+	return sb.String(), links
+}
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func newTestJar(t *testing.T, policy string, allow ...string) *CookieJar {
+	db, err := OpenDB(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewCookieJar(db, policy, allow)
+}
+
+func mustURL(t *testing.T, raw string) *url.URL {
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func cookieNames(cookies []*http.Cookie) map[string]bool {
+	names := make(map[string]bool)
+	for _, c := range cookies {
+		names[c.Name] = true
+	}
+	return names
+}
+
+func TestCookieJarMatching(t *testing.T) {
+	jar := newTestJar(t, CookiePolicyAll)
+	jar.SetCookies(mustURL(t, "https://www.example.com/app/login"), []*http.Cookie{
+		{Name: "host", Value: "1", Path: "/"},
+		{Name: "domain", Value: "2", Domain: ".example.com", Path: "/"},
+		{Name: "secure", Value: "3", Secure: true, Path: "/"},
+		{Name: "scoped", Value: "4"}, // defaults to the /app directory
+		{Name: "suffix", Value: "5", Domain: "com"},
+	})
+
+	tests := []struct {
+		url  string
+		want []string
+	}{
+		{"https://www.example.com/app/x", []string{"host", "domain", "secure", "scoped"}},
+		{"http://www.example.com/", []string{"host", "domain"}},
+		{"https://api.example.com/", []string{"domain"}},
+		{"https://www.example.com/application", []string{"host", "domain", "secure"}},
+		{"https://other.com/", nil},
+	}
+
+	for _, tt := range tests {
+		got := cookieNames(jar.Cookies(mustURL(t, tt.url)))
+		if len(got) != len(tt.want) {
+			t.Errorf("Cookies(%s) = %v, want %v", tt.url, got, tt.want)
+			continue
+		}
+		for _, name := range tt.want {
+			if !got[name] {
+				t.Errorf("Cookies(%s) missing %q, got %v", tt.url, name, got)
+			}
+		}
+	}
+}
+
+func TestCookieJarExpiry(t *testing.T) {
+	jar := newTestJar(t, CookiePolicyAll)
+	u := mustURL(t, "https://example.com/")
+
+	jar.SetCookies(u, []*http.Cookie{{Name: "a", Value: "1", MaxAge: 3600}})
+	if len(jar.Cookies(u)) != 1 {
+		t.Fatal("expected cookie to be stored")
+	}
+
+	// Max-Age=0 in a header parses as MaxAge -1: delete the cookie.
+	jar.SetCookies(u, []*http.Cookie{{Name: "a", MaxAge: -1}})
+	if len(jar.Cookies(u)) != 0 {
+		t.Error("expected cookie to be deleted")
+	}
+}
+
+func TestCookieJarPolicies(t *testing.T) {
+	site := mustURL(t, "https://news.example.com/")
+	tracker := mustURL(t, "https://tracker.net/")
+	sub := mustURL(t, "https://login.example.com/")
+
+	tests := []struct {
+		policy string
+		allow  []string
+		target *url.URL
+		want   bool
+	}{
+		{CookiePolicyAll, nil, tracker, true},
+		{CookiePolicyBlock, nil, sub, false},
+		{CookiePolicyFirstParty, nil, sub, true},
+		{CookiePolicyFirstParty, nil, tracker, false},
+		{CookiePolicyAllowList, []string{"tracker.net"}, tracker, true},
+		{CookiePolicyAllowList, []string{"tracker.net"}, sub, false},
+	}
+
+	for _, tt := range tests {
+		jar := newTestJar(t, tt.policy, tt.allow...).ForSite(site)
+		jar.SetCookies(tt.target, []*http.Cookie{{Name: "c", Value: "1"}})
+		if got := len(jar.Cookies(tt.target)) == 1; got != tt.want {
+			t.Errorf("policy %s: cookie for %s stored = %v, want %v", tt.policy, tt.target.Host, got, tt.want)
+		}
+	}
+}
+
+func TestCookieJarDropsSessionCookiesOnRestart(t *testing.T) {
+	db, err := OpenDB(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	u := mustURL(t, "https://example.com/")
+
+	jar := NewCookieJar(db, CookiePolicyAll, nil)
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "session", Value: "1"},
+		{Name: "persistent", Value: "2", MaxAge: 3600},
+	})
+
+	got := cookieNames(NewCookieJar(db, CookiePolicyAll, nil).Cookies(u))
+	if got["session"] || !got["persistent"] || len(got) != 1 {
+		t.Errorf("cookies after restart = %v, want only persistent", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
@@ -77,7 +78,10 @@ func (db *DB) migrate() error {
 		url        TEXT    NOT NULL UNIQUE,
 		title      TEXT    NOT NULL DEFAULT '',
 		is_read    INTEGER NOT NULL DEFAULT 0,
-		created_at DATETIME NOT NULL DEFAULT (datetime('now'))
+		created_at DATETIME NOT NULL DEFAULT (datetime('now')),
+		html       TEXT    NOT NULL DEFAULT '', -- article snapshot for offline reading
+		text       TEXT    NOT NULL DEFAULT '',
+		progress   INTEGER NOT NULL DEFAULT 0   -- percent scrolled
 	);
 
 	CREATE TABLE IF NOT EXISTS history (
@@ -87,12 +91,99 @@ func (db *DB) migrate() error {
 		visited_at DATETIME NOT NULL DEFAULT (datetime('now'))
 	);
 
+	CREATE TABLE IF NOT EXISTS sessions (
+		name       TEXT    PRIMARY KEY,
+		data       TEXT    NOT NULL,
+		updated_at DATETIME NOT NULL DEFAULT (datetime('now'))
+	);
+
+	CREATE TABLE IF NOT EXISTS cookies (
+		domain    TEXT    NOT NULL,
+		path      TEXT    NOT NULL,
+		name      TEXT    NOT NULL,
+		value     TEXT    NOT NULL DEFAULT '',
+		expires   INTEGER NOT NULL DEFAULT 0, -- unix seconds, 0 for session cookies
+		secure    INTEGER NOT NULL DEFAULT 0,
+		http_only INTEGER NOT NULL DEFAULT 0,
+		host_only INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (domain, path, name)
+	);
+
+	CREATE TABLE IF NOT EXISTS command_history (
+		id   INTEGER PRIMARY KEY AUTOINCREMENT,
+		line TEXT    NOT NULL
+	);
+
+	-- Readable text of visited pages for :grep, one row per URL.
+	CREATE VIRTUAL TABLE IF NOT EXISTS page_text USING fts5(
+		url UNINDEXED,
+		title,
+		content,
+		visited_at UNINDEXED,
+		tokenize = 'porter unicode61'
+	);
+
 	CREATE INDEX IF NOT EXISTS idx_history_visited_at ON history(visited_at DESC);
 	CREATE INDEX IF NOT EXISTS idx_history_url ON history(url);
 	CREATE INDEX IF NOT EXISTS idx_bookmarks_url ON bookmarks(url);
 	CREATE INDEX IF NOT EXISTS idx_read_later_url ON read_later(url);
 	`
 
-	_, err := db.conn.Exec(schema)
+	if _, err := db.conn.Exec(schema); err != nil {
+		return err
+	}
+
+	// Columns added after the first release, missing from older databases.
+	for _, c := range []struct{ table, column, decl string }{
+		{"read_later", "html", "TEXT NOT NULL DEFAULT ''"},
+		{"read_later", "text", "TEXT NOT NULL DEFAULT ''"},
+		{"read_later", "progress", "INTEGER NOT NULL DEFAULT 0"},
+	} {
+		if err := db.addColumn(c.table, c.column, c.decl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumn adds a column to a table unless it already has it.
+func (db *DB) addColumn(table, column, decl string) error {
+	rows, err := db.conn.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name, typ  string
+			notNull    int
+			defaultVal sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &defaultVal, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	_, err = db.conn.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, decl))
 	return err
 }
+
+// parseDBTime parses a DATETIME column scanned into a string. The driver
+// reports it as RFC 3339, though SQLite stores "2006-01-02 15:04:05".
+func parseDBTime(s string) time.Time {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t
+	}
+	t, _ := time.Parse("2006-01-02 15:04:05", s)
+	return t
+}
@@ -63,6 +63,7 @@ func (hs *HistoryStore) Add(url, title string) {
 		)`,
 		hs.maxSize,
 	)
+	hs.db.Exec(`DELETE FROM page_text WHERE url NOT IN (SELECT url FROM history)`)
 }
 
 // List returns all history entries, newest first.
@@ -97,10 +98,11 @@ func (hs *HistoryStore) Search(query string) []HistoryEntry {
 func (hs *HistoryStore) Remove(idx int) bool {
 	// Get the ID of the entry at the given index.
 	var id int64
+	var url string
 	err := hs.db.QueryRow(
-		`SELECT id FROM history ORDER BY visited_at DESC LIMIT 1 OFFSET ?`,
+		`SELECT id, url FROM history ORDER BY visited_at DESC LIMIT 1 OFFSET ?`,
 		idx,
-	).Scan(&id)
+	).Scan(&id, &url)
 	if err != nil {
 		return false
 	}
@@ -110,12 +112,19 @@ func (hs *HistoryStore) Remove(idx int) bool {
 		return false
 	}
 	n, _ := res.RowsAffected()
+
+	// Forget the page text once no visit to the page is left.
+	hs.db.Exec(
+		`DELETE FROM page_text WHERE url = ? AND NOT EXISTS (SELECT 1 FROM history WHERE url = ?)`,
+		url, url,
+	)
 	return n > 0
 }
 
-// Clear removes all history entries.
+// Clear removes all history entries and the indexed page text.
 func (hs *HistoryStore) Clear() {
 	hs.db.Exec(`DELETE FROM history`)
+	hs.db.Exec(`DELETE FROM page_text`)
 }
 
 // Count returns the number of history entries.
@@ -133,7 +142,7 @@ func scanHistoryEntries(rows *sql.Rows) []HistoryEntry {
 		if err := rows.Scan(&e.ID, &e.URL, &e.Title, &visitedAt); err != nil {
 			continue
 		}
-		e.VisitedAt, _ = time.Parse("2006-01-02 15:04:05", visitedAt)
+		e.VisitedAt = parseDBTime(visitedAt)
 		entries = append(entries, e)
 	}
 	return entries
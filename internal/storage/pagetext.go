@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxPageText bounds the text indexed per page.
+const maxPageText = 256 << 10
+
+// Snippet highlight markers around the matched terms in PageMatch.Snippet.
+const (
+	MatchStart = "\x02"
+	MatchEnd   = "\x03"
+)
+
+// PageMatch is a visited page found by its content.
+type PageMatch struct {
+	URL       string
+	Title     string
+	Snippet   string // text around the matches, which are wrapped in MatchStart and MatchEnd
+	VisitedAt time.Time
+}
+
+// IndexPage stores the readable text of a visited page in the full-text
+// index, replacing what was indexed for the URL before.
+func (hs *HistoryStore) IndexPage(url, title, text string) {
+	if url == "" || strings.TrimSpace(text) == "" {
+		return
+	}
+	text = truncateText(text, maxPageText)
+	hs.db.Exec(`DELETE FROM page_text WHERE url = ?`, url)
+	hs.db.Exec(
+		`INSERT INTO page_text (url, title, content, visited_at) VALUES (?, ?, ?, datetime('now'))`,
+		url, title, text,
+	)
+}
+
+// truncateText cuts text to at most n bytes without splitting a character.
+func truncateText(text string, n int) string {
+	if len(text) <= n {
+		return text
+	}
+	for n > 0 && !utf8.RuneStart(text[n]) {
+		n--
+	}
+	return text[:n]
+}
+
+// Grep finds visited pages whose title or text contain all the terms, best
+// matches first. A term ending in * matches any word it starts.
+func (hs *HistoryStore) Grep(terms string, limit int) ([]PageMatch, error) {
+	query := ftsQuery(terms)
+	if query == "" {
+		return nil, nil
+	}
+
+	// bm25 weighs each column; title matches count five times as much.
+	rows, err := hs.db.Query(
+		`SELECT url, title, snippet(page_text, 2, ?, ?, '…', 24), visited_at
+		 FROM page_text WHERE page_text MATCH ?
+		 ORDER BY bm25(page_text, 0, 5.0, 1.0) LIMIT ?`,
+		MatchStart, MatchEnd, query, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []PageMatch
+	for rows.Next() {
+		var pm PageMatch
+		var visitedAt string
+		if err := rows.Scan(&pm.URL, &pm.Title, &pm.Snippet, &visitedAt); err != nil {
+			continue
+		}
+		pm.VisitedAt = parseDBTime(visitedAt)
+		matches = append(matches, pm)
+	}
+	return matches, rows.Err()
+}
+
+// ftsQuery turns search terms into an FTS5 query matching all of them.
+// Each term is quoted so punctuation in it is not read as query syntax.
+func ftsQuery(terms string) string {
+	var parts []string
+	for _, term := range strings.Fields(terms) {
+		prefix := strings.HasSuffix(term, "*")
+		term = strings.TrimRight(term, "*")
+		if term == "" {
+			continue
+		}
+		part := `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+		if prefix {
+			part += "*"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
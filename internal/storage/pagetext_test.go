@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestGrep(t *testing.T) {
+	db, err := OpenDB(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	hs := NewHistoryStore(db)
+	pages := []struct{ url, title, text string }{
+		{"https://a.example/leaks", "Finding goroutine leaks", "A goroutine blocked on a channel forever is a leak."},
+		{"https://b.example/gc", "Garbage collection", "The collector does not free goroutines that are leaking."},
+		{"https://c.example/cats", "Cats", "Nothing about Go here."},
+	}
+	for _, p := range pages {
+		hs.Add(p.url, p.title)
+		hs.IndexPage(p.url, p.title, p.text)
+	}
+
+	matches, err := hs.Grep("goroutine leaks", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("Grep returned %d matches, want 2: %+v", len(matches), matches)
+	}
+	if matches[0].URL != "https://a.example/leaks" {
+		t.Errorf("best match = %s, want the page with the terms in its title", matches[0].URL)
+	}
+	if !strings.Contains(matches[0].Snippet, MatchStart+"goroutine"+MatchEnd) {
+		t.Errorf("snippet %q has no highlighted match", matches[0].Snippet)
+	}
+	// :grep shows when each page was visited.
+	if age := time.Since(matches[0].VisitedAt); age < 0 || age > time.Hour {
+		t.Errorf("VisitedAt = %v, want the time of the visit", matches[0].VisitedAt)
+	}
+
+	// Punctuation is searched for, not parsed as query syntax.
+	if _, err := hs.Grep(`"unbalanced AND (`, 10); err != nil {
+		t.Errorf("Grep with punctuation: %v", err)
+	}
+
+	hs.Clear()
+	if matches, _ := hs.Grep("goroutine", 10); len(matches) != 0 {
+		t.Errorf("Grep after Clear = %+v", matches)
+	}
+}
+
+func TestParseDBTime(t *testing.T) {
+	want := time.Date(2026, 3, 1, 14, 30, 5, 0, time.UTC)
+	// The driver reports DATETIME columns as RFC 3339; CURRENT_TIMESTAMP
+	// stores the SQLite format.
+	for _, s := range []string{"2026-03-01T14:30:05Z", "2026-03-01 14:30:05"} {
+		if got := parseDBTime(s); !got.Equal(want) {
+			t.Errorf("parseDBTime(%q) = %v, want %v", s, got, want)
+		}
+	}
+	if got := parseDBTime("yesterday"); !got.IsZero() {
+		t.Errorf("parseDBTime of garbage = %v, want zero", got)
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	text := "naïve café"
+	for n := 0; n <= len(text)+1; n++ {
+		got := truncateText(text, n)
+		if len(got) > n || !utf8.ValidString(got) || !strings.HasPrefix(text, got) {
+			t.Errorf("truncateText(%q, %d) = %q", text, n, got)
+		}
+	}
+	if got := truncateText(text, 3); got != "na" {
+		t.Errorf("truncateText cut inside ï: %q", got)
+	}
+}
@@ -16,6 +16,8 @@ type ReadLaterItem struct {
 	Title     string
 	CreatedAt time.Time
 	Read      bool
+	Progress  int  // percent of the page scrolled through
+	Archived  bool // a snapshot of the article is stored
 }
 
 // ReadLaterStore manages the read-later queue in SQLite.
@@ -47,6 +49,53 @@ func (rl *ReadLaterStore) Remove(url string) bool {
 	return n > 0
 }
 
+// SaveSnapshot stores the article of a queued page so it can be read
+// without the network.
+func (rl *ReadLaterStore) SaveSnapshot(url string, article *browser.Article) {
+	rl.db.Exec(
+		`UPDATE read_later SET html = ?, text = ? WHERE url = ?`,
+		article.Content, article.TextContent, url,
+	)
+}
+
+// Snapshot returns the stored article of an unread item, or nil if the item
+// is read, not queued or was saved without one.
+func (rl *ReadLaterStore) Snapshot(url string) *browser.Article {
+	var title, html, text string
+	err := rl.db.QueryRow(
+		`SELECT title, html, text FROM read_later WHERE url = ? AND is_read = 0 AND html != ''`,
+		url,
+	).Scan(&title, &html, &text)
+	if err != nil {
+		return nil
+	}
+	return &browser.Article{
+		Title:       title,
+		Content:     html,
+		TextContent: text,
+		URL:         url,
+		FinalURL:    url,
+	}
+}
+
+// Progress returns how far through a queued page the reader got, in
+// percent. ok is false if the page is not queued.
+func (rl *ReadLaterStore) Progress(url string) (percent int, ok bool) {
+	err := rl.db.QueryRow(`SELECT progress FROM read_later WHERE url = ?`, url).Scan(&percent)
+	return percent, err == nil
+}
+
+// SetProgress records the reading position of a queued page. Reaching the
+// end marks it read.
+func (rl *ReadLaterStore) SetProgress(url string, percent int) {
+	percent = min(max(percent, 0), 100)
+	rl.db.Exec(
+		`UPDATE read_later SET progress = ?, is_read = CASE WHEN ? >= 100 THEN 1 ELSE is_read END
+		 WHERE url = ?`,
+		percent, percent, url,
+	)
+}
+
 // MarkRead marks an item as read.
 func (rl *ReadLaterStore) MarkRead(url string) {
 	rl.db.Exec(`UPDATE read_later SET is_read = 1 WHERE url = ?`, url)
@@ -55,7 +104,7 @@ func (rl *ReadLaterStore) MarkRead(url string) {
 // ListUnread returns unread items, oldest first.
 func (rl *ReadLaterStore) ListUnread() []ReadLaterItem {
 	rows, err := rl.db.Query(
-		`SELECT id, url, title, is_read, created_at FROM read_later
+		`SELECT id, url, title, is_read, created_at, progress, html != '' FROM read_later
 		 WHERE is_read = 0 ORDER BY created_at ASC`,
 	)
 	if err != nil {
@@ -68,7 +117,7 @@ func (rl *ReadLaterStore) ListUnread() []ReadLaterItem {
 // ListAll returns all items, newest first.
 func (rl *ReadLaterStore) ListAll() []ReadLaterItem {
 	rows, err := rl.db.Query(
-		`SELECT id, url, title, is_read, created_at FROM read_later ORDER BY created_at DESC`,
+		`SELECT id, url, title, is_read, created_at, progress, html != '' FROM read_later ORDER BY created_at DESC`,
 	)
 	if err != nil {
 		return nil
@@ -97,11 +146,11 @@ func scanReadLaterItems(rows *sql.Rows) []ReadLaterItem {
 		var item ReadLaterItem
 		var isRead int
 		var createdAt string
-		if err := rows.Scan(&item.ID, &item.URL, &item.Title, &isRead, &createdAt); err != nil {
+		if err := rows.Scan(&item.ID, &item.URL, &item.Title, &isRead, &createdAt, &item.Progress, &item.Archived); err != nil {
 			continue
 		}
 		item.Read = isRead == 1
-		item.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+		item.CreatedAt = parseDBTime(createdAt)
 		items = append(items, item)
 	}
 	return items
@@ -128,7 +177,18 @@ func RenderReadLater(items []ReadLaterItem) (string, []browser.Link) {
 		}
 		sb.WriteString(fmt.Sprintf("  [%d]%s %s\n", idx, status, item.Title))
 		sb.WriteString(fmt.Sprintf("       %s\n", item.URL))
-		sb.WriteString(fmt.Sprintf("       added %s\n\n", timeAgoStore(item.CreatedAt)))
+		var state []string
+		switch {
+		case item.Read:
+			state = append(state, "read")
+		case item.Progress > 0:
+			state = append(state, fmt.Sprintf("%d%% read", item.Progress))
+		}
+		if item.Archived {
+			state = append(state, "saved offline")
+		}
+		state = append(state, "added "+timeAgoStore(item.CreatedAt))
+		sb.WriteString(fmt.Sprintf("       %s\n\n", strings.Join(state, " · ")))
 
 		links = append(links, browser.Link{
 			Index: idx,
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/vidyasagar/tsurf/internal/browser"
+)
+
+func TestReadLaterSnapshotAndProgress(t *testing.T) {
+	db, err := OpenDB(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	rl := NewReadLaterStore(db)
+	const url = "https://example.com/post"
+	rl.Add(url, "Post")
+	rl.SaveSnapshot(url, &browser.Article{Content: "<p>Hello</p>", TextContent: "Hello"})
+
+	if a := rl.Snapshot(url); a == nil || a.Content != "<p>Hello</p>" || a.Title != "Post" {
+		t.Fatalf("Snapshot = %+v", a)
+	}
+
+	rl.SetProgress(url, 42)
+	items := rl.ListAll()
+	if len(items) != 1 || items[0].Progress != 42 || !items[0].Archived || items[0].Read {
+		t.Fatalf("after SetProgress(42): %+v", items)
+	}
+
+	rl.SetProgress(url, 100)
+	if items := rl.ListAll(); !items[0].Read {
+		t.Errorf("reaching the end did not mark the item read: %+v", items[0])
+	}
+	if a := rl.Snapshot(url); a != nil {
+		t.Errorf("Snapshot of a read item = %+v, want nil", a)
+	}
+	if _, ok := rl.Progress("https://example.com/other"); ok {
+		t.Error("Progress reported an unqueued page")
+	}
+}
+
+func TestMigrateAddsColumns(t *testing.T) {
+	dir := t.TempDir()
+	db, err := OpenDB(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Recreate the table as older releases did.
+	if _, err := db.Conn().Exec(`DROP TABLE read_later;
+		CREATE TABLE read_later (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, url TEXT NOT NULL UNIQUE,
+			title TEXT NOT NULL DEFAULT '', is_read INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL DEFAULT (datetime('now')))`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	db, err = OpenDB(dir)
+	if err != nil {
+		t.Fatalf("reopening an old database: %v", err)
+	}
+	defer db.Close()
+	rl := NewReadLaterStore(db)
+	rl.Add("https://example.com/", "Example")
+	rl.SetProgress("https://example.com/", 10)
+	if items := rl.ListAll(); len(items) != 1 || items[0].Progress != 10 {
+		t.Errorf("items after migration = %+v", items)
+	}
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// LastSession is the name of the session saved automatically on quit and
+// restored on the next launch.
+const LastSession = "last"
+
+// Session is a snapshot of the open tabs and split layout.
+type Session struct {
+	Name      string       `json:"-"`
+	Tabs      []SessionTab `json:"tabs"`
+	ActiveTab int          `json:"active_tab"` // index into Tabs
+	Split     SessionSplit `json:"split"`
+	UpdatedAt time.Time    `json:"-"`
+}
+
+// SessionTab is the saved state of a single tab.
+type SessionTab struct {
+	URL        string   `json:"url"`
+	Title      string   `json:"title"`
+	History    []string `json:"history"`
+	HistoryPos int      `json:"history_pos"`
+	Scroll     int      `json:"scroll"` // viewport line offset
+}
+
+// SessionSplit is the saved split layout. Direction uses ui.SplitDirection
+// values; zero means no split.
+type SessionSplit struct {
+	Direction int     `json:"direction"`
+	Ratio     float64 `json:"ratio"`
+	Panes     [2]int  `json:"panes"` // indexes into Tabs
+	Active    int     `json:"active"`
+}
+
+// SessionStore manages named sessions persisted in SQLite.
+type SessionStore struct {
+	db *sql.DB
+}
+
+// NewSessionStore creates a session store using the given database.
+func NewSessionStore(db *DB) *SessionStore {
+	return &SessionStore{db: db.Conn()}
+}
+
+// Save stores a session under its name, replacing any existing one.
+func (ss *SessionStore) Save(s Session) error {
+	if s.Name == "" {
+		return fmt.Errorf("session name is empty")
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("marshaling session: %w", err)
+	}
+
+	_, err = ss.db.Exec(
+		`INSERT INTO sessions (name, data, updated_at) VALUES (?, ?, datetime('now'))
+		 ON CONFLICT(name) DO UPDATE SET data = excluded.data, updated_at = excluded.updated_at`,
+		s.Name, string(data),
+	)
+	if err != nil {
+		return fmt.Errorf("saving session: %w", err)
+	}
+	return nil
+}
+
+// Load returns the session with the given name.
+func (ss *SessionStore) Load(name string) (*Session, error) {
+	var data string
+	var updatedAt time.Time
+	err := ss.db.QueryRow(
+		`SELECT data, updated_at FROM sessions WHERE name = ?`, name,
+	).Scan(&data, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("no session named %q", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading session: %w", err)
+	}
+
+	var s Session
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		return nil, fmt.Errorf("parsing session: %w", err)
+	}
+	s.Name = name
+	s.UpdatedAt = updatedAt
+	return &s, nil
+}
+
+// List returns all saved sessions, most recently updated first.
+func (ss *SessionStore) List() []Session {
+	rows, err := ss.db.Query(
+		`SELECT name, data, updated_at FROM sessions ORDER BY updated_at DESC`,
+	)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var sessions []Session
+	for rows.Next() {
+		var name, data string
+		var updatedAt time.Time
+		if err := rows.Scan(&name, &data, &updatedAt); err != nil {
+			continue
+		}
+		var s Session
+		if err := json.Unmarshal([]byte(data), &s); err != nil {
+			continue
+		}
+		s.Name = name
+		s.UpdatedAt = updatedAt
+		sessions = append(sessions, s)
+	}
+	return sessions
+}
+
+// Delete removes a session by name. Returns false if not found.
+func (ss *SessionStore) Delete(name string) bool {
+	res, err := ss.db.Exec(`DELETE FROM sessions WHERE name = ?`, name)
+	if err != nil {
+		return false
+	}
+	n, _ := res.RowsAffected()
+	return n > 0
+}
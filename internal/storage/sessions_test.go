@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSessionStore(t *testing.T) {
+	db, err := OpenDB(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	ss := NewSessionStore(db)
+	work := Session{
+		Name: "work",
+		Tabs: []SessionTab{
+			{URL: "https://go.dev/", Title: "Go", History: []string{"https://example.com/", "https://go.dev/"}, HistoryPos: 1, Scroll: 12},
+			{URL: "https://pkg.go.dev/", Title: "Packages", History: []string{"https://pkg.go.dev/"}},
+		},
+		ActiveTab: 1,
+		Split:     SessionSplit{Direction: 1, Ratio: 0.4, Panes: [2]int{0, 1}, Active: 1},
+	}
+	if err := ss.Save(work); err != nil {
+		t.Fatal(err)
+	}
+	if err := ss.Save(Session{}); err == nil {
+		t.Error("Save of a session without a name succeeded")
+	}
+
+	got, err := ss.Load("work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "work" || got.UpdatedAt.IsZero() || got.ActiveTab != 1 || got.Split != work.Split {
+		t.Errorf("Load = %+v", got)
+	}
+	if len(got.Tabs) != 2 || !slices.Equal(got.Tabs[0].History, work.Tabs[0].History) ||
+		got.Tabs[0].HistoryPos != 1 || got.Tabs[0].Scroll != 12 {
+		t.Errorf("loaded tabs = %+v", got.Tabs)
+	}
+
+	// Saving under the same name replaces the session.
+	work.Tabs = work.Tabs[:1]
+	if err := ss.Save(work); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ss.Load("work"); len(got.Tabs) != 1 {
+		t.Errorf("after a second Save, %d tabs, want 1", len(got.Tabs))
+	}
+
+	ss.Save(Session{Name: LastSession})
+	var names []string
+	for _, s := range ss.List() {
+		names = append(names, s.Name)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{LastSession, "work"}) {
+		t.Errorf("List = %v", names)
+	}
+
+	if !ss.Delete("work") || ss.Delete("work") {
+		t.Error("Delete should succeed once")
+	}
+	if _, err := ss.Load("work"); err == nil {
+		t.Error("Load of a deleted session succeeded")
+	}
+}
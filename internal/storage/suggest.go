@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Suggestion is a visited or bookmarked page offered by the URL bar.
+type Suggestion struct {
+	URL        string
+	Title      string
+	Bookmarked bool
+	Score      int // frecency
+}
+
+// bookmarkBoost is the frecency a bookmark adds to a page, worth as much as
+// a visit in the last few days.
+const bookmarkBoost = 100
+
+// frecencyQuery scores each page by its visits, weighting each one by how
+// recent it is (so the score grows with the visit count and decays with
+// age), plus bookmarkBoost if it is bookmarked. Suggest adds a HAVING
+// clause per search term and the ordering.
+const frecencyQuery = `
+	SELECT url, MAX(title), SUM(score), MAX(bookmarked) FROM (
+		SELECT url, title, CASE
+			WHEN julianday('now') - julianday(visited_at) < 4  THEN 100
+			WHEN julianday('now') - julianday(visited_at) < 14 THEN 70
+			WHEN julianday('now') - julianday(visited_at) < 31 THEN 50
+			WHEN julianday('now') - julianday(visited_at) < 90 THEN 30
+			ELSE 10
+		END AS score, 0 AS bookmarked
+		FROM history
+		UNION ALL
+		SELECT url, title, ? AS score, 1 AS bookmarked FROM bookmarks
+	)
+	GROUP BY url`
+
+// Suggest returns up to limit visited or bookmarked pages whose URL or title
+// fuzzy-matches query, highest frecency first.
+func (hs *HistoryStore) Suggest(query string, limit int) []Suggestion {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 || limit <= 0 {
+		return nil
+	}
+
+	// SQLite narrows the pages down to likely matches; fuzzyMatch has the
+	// final say, as LIKE folds the case of ASCII letters only.
+	stmt := frecencyQuery
+	args := []any{bookmarkBoost}
+	for i, term := range terms {
+		if i == 0 {
+			stmt += "\n\tHAVING "
+		} else {
+			stmt += " AND "
+		}
+		stmt += `(url LIKE ? ESCAPE '\' OR MAX(title) LIKE ? ESCAPE '\')`
+		pattern := likePattern(term)
+		args = append(args, pattern, pattern)
+	}
+	stmt += "\n\tORDER BY SUM(score) DESC, MAX(bookmarked) DESC, url"
+
+	rows, err := hs.db.Query(stmt, args...)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var suggestions []Suggestion
+	for rows.Next() && len(suggestions) < limit {
+		var s Suggestion
+		if err := rows.Scan(&s.URL, &s.Title, &s.Score, &s.Bookmarked); err != nil {
+			continue
+		}
+		if fuzzyMatch(terms, strings.ToLower(s.URL), strings.ToLower(s.Title)) {
+			suggestions = append(suggestions, s)
+		}
+	}
+	return suggestions
+}
+
+// fuzzyMatch reports whether every term appears, in order but not
+// necessarily contiguously, in the URL or in the title.
+func fuzzyMatch(terms []string, url, title string) bool {
+	for _, term := range terms {
+		if !isSubsequence(term, url) && !isSubsequence(term, title) {
+			return false
+		}
+	}
+	return true
+}
+
+// likePattern returns a LIKE pattern for the strings containing the runes
+// of term in order. Runes outside ASCII match anything, since LIKE would
+// not fold their case; the pattern only has to let every match through.
+func likePattern(term string) string {
+	var sb strings.Builder
+	sb.WriteByte('%')
+	for _, r := range term {
+		if r >= utf8.RuneSelf {
+			continue
+		}
+		if r == '%' || r == '_' || r == '\\' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+		sb.WriteByte('%')
+	}
+	return sb.String()
+}
+
+// isSubsequence reports whether the runes of sub appear in s in order.
+func isSubsequence(sub, s string) bool {
+	for _, r := range sub {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(string(r)):]
+	}
+	return true
+}
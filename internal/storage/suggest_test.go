@@ -0,0 +1,59 @@
+package storage
+
+import "testing"
+
+func TestSuggest(t *testing.T) {
+	db, err := OpenDB(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	conn := db.Conn()
+	for _, v := range []struct{ url, title, age string }{
+		{"https://go.dev/doc/", "Documentation", "-1 day"},
+		{"https://go.dev/doc/", "Documentation", "-2 days"},
+		{"https://godoc.org/", "GoDoc", "-200 days"},
+		{"https://golang.org/", "The Go Programming Language", "-20 days"},
+		{"https://example.com/", "Example Domain", "-1 day"},
+	} {
+		if _, err := conn.Exec(
+			`INSERT INTO history (url, title, visited_at) VALUES (?, ?, datetime('now', ?))`,
+			v.url, v.title, v.age,
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+	NewBookmarkStore(db).Add("https://godoc.org/", "GoDoc")
+
+	got := NewHistoryStore(db).Suggest("gdoc", 10)
+	want := []string{"https://go.dev/doc/", "https://godoc.org/"}
+	if len(got) != len(want) {
+		t.Fatalf("Suggest returned %d results, want %d: %+v", len(got), len(want), got)
+	}
+	for i, s := range got {
+		if s.URL != want[i] {
+			t.Errorf("result %d = %s, want %s", i, s.URL, want[i])
+		}
+	}
+	if !got[1].Bookmarked || got[1].Score != 10+bookmarkBoost {
+		t.Errorf("bookmarked result = %+v", got[1])
+	}
+
+	if got := NewHistoryStore(db).Suggest("programming go", 10); len(got) != 1 || got[0].URL != "https://golang.org/" {
+		t.Errorf("title match = %+v", got)
+	}
+}
+
+func TestLikePattern(t *testing.T) {
+	tests := []struct{ term, want string }{
+		{"gdoc", "%g%d%o%c%"},
+		{"50%_", `%5%0%\%%\_%`},
+		{"café", "%c%a%f%"},
+	}
+	for _, tt := range tests {
+		if got := likePattern(tt.term); got != tt.want {
+			t.Errorf("likePattern(%q) = %q, want %q", tt.term, got, tt.want)
+		}
+	}
+}
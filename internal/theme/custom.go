@@ -0,0 +1,241 @@
+package theme
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// Custom themes are loaded from a directory, one theme per file:
+//
+//   - .toml and .json files set Theme colors by snake_case key (primary,
+//     text_dim, code_bg, ...). Colors left out are taken from the theme
+//     named by "base", or from the default theme.
+//   - .yaml and .yml files are base16 schemes (base00 through base0F).
+//
+// A theme is named by its "name" key or, failing that, its file name.
+
+// colorKeys maps theme file keys to the colors they set.
+var colorKeys = map[string]func(*Theme) *lipgloss.Color{
+	"primary":      func(t *Theme) *lipgloss.Color { return &t.Primary },
+	"secondary":    func(t *Theme) *lipgloss.Color { return &t.Secondary },
+	"accent":       func(t *Theme) *lipgloss.Color { return &t.Accent },
+	"text":         func(t *Theme) *lipgloss.Color { return &t.Text },
+	"text_dim":     func(t *Theme) *lipgloss.Color { return &t.TextDim },
+	"text_bright":  func(t *Theme) *lipgloss.Color { return &t.TextBright },
+	"background":   func(t *Theme) *lipgloss.Color { return &t.Background },
+	"surface":      func(t *Theme) *lipgloss.Color { return &t.Surface },
+	"border":       func(t *Theme) *lipgloss.Color { return &t.Border },
+	"border_focus": func(t *Theme) *lipgloss.Color { return &t.BorderFocus },
+	"link":         func(t *Theme) *lipgloss.Color { return &t.Link },
+	"link_index":   func(t *Theme) *lipgloss.Color { return &t.LinkIndex },
+	"heading":      func(t *Theme) *lipgloss.Color { return &t.Heading },
+	"code":         func(t *Theme) *lipgloss.Color { return &t.Code },
+	"code_bg":      func(t *Theme) *lipgloss.Color { return &t.CodeBg },
+	"quote":        func(t *Theme) *lipgloss.Color { return &t.Quote },
+	"error":        func(t *Theme) *lipgloss.Color { return &t.Error },
+	"success":      func(t *Theme) *lipgloss.Color { return &t.Success },
+	"warning":      func(t *Theme) *lipgloss.Color { return &t.Warning },
+	"info":         func(t *Theme) *lipgloss.Color { return &t.Info },
+	"tab_active":   func(t *Theme) *lipgloss.Color { return &t.TabActive },
+	"tab_inactive": func(t *Theme) *lipgloss.Color { return &t.TabInactive },
+}
+
+// base16Slots maps each theme color to the base16 slot it is taken from:
+// 00-07 run from the darkest background to the brightest foreground, 08-0F
+// are red, orange, yellow, green, cyan, blue, magenta and brown.
+var base16Slots = map[string]string{
+	"background":   "base00",
+	"surface":      "base01",
+	"code_bg":      "base01",
+	"border":       "base02",
+	"text_dim":     "base03",
+	"tab_inactive": "base03",
+	"quote":        "base04",
+	"text":         "base05",
+	"text_bright":  "base07",
+	"error":        "base08",
+	"accent":       "base09",
+	"link_index":   "base0a",
+	"warning":      "base0a",
+	"success":      "base0b",
+	"code":         "base0b",
+	"secondary":    "base0c",
+	"link":         "base0d",
+	"info":         "base0d",
+	"primary":      "base0e",
+	"heading":      "base0e",
+	"border_focus": "base0e",
+	"tab_active":   "base0e",
+}
+
+// hexColorRe matches a hex color. The # is optional for six digits, as
+// base16 schemes leave it out; short colors need it to tell them apart from
+// ANSI color numbers.
+var hexColorRe = regexp.MustCompile(`^(#?[0-9a-fA-F]{6}|#[0-9a-fA-F]{3})$`)
+
+// LoadDir registers the custom themes defined in dir. A missing directory is
+// not an error; files that fail to load are skipped and reported together.
+func LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("reading themes: %w", err)
+	}
+
+	var errs []error
+	for _, e := range entries {
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".toml", ".json", ".yaml", ".yml":
+		default:
+			continue
+		}
+		t, err := LoadFile(filepath.Join(dir, e.Name()))
+		if err == nil {
+			err = Register(t)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("theme %s: %w", e.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LoadFile reads a custom theme from a TOML, JSON or base16 YAML file.
+func LoadFile(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		values := make(map[string]string)
+		if err := toml.Unmarshal(data, &values); err != nil {
+			return Theme{}, err
+		}
+		return parseTheme(name, values)
+	case ".json":
+		values := make(map[string]string)
+		if err := json.Unmarshal(data, &values); err != nil {
+			return Theme{}, err
+		}
+		return parseTheme(name, values)
+	case ".yaml", ".yml":
+		return parseBase16(name, data)
+	}
+	return Theme{}, fmt.Errorf("unsupported theme file: %s", filepath.Base(path))
+}
+
+// Register adds a custom theme. Built-in themes cannot be replaced.
+func Register(t Theme) error {
+	if t.Name == "" {
+		return errors.New("theme has no name")
+	}
+	if slices.Contains(builtin, t.Name) {
+		return fmt.Errorf("%q is a built-in theme", t.Name)
+	}
+	themes[t.Name] = t
+	return nil
+}
+
+// parseTheme builds a theme from TOML/JSON keys, starting from its base.
+func parseTheme(name string, values map[string]string) (Theme, error) {
+	base := Default
+	if b, ok := values["base"]; ok {
+		t, ok := themes[b]
+		if !ok {
+			return Theme{}, fmt.Errorf("unknown base theme %q", b)
+		}
+		base = t
+	}
+	if n := values["name"]; n != "" {
+		name = n
+	}
+
+	t := base
+	t.Name = themeName(name)
+	for key, value := range values {
+		if key == "name" || key == "base" {
+			continue
+		}
+		field, ok := colorKeys[key]
+		if !ok {
+			return Theme{}, fmt.Errorf("unknown key %q", key)
+		}
+		c, err := parseColor(value)
+		if err != nil {
+			return Theme{}, fmt.Errorf("%s: %w", key, err)
+		}
+		*field(&t) = c
+	}
+	return t, nil
+}
+
+// base16Scheme is a base16 scheme file. Older files list the slots at the
+// top level; newer ones nest them under "palette".
+type base16Scheme struct {
+	Palette map[string]string `yaml:"palette"`
+	Slots   map[string]string `yaml:",inline"`
+}
+
+// parseBase16 builds a theme from a base16 scheme, mapping its 16 slots onto
+// the theme colors.
+func parseBase16(name string, data []byte) (Theme, error) {
+	var scheme base16Scheme
+	if err := yaml.Unmarshal(data, &scheme); err != nil {
+		return Theme{}, err
+	}
+
+	slots := make(map[string]string)
+	for _, m := range []map[string]string{scheme.Slots, scheme.Palette} {
+		for k, v := range m {
+			slots[strings.ToLower(k)] = v
+		}
+	}
+
+	t := Theme{Name: themeName(name)}
+	for key, slot := range base16Slots {
+		value, ok := slots[slot]
+		if !ok {
+			return Theme{}, fmt.Errorf("missing %s", slot)
+		}
+		c, err := parseColor(value)
+		if err != nil {
+			return Theme{}, fmt.Errorf("%s: %w", slot, err)
+		}
+		*colorKeys[key](&t) = c
+	}
+	return t, nil
+}
+
+// parseColor accepts a hex color (#RRGGBB, RRGGBB or #RGB) or an ANSI color
+// number from 0 to 255.
+func parseColor(s string) (lipgloss.Color, error) {
+	s = strings.TrimSpace(s)
+	if hexColorRe.MatchString(s) {
+		return lipgloss.Color("#" + strings.TrimPrefix(s, "#")), nil
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 255 {
+		return lipgloss.Color(s), nil
+	}
+	return "", fmt.Errorf("invalid color %q", s)
+}
+
+// themeName turns a file or theme name into one usable with :theme.
+func themeName(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), "-")
+}
@@ -0,0 +1,84 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"house.toml": `
+name = "House Style"
+base = "nord"
+heading = "#FF8800"
+link = "33"
+`,
+		"paper.json": `{"background": "#FFF", "text": "#222222"}`,
+		"ocean.yaml": `
+scheme: "Ocean"
+author: "someone"
+base00: "2b303b"
+base01: "343d46"
+base02: "4f5b66"
+base03: "65737e"
+base04: "a7adba"
+base05: "c0c5ce"
+base06: "dfe1e8"
+base07: "eff1f5"
+base08: "bf616a"
+base09: "d08770"
+base0A: "ebcb8b"
+base0B: "a3be8c"
+base0C: "96b5b4"
+base0D: "8fa1b3"
+base0E: "b48ead"
+base0F: "ab7967"
+`,
+		"broken.toml": `link = "not-a-color"`,
+		"nord.json":   `{"text": "#FFFFFF"}`,
+		"notes.txt":   `ignored`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := LoadDir(dir)
+	if err == nil {
+		t.Error("LoadDir: expected errors for broken.toml and nord.json")
+	}
+
+	house, ok := themes["house-style"]
+	if !ok {
+		t.Fatalf("house-style not registered; have %v", List())
+	}
+	if house.Heading != "#FF8800" || house.Link != "33" || house.Background != Nord.Background {
+		t.Errorf("house-style = %+v", house)
+	}
+
+	if paper := themes["paper"]; paper.Background != "#FFF" || paper.Quote != Default.Quote {
+		t.Errorf("paper = %+v", paper)
+	}
+
+	ocean := themes["ocean"]
+	if ocean.Background != "#2b303b" || ocean.Link != "#8fa1b3" || ocean.Heading != "#b48ead" {
+		t.Errorf("ocean = %+v", ocean)
+	}
+
+	if _, ok := themes["broken"]; ok {
+		t.Error("broken theme was registered")
+	}
+	if Nord.Text == "#FFFFFF" || themes["nord"].Text == "#FFFFFF" {
+		t.Error("built-in nord was replaced")
+	}
+
+	list := List()
+	if list[0] != "default" || !slices.Contains(list, "ocean") || !Set("ocean") {
+		t.Errorf("List() = %v", list)
+	}
+	Set("default")
+}
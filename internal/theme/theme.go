@@ -1,6 +1,10 @@
 package theme
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 // Theme defines the color palette for the TUI.
 type Theme struct {
@@ -39,6 +43,9 @@ type Theme struct {
 	TabInactive lipgloss.Color
 }
 
+// builtin lists the built-in themes in display order.
+var builtin = []string{"default", "gruvbox", "catppuccin", "nord", "dracula", "solarized", "tokyonight"}
+
 var themes = map[string]Theme{
 	"default":    Default,
 	"gruvbox":    Gruvbox,
@@ -243,11 +250,16 @@ func Set(name string) bool {
 	return false
 }
 
-// List returns all available theme names.
+// List returns all available theme names: the built-in themes, then custom
+// themes in alphabetical order.
 func List() []string {
-	names := make([]string, 0, len(themes))
+	names := append([]string(nil), builtin...)
+	var custom []string
 	for name := range themes {
-		names = append(names, name)
+		if !slices.Contains(builtin, name) {
+			custom = append(custom, name)
+		}
 	}
-	return names
+	slices.Sort(custom)
+	return append(names, custom...)
 }
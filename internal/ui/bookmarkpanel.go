@@ -0,0 +1,345 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vidyasagar/tsurf/internal/storage"
+	"github.com/vidyasagar/tsurf/internal/theme"
+)
+
+// bookmarkRow is one line of the bookmark panel: a tag folder or a bookmark.
+type bookmarkRow struct {
+	depth    int
+	folder   *storage.BookmarkFolder
+	bookmark *storage.Bookmark
+}
+
+// BookmarkPanel displays the bookmark tree with vim navigation, for opening,
+// deleting, renaming and retagging bookmarks.
+type BookmarkPanel struct {
+	bookmarks []storage.Bookmark
+	filter    string          // tag the panel is limited to, if any
+	collapsed map[string]bool // folder paths that are folded
+	rows      []bookmarkRow
+	cursor    int
+	offset    int // scroll offset for visible window
+	width     int
+	height    int
+	visible   bool
+	lastKey   string // first key of gg or dd
+}
+
+// NewBookmarkPanel creates a new bookmark panel.
+func NewBookmarkPanel() BookmarkPanel {
+	return BookmarkPanel{collapsed: make(map[string]bool)}
+}
+
+// SetBookmarks updates the bookmarks displayed, limited to those with the
+// filter tag if it isn't empty. The cursor stays on the same row number.
+func (bp *BookmarkPanel) SetBookmarks(bookmarks []storage.Bookmark, filter string) {
+	bp.bookmarks = bookmarks
+	bp.filter = filter
+	bp.buildRows()
+}
+
+// Filter returns the tag the panel is limited to.
+func (bp *BookmarkPanel) Filter() string {
+	return bp.filter
+}
+
+// buildRows flattens the tree into rows, skipping folded folders' contents.
+func (bp *BookmarkPanel) buildRows() {
+	bookmarks := bp.bookmarks
+	if bp.filter != "" {
+		bookmarks = storage.FilterTag(bookmarks, bp.filter)
+	}
+
+	bp.rows = nil
+	var walk func(f *storage.BookmarkFolder, depth int)
+	walk = func(f *storage.BookmarkFolder, depth int) {
+		for _, sub := range f.Folders {
+			bp.rows = append(bp.rows, bookmarkRow{depth: depth, folder: sub})
+			if !bp.collapsed[sub.Path] {
+				walk(sub, depth+1)
+			}
+		}
+		for i := range f.Bookmarks {
+			bp.rows = append(bp.rows, bookmarkRow{depth: depth, bookmark: &f.Bookmarks[i]})
+		}
+	}
+	walk(storage.BookmarkTree(bookmarks), 0)
+
+	if bp.cursor >= len(bp.rows) {
+		bp.cursor = len(bp.rows) - 1
+	}
+	if bp.cursor < 0 {
+		bp.cursor = 0
+	}
+	bp.ensureVisible()
+}
+
+// SetSize updates the panel dimensions.
+func (bp *BookmarkPanel) SetSize(w, h int) {
+	bp.width = w
+	bp.height = h
+}
+
+// Show makes the panel visible.
+func (bp *BookmarkPanel) Show() {
+	bp.visible = true
+	bp.cursor = 0
+	bp.offset = 0
+	bp.lastKey = ""
+}
+
+// Hide closes the panel.
+func (bp *BookmarkPanel) Hide() {
+	bp.visible = false
+	bp.lastKey = ""
+}
+
+// IsVisible reports whether the panel is shown.
+func (bp *BookmarkPanel) IsVisible() bool {
+	return bp.visible
+}
+
+// CursorUp moves the cursor up one row.
+func (bp *BookmarkPanel) CursorUp() {
+	bp.lastKey = ""
+	if bp.cursor > 0 {
+		bp.cursor--
+		bp.ensureVisible()
+	}
+}
+
+// CursorDown moves the cursor down one row.
+func (bp *BookmarkPanel) CursorDown() {
+	bp.lastKey = ""
+	if bp.cursor < len(bp.rows)-1 {
+		bp.cursor++
+		bp.ensureVisible()
+	}
+}
+
+// GotoTop moves to the first row.
+func (bp *BookmarkPanel) GotoTop() {
+	bp.lastKey = ""
+	bp.cursor = 0
+	bp.offset = 0
+}
+
+// GotoBottom moves to the last row.
+func (bp *BookmarkPanel) GotoBottom() {
+	bp.lastKey = ""
+	if len(bp.rows) > 0 {
+		bp.cursor = len(bp.rows) - 1
+		bp.ensureVisible()
+	}
+}
+
+// HalfPageDown scrolls down half a page.
+func (bp *BookmarkPanel) HalfPageDown() {
+	bp.lastKey = ""
+	bp.cursor = min(bp.cursor+bp.visibleCount()/2, len(bp.rows)-1)
+	bp.cursor = max(bp.cursor, 0)
+	bp.ensureVisible()
+}
+
+// HalfPageUp scrolls up half a page.
+func (bp *BookmarkPanel) HalfPageUp() {
+	bp.lastKey = ""
+	bp.cursor = max(bp.cursor-bp.visibleCount()/2, 0)
+	bp.ensureVisible()
+}
+
+// HandleRepeatKey tracks doubled keys such as "gg" and "dd". It returns true
+// when key completes the pair.
+func (bp *BookmarkPanel) HandleRepeatKey(key string) bool {
+	if bp.lastKey == key {
+		bp.lastKey = ""
+		return true
+	}
+	bp.lastKey = key
+	return false
+}
+
+// ResetKeys forgets a pending first key of "gg" or "dd".
+func (bp *BookmarkPanel) ResetKeys() {
+	bp.lastKey = ""
+}
+
+// SelectedBookmark returns the bookmark at the cursor, or nil if the cursor
+// is on a folder or the panel is empty.
+func (bp *BookmarkPanel) SelectedBookmark() *storage.Bookmark {
+	if bp.cursor < 0 || bp.cursor >= len(bp.rows) || bp.rows[bp.cursor].bookmark == nil {
+		return nil
+	}
+	b := *bp.rows[bp.cursor].bookmark
+	return &b
+}
+
+// ToggleFolder folds or unfolds the folder at the cursor. Returns false if
+// the cursor isn't on a folder.
+func (bp *BookmarkPanel) ToggleFolder() bool {
+	if bp.cursor < 0 || bp.cursor >= len(bp.rows) || bp.rows[bp.cursor].folder == nil {
+		return false
+	}
+	path := bp.rows[bp.cursor].folder.Path
+	bp.collapsed[path] = !bp.collapsed[path]
+	bp.buildRows()
+	return true
+}
+
+// visibleCount returns how many rows fit in the visible area, below the
+// header and above the selected bookmark's URL and the key hints.
+func (bp *BookmarkPanel) visibleCount() int {
+	return max(bp.height-5, 1)
+}
+
+// ensureVisible adjusts offset so the cursor is within the visible window.
+func (bp *BookmarkPanel) ensureVisible() {
+	visible := bp.visibleCount()
+	if bp.cursor < bp.offset {
+		bp.offset = bp.cursor
+	}
+	if bp.cursor >= bp.offset+visible {
+		bp.offset = bp.cursor - visible + 1
+	}
+	if bp.offset < 0 {
+		bp.offset = 0
+	}
+}
+
+// View renders the bookmark panel.
+func (bp *BookmarkPanel) View() string {
+	if !bp.visible {
+		return ""
+	}
+
+	t := theme.Current
+
+	panelStyle := lipgloss.NewStyle().
+		Width(bp.width).
+		Height(bp.height).
+		Background(t.Background)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Primary).
+		Background(t.Surface).
+		Width(bp.width).
+		Padding(0, 1)
+
+	separatorStyle := lipgloss.NewStyle().
+		Foreground(t.Border)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(t.TextBright).
+		Background(t.TabActive).
+		Bold(true).
+		Width(bp.width).
+		Padding(0, 1)
+
+	folderStyle := lipgloss.NewStyle().
+		Foreground(t.Accent).
+		Bold(true).
+		Width(bp.width).
+		Padding(0, 1)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(t.Text).
+		Width(bp.width).
+		Padding(0, 1)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(t.TextDim).
+		Padding(0, 1)
+
+	var sb strings.Builder
+
+	title := "🔖 Bookmarks"
+	if bp.filter != "" {
+		title += " tag:" + bp.filter
+	}
+	sb.WriteString(titleStyle.Render(title))
+	sb.WriteString("\n")
+
+	sepWidth := max(bp.width-2, 1)
+	sb.WriteString(separatorStyle.Render(strings.Repeat("─", sepWidth)))
+	sb.WriteString("\n")
+
+	if len(bp.rows) == 0 {
+		if bp.filter != "" {
+			sb.WriteString(dimStyle.Render("No bookmarks with this tag."))
+		} else {
+			sb.WriteString(dimStyle.Render("No bookmarks yet."))
+		}
+		sb.WriteString("\n")
+		return panelStyle.Render(sb.String())
+	}
+
+	end := min(bp.offset+bp.visibleCount(), len(bp.rows))
+	maxLen := max(bp.width-4, 10)
+
+	for i := bp.offset; i < end; i++ {
+		row := bp.rows[i]
+		indent := strings.Repeat("  ", row.depth)
+
+		var line string
+		if row.folder != nil {
+			arrow := "▾"
+			if bp.collapsed[row.folder.Path] {
+				arrow = "▸"
+			}
+			line = fmt.Sprintf("%s%s %s (%d)", indent, arrow, row.folder.Name, row.folder.Count())
+		} else {
+			name := row.bookmark.Title
+			if name == "" {
+				name = row.bookmark.URL
+			}
+			line = indent + "  " + name
+		}
+		if len(line) > maxLen {
+			line = line[:maxLen-3] + "..."
+		}
+
+		switch {
+		case i == bp.cursor:
+			sb.WriteString(selectedStyle.Render(line))
+		case row.folder != nil:
+			sb.WriteString(folderStyle.Render(line))
+		default:
+			sb.WriteString(normalStyle.Render(line))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Pad, then show the selected bookmark's URL and tags above the hints.
+	linesUsed := 2 + (end - bp.offset)
+	for i := linesUsed; i < bp.height-3; i++ {
+		sb.WriteString("\n")
+	}
+	if b := bp.SelectedBookmark(); b != nil {
+		url := b.URL
+		if len(url) > maxLen {
+			url = url[:maxLen-3] + "..."
+		}
+		sb.WriteString(dimStyle.Render(url))
+		sb.WriteString("\n")
+		if len(b.Tags) > 0 {
+			sb.WriteString(dimStyle.Render("+" + strings.Join(b.Tags, " +")))
+		}
+		sb.WriteString("\n")
+	} else {
+		sb.WriteString("\n\n")
+	}
+	hintStyle := lipgloss.NewStyle().
+		Foreground(t.TextDim).
+		Italic(true).
+		Padding(0, 1)
+	sb.WriteString(hintStyle.Render("Enter:open/fold  dd:del  r:rename  t:tags  Esc:close"))
+
+	return panelStyle.Render(sb.String())
+}
@@ -1,11 +1,14 @@
 package ui
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/vidyasagar/tsurf/internal/theme"
 )
 
@@ -17,6 +20,8 @@ const (
 	CommandEx                 // : commands
 	CommandSearch             // / search
 	CommandFollow             // f link follow
+	CommandField              // i form field selection
+	CommandEdit               // editing a form field value
 )
 
 // CommandResult is emitted when a command is submitted.
@@ -25,6 +30,16 @@ type CommandResult struct {
 	Value string
 }
 
+// Completer returns the completions for an ex command line: the byte offset
+// where the word being completed starts, and the words that can replace it.
+type Completer func(line string) (start int, candidates []string)
+
+// maxCommandHistory bounds the ex command history kept in memory.
+const maxCommandHistory = 500
+
+// maxMenuRows is the height of the completion and history popup.
+const maxMenuRows = 8
+
 // CommandBar handles vim-style : commands, / search, and f link following.
 type CommandBar struct {
 	input      textinput.Model
@@ -33,6 +48,13 @@ type CommandBar struct {
 	width      int
 	history    []string
 	historyPos int
+
+	// Popup menu above the input: completion candidates (wildmenu) or the
+	// command history (Ctrl+f). The selected entry is shown in the input.
+	menu        []string
+	menuSel     int
+	menuBase    string // input text before the completed word
+	menuHistory bool   // menu lists the command history
 }
 
 // NewCommandBar creates a new command bar.
@@ -52,12 +74,18 @@ func (c *CommandBar) SetWidth(w int) {
 	c.input.Width = w - 4
 }
 
+// SetHistory replaces the ex command history, oldest first.
+func (c *CommandBar) SetHistory(lines []string) {
+	c.history = lines
+}
+
 // Open activates the command bar in the given mode.
 func (c *CommandBar) Open(ct CommandType) tea.Cmd {
 	c.active = true
 	c.cmdType = ct
 	c.input.Reset()
 	c.historyPos = -1
+	c.CloseMenu()
 
 	switch ct {
 	case CommandEx:
@@ -67,17 +95,45 @@ func (c *CommandBar) Open(ct CommandType) tea.Cmd {
 		c.input.Placeholder = "search..."
 		c.input.Prompt = "/"
 	case CommandFollow:
-		c.input.Placeholder = "link #..."
+		c.input.Placeholder = "hint or link #..."
 		c.input.Prompt = "f"
+	case CommandField:
+		c.input.Placeholder = "field #..."
+		c.input.Prompt = "i"
 	}
 
 	return c.input.Focus()
 }
 
+// OpenEdit activates the command bar as an editor for a form field value.
+// Password values are masked.
+func (c *CommandBar) OpenEdit(label, value string, password bool) tea.Cmd {
+	c.active = true
+	c.cmdType = CommandEdit
+	c.input.Reset()
+	c.historyPos = -1
+
+	c.input.Placeholder = ""
+	c.input.Prompt = label + ": "
+	if password {
+		c.input.EchoMode = textinput.EchoPassword
+	}
+	c.SetValue(value)
+
+	return c.input.Focus()
+}
+
+// SetPrompt changes the prompt of the open command bar.
+func (c *CommandBar) SetPrompt(prompt string) {
+	c.input.Prompt = prompt
+}
+
 // Close deactivates the command bar.
 func (c *CommandBar) Close() {
+	c.CloseMenu()
 	c.active = false
 	c.cmdType = CommandNone
+	c.input.EchoMode = textinput.EchoNormal
 	c.input.Blur()
 	c.input.Reset()
 }
@@ -93,6 +149,11 @@ func (c *CommandBar) SetValue(val string) {
 	c.input.SetCursor(len(val))
 }
 
+// Value returns the current (unsubmitted) input text.
+func (c *CommandBar) Value() string {
+	return c.input.Value()
+}
+
 // Type returns the current command type.
 func (c *CommandBar) Type() CommandType {
 	return c.cmdType
@@ -100,14 +161,21 @@ func (c *CommandBar) Type() CommandType {
 
 // Submit returns the command result and adds to history.
 func (c *CommandBar) Submit() CommandResult {
-	val := strings.TrimSpace(c.input.Value())
+	val := c.input.Value()
+	if c.cmdType != CommandEdit {
+		val = strings.TrimSpace(val)
+	}
 	result := CommandResult{
 		Type:  c.cmdType,
 		Value: val,
 	}
 
 	if val != "" && c.cmdType == CommandEx {
+		c.history = slices.DeleteFunc(c.history, func(h string) bool { return h == val })
 		c.history = append(c.history, val)
+		if len(c.history) > maxCommandHistory {
+			c.history = c.history[1:]
+		}
 	}
 
 	c.Close()
@@ -129,7 +197,16 @@ func (c *CommandBar) Update(msg tea.Msg) (*CommandBar, tea.Cmd) {
 		case tea.KeyEnter:
 			// Handled by the parent (app.go) to process the result.
 			return c, nil
+		case tea.KeyCtrlF:
+			if c.cmdType == CommandEx {
+				c.ShowHistory()
+				return c, nil
+			}
 		case tea.KeyUp:
+			if c.MenuOpen() {
+				c.moveMenu(-1)
+				return c, nil
+			}
 			// History navigation for ex commands.
 			if c.cmdType == CommandEx && len(c.history) > 0 {
 				if c.historyPos < len(c.history)-1 {
@@ -139,6 +216,10 @@ func (c *CommandBar) Update(msg tea.Msg) (*CommandBar, tea.Cmd) {
 			}
 			return c, nil
 		case tea.KeyDown:
+			if c.MenuOpen() {
+				c.moveMenu(1)
+				return c, nil
+			}
 			if c.cmdType == CommandEx && c.historyPos > 0 {
 				c.historyPos--
 				c.input.SetValue(c.history[len(c.history)-1-c.historyPos])
@@ -150,11 +231,102 @@ func (c *CommandBar) Update(msg tea.Msg) (*CommandBar, tea.Cmd) {
 		}
 	}
 
+	// Editing the line accepts the selected entry and closes the menu.
+	if _, ok := msg.(tea.KeyMsg); ok {
+		c.CloseMenu()
+	}
+
 	var cmd tea.Cmd
 	c.input, cmd = c.input.Update(msg)
 	return c, cmd
 }
 
+// Complete completes the last word of an ex command (Tab, or Shift+Tab for
+// dir < 0). With several candidates the first call opens the menu and later
+// ones step through it.
+func (c *CommandBar) Complete(dir int, completer Completer) {
+	if c.cmdType != CommandEx {
+		return
+	}
+	if c.MenuOpen() && !c.menuHistory {
+		c.moveMenu(dir)
+		return
+	}
+
+	line := c.input.Value()
+	start, candidates := completer(line)
+	switch len(candidates) {
+	case 0:
+		return
+	case 1:
+		c.CloseMenu()
+		c.SetValue(line[:start] + candidates[0])
+		return
+	}
+
+	c.menu = candidates
+	c.menuBase = line[:start]
+	c.menuHistory = false
+	c.menuSel = 0
+	if dir < 0 {
+		c.menuSel = len(candidates) - 1
+	}
+	c.SetValue(c.menuBase + c.menu[c.menuSel])
+}
+
+// ShowHistory opens the command history in the popup, newest at the bottom,
+// narrowed to the lines containing what has been typed so far.
+func (c *CommandBar) ShowHistory() {
+	filter := strings.TrimSpace(c.input.Value())
+	var lines []string
+	for _, h := range c.history {
+		if strings.Contains(h, filter) {
+			lines = append(lines, h)
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	c.menu = lines
+	c.menuBase = ""
+	c.menuHistory = true
+	c.menuSel = len(lines) - 1
+	c.SetValue(c.menu[c.menuSel])
+}
+
+// moveMenu selects the next (dir > 0) or previous menu entry, wrapping.
+func (c *CommandBar) moveMenu(dir int) {
+	c.menuSel = (c.menuSel + dir + len(c.menu)) % len(c.menu)
+	c.SetValue(c.menuBase + c.menu[c.menuSel])
+}
+
+// MenuOpen reports whether the completion or history popup is shown.
+func (c *CommandBar) MenuOpen() bool {
+	return len(c.menu) > 0
+}
+
+// CloseMenu hides the popup, keeping the selected entry in the input.
+// Returns false if it wasn't open.
+func (c *CommandBar) CloseMenu() bool {
+	if !c.MenuOpen() {
+		return false
+	}
+	c.menu = nil
+	c.menuSel = 0
+	c.menuBase = ""
+	c.menuHistory = false
+	return true
+}
+
+// Height returns the number of lines the command bar takes up.
+func (c *CommandBar) Height() int {
+	if !c.active {
+		return 0
+	}
+	return 1 + min(len(c.menu), maxMenuRows)
+}
+
 // View renders the command bar.
 func (c *CommandBar) View() string {
 	if !c.active {
@@ -168,5 +340,62 @@ func (c *CommandBar) View() string {
 		Background(t.Surface).
 		Width(c.width)
 
-	return barStyle.Render(c.input.View())
+	bar := barStyle.Render(c.input.View())
+	if !c.MenuOpen() {
+		return bar
+	}
+	return c.menuView() + "\n" + bar
+}
+
+// menuView renders the popup rows, scrolled to keep the selection visible
+// and lined up with the word being completed.
+func (c *CommandBar) menuView() string {
+	t := theme.Current
+
+	first := 0
+	if c.menuSel >= maxMenuRows {
+		first = c.menuSel - maxMenuRows + 1
+	}
+	last := min(first+maxMenuRows, len(c.menu))
+
+	itemWidth := 0
+	for _, item := range c.menu[first:last] {
+		itemWidth = max(itemWidth, lipgloss.Width(item))
+	}
+	itemWidth = min(itemWidth+2, max(c.width-2, 1))
+	indent := lipgloss.Width(c.input.Prompt) + lipgloss.Width(c.menuBase)
+	indent = max(min(indent, c.width-itemWidth), 0)
+
+	itemStyle := lipgloss.NewStyle().
+		Foreground(t.Text).
+		Background(t.Surface).
+		Width(itemWidth).
+		PaddingLeft(1)
+	selectedStyle := itemStyle.
+		Foreground(t.Background).
+		Background(t.Primary).
+		Bold(true)
+
+	// Entries past the visible rows are counted on the last row.
+	more := ""
+	if len(c.menu) > maxMenuRows {
+		more = lipgloss.NewStyle().
+			Foreground(t.TextDim).
+			Render(fmt.Sprintf(" %d/%d", c.menuSel+1, len(c.menu)))
+	}
+
+	var rows []string
+	pad := strings.Repeat(" ", indent)
+	for i := first; i < last; i++ {
+		style := itemStyle
+		if i == c.menuSel {
+			style = selectedStyle
+		}
+		row := pad + style.Render(ansi.Truncate(strings.TrimSpace(c.menu[i]), itemWidth-1, "…"))
+		if i == last-1 {
+			row += more
+		}
+		rows = append(rows, row)
+	}
+	return strings.Join(rows, "\n")
 }
@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/vidyasagar/tsurf/internal/browser"
+	"github.com/vidyasagar/tsurf/internal/theme"
+)
+
+// HintAlphabet holds the home-row letters used for link hint labels.
+const HintAlphabet = "asdfghjkl"
+
+// linkRefRe matches the [N] link references the renderers place after links.
+var linkRefRe = regexp.MustCompile(`\[(\d+)\]`)
+
+// Hint is a letter label overlaid on a visible [N] link reference.
+type Hint struct {
+	Label string
+	Link  int // the link number N
+
+	line  int
+	start int // first cell of the [N] reference
+	end   int // one past its last cell
+}
+
+// HintLabels returns n labels of equal length drawn from HintAlphabet. Equal
+// lengths keep the labels prefix-free, so a label is followed as soon as it
+// has been typed in full.
+func HintLabels(n int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	base := len(HintAlphabet)
+	length := 1
+	for capacity := base; capacity < n; capacity *= base {
+		length++
+	}
+
+	labels := make([]string, n)
+	digits := make([]int, length)
+	for i := range labels {
+		var sb strings.Builder
+		for _, d := range digits {
+			sb.WriteByte(HintAlphabet[d])
+		}
+		labels[i] = sb.String()
+
+		// Increment, least significant letter last.
+		for j := length - 1; j >= 0; j-- {
+			digits[j]++
+			if digits[j] < base {
+				break
+			}
+			digits[j] = 0
+		}
+	}
+	return labels
+}
+
+// findLinkRefs locates the [N] references in lines for which valid(N) is
+// true, for pages whose references were not recorded when rendered. Links
+// are numbered down the page, so a reference only counts when its number is
+// above the last one found: a "[1]" footnote in a comment further down does
+// not take the place of link 1.
+func findLinkRefs(lines []string, valid func(int) bool) []Hint {
+	var refs []Hint
+	last := 0
+	for i, line := range lines {
+		plain := ansi.Strip(line)
+		for _, loc := range linkRefRe.FindAllStringSubmatchIndex(plain, -1) {
+			n, err := strconv.Atoi(plain[loc[2]:loc[3]])
+			if err != nil || n <= last || !valid(n) {
+				continue
+			}
+			last = n
+			start := ansi.StringWidth(plain[:loc[0]])
+			refs = append(refs, Hint{
+				Link:  n,
+				line:  i,
+				start: start,
+				end:   start + ansi.StringWidth(plain[loc[0]:loc[1]]),
+			})
+		}
+	}
+	return refs
+}
+
+// refHints turns the link references recorded by the renderer into hints,
+// for those with valid(N).
+func refHints(refs []browser.LinkRef, valid func(int) bool) []Hint {
+	var hints []Hint
+	for _, r := range refs {
+		if valid(r.Link) {
+			hints = append(hints, Hint{Link: r.Link, line: r.Line, start: r.Start, end: r.End})
+		}
+	}
+	return hints
+}
+
+// overlayHints draws the hint labels over their link references on a styled
+// line. typed is the part of the label entered so far; it is shown dimmed.
+func overlayHints(line string, hints []Hint, typed string) string {
+	t := theme.Current
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(t.Background).
+		Background(t.Warning).
+		Bold(true)
+
+	typedStyle := lipgloss.NewStyle().
+		Foreground(t.TextDim).
+		Background(t.Warning)
+
+	reset := ""
+	if strings.Contains(line, "\x1b") {
+		reset = ansi.ResetStyle
+	}
+
+	var sb strings.Builder
+	pos := 0
+	for _, h := range hints {
+		if pos == 0 {
+			sb.WriteString(ansi.Truncate(line, h.start, ""))
+		} else {
+			sb.WriteString(ansi.Cut(line, pos, h.start))
+		}
+
+		rest := strings.TrimPrefix(h.Label, typed)
+		sb.WriteString(reset)
+		sb.WriteString(typedStyle.Render(typed) + labelStyle.Render(rest))
+		// Pad to the width of the reference so the line doesn't shift.
+		if pad := h.end - h.start - len(h.Label); pad > 0 {
+			sb.WriteString(strings.Repeat(" ", pad))
+		}
+		pos = h.end
+	}
+	sb.WriteString(ansi.TruncateLeft(line, pos, ""))
+
+	return sb.String()
+}
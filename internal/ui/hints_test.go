@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/vidyasagar/tsurf/internal/browser"
+)
+
+func TestHintLabels(t *testing.T) {
+	tests := []struct {
+		n      int
+		length int
+	}{
+		{1, 1},
+		{9, 1},
+		{10, 2},
+		{81, 2},
+		{82, 3},
+	}
+
+	for _, tt := range tests {
+		labels := HintLabels(tt.n)
+		if len(labels) != tt.n {
+			t.Errorf("HintLabels(%d) returned %d labels", tt.n, len(labels))
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, l := range labels {
+			if len(l) != tt.length {
+				t.Errorf("HintLabels(%d): label %q, want length %d", tt.n, l, tt.length)
+			}
+			if seen[l] {
+				t.Errorf("HintLabels(%d): duplicate label %q", tt.n, l)
+			}
+			seen[l] = true
+		}
+	}
+}
+
+func TestHintOverlay(t *testing.T) {
+	lines := []string{
+		"Go \x1b[1m[1]\x1b[0m and Rust [2]",
+		"not a link [7] and a footnote [1]",
+		"further down [3]",
+	}
+	valid := func(n int) bool { return n != 7 }
+
+	hints := findLinkRefs(lines, valid)
+	if len(hints) != 3 || hints[0].Link != 1 || hints[0].line != 0 || hints[2].Link != 3 {
+		t.Fatalf("findLinkRefs = %+v, want links 1, 2 and 3 (the footnote [1] skipped)", hints)
+	}
+
+	// Recorded references are taken as they are.
+	hints = refHints([]browser.LinkRef{
+		{Link: 1, Line: 0, Start: 3, End: 6},
+		{Link: 2, Line: 0, Start: 16, End: 19},
+		{Link: 7, Line: 1, Start: 11, End: 14},
+	}, valid)
+	if len(hints) != 2 || hints[0].Link != 1 || hints[1].Link != 2 {
+		t.Fatalf("refHints = %+v, want links 1 and 2", hints)
+	}
+	for i, label := range HintLabels(len(hints)) {
+		hints[i].Label = label
+	}
+
+	got := ansi.Strip(overlayHints(lines[0], hints, ""))
+	if want := "Go a   and Rust s  "; got != want {
+		t.Errorf("overlayHints = %q, want %q", got, want)
+	}
+}
@@ -29,69 +29,14 @@ type LeaderPanel struct {
 	groups  []LeaderGroup
 }
 
-// NewLeaderPanel creates a leader panel with the default shortcut groups.
+// NewLeaderPanel creates an empty leader panel; see SetGroups.
 func NewLeaderPanel() LeaderPanel {
-	return LeaderPanel{
-		groups: defaultLeaderGroups(),
-	}
+	return LeaderPanel{}
 }
 
-// defaultLeaderGroups returns the built-in shortcut groups.
-func defaultLeaderGroups() []LeaderGroup {
-	return []LeaderGroup{
-		{
-			Name: "Navigate",
-			Icon: "🧭",
-			Bindings: []LeaderBinding{
-				{Key: "o", Desc: "Open URL"},
-				{Key: "b", Desc: "Back"},
-				{Key: "f", Desc: "Forward"},
-				{Key: "l", Desc: "Follow link"},
-				{Key: "r", Desc: "Reload"},
-			},
-		},
-		{
-			Name: "Tabs",
-			Icon: "📑",
-			Bindings: []LeaderBinding{
-				{Key: "t", Desc: "New tab"},
-				{Key: "w", Desc: "Close tab"},
-				{Key: "n", Desc: "Next tab"},
-				{Key: "p", Desc: "Prev tab"},
-			},
-		},
-		{
-			Name: "Feeds",
-			Icon: "📡",
-			Bindings: []LeaderBinding{
-				{Key: "h", Desc: "Hacker News"},
-				{Key: "e", Desc: "Reddit"},
-				{Key: "s", Desc: "Search"},
-				{Key: "a", Desc: "RSS feed"},
-			},
-		},
-		{
-			Name: "Tools",
-			Icon: "🔧",
-			Bindings: []LeaderBinding{
-				{Key: "B", Desc: "Bookmarks"},
-				{Key: "R", Desc: "Read later"},
-				{Key: "/", Desc: "Search page"},
-				{Key: ":", Desc: "Command"},
-			},
-		},
-		{
-			Name: "Views",
-			Icon: "👁",
-			Bindings: []LeaderBinding{
-				{Key: "H", Desc: "History"},
-				{Key: "v", Desc: "Split vert"},
-				{Key: "x", Desc: "Close split"},
-				{Key: "T", Desc: "Theme cycle"},
-				{Key: "?", Desc: "Help"},
-			},
-		},
-	}
+// SetGroups sets the shortcut groups shown as the palette's columns.
+func (lp *LeaderPanel) SetGroups(groups []LeaderGroup) {
+	lp.groups = groups
 }
 
 // Show makes the panel visible.
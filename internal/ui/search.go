@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/vidyasagar/tsurf/internal/theme"
+)
+
+// searchMatch is a single match in the rendered content. Columns are measured
+// in terminal cells so they can be mapped back onto ANSI-styled lines.
+type searchMatch struct {
+	line  int
+	start int // first cell of the match
+	end   int // one past the last cell of the match
+}
+
+// CompileSearch builds the regexp for a / search pattern.
+// Patterns prefixed with \v are "very magic" regular expressions; anything
+// else is matched literally. Smart-case applies to both: the search ignores
+// case unless the pattern contains an uppercase letter.
+func CompileSearch(pattern string) (*regexp.Regexp, error) {
+	expr := regexp.QuoteMeta(pattern)
+	isRegex := strings.HasPrefix(pattern, `\v`)
+	if isRegex {
+		pattern = pattern[2:]
+		expr = pattern
+	}
+
+	if !hasUpper(pattern, isRegex) {
+		expr = "(?i)" + expr
+	}
+
+	return regexp.Compile(expr)
+}
+
+// hasUpper reports whether the pattern contains an uppercase letter. In regex
+// patterns, escaped characters such as \S or \W are not counted.
+func hasUpper(pattern string, isRegex bool) bool {
+	escaped := false
+	for _, r := range pattern {
+		if isRegex && !escaped && r == '\\' {
+			escaped = true
+			continue
+		}
+		if !escaped && unicode.IsUpper(r) {
+			return true
+		}
+		escaped = false
+	}
+	return false
+}
+
+// findMatches locates every non-empty match of re in the given lines,
+// ignoring ANSI escape sequences.
+func findMatches(lines []string, re *regexp.Regexp) []searchMatch {
+	var matches []searchMatch
+	for i, line := range lines {
+		plain := ansi.Strip(line)
+		for _, loc := range re.FindAllStringIndex(plain, -1) {
+			if loc[0] == loc[1] {
+				continue
+			}
+			start := ansi.StringWidth(plain[:loc[0]])
+			matches = append(matches, searchMatch{
+				line:  i,
+				start: start,
+				end:   start + ansi.StringWidth(plain[loc[0]:loc[1]]),
+			})
+		}
+	}
+	return matches
+}
+
+// highlightLine overlays match highlighting on a styled line. The text around
+// each match keeps its original escape sequences, so glamour styling resumes
+// after the highlight.
+func highlightLine(line string, matches []searchMatch, current *searchMatch) string {
+	t := theme.Current
+
+	matchStyle := lipgloss.NewStyle().
+		Foreground(t.Background).
+		Background(t.Warning)
+
+	currentStyle := lipgloss.NewStyle().
+		Foreground(t.Background).
+		Background(t.Primary).
+		Bold(true)
+
+	// Reset any active styling before a highlight so it doesn't bleed in.
+	// Plain lines carry no styling to reset.
+	reset := ""
+	if strings.Contains(line, "\x1b") {
+		reset = ansi.ResetStyle
+	}
+
+	var sb strings.Builder
+	pos := 0
+	for _, mt := range matches {
+		if pos == 0 {
+			sb.WriteString(ansi.Truncate(line, mt.start, ""))
+		} else {
+			sb.WriteString(ansi.Cut(line, pos, mt.start))
+		}
+
+		style := matchStyle
+		if current != nil && *current == mt {
+			style = currentStyle
+		}
+		sb.WriteString(reset)
+		sb.WriteString(style.Render(ansi.Strip(ansi.Cut(line, mt.start, mt.end))))
+		pos = mt.end
+	}
+	sb.WriteString(ansi.TruncateLeft(line, pos, ""))
+
+	return sb.String()
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestCompileSearchSmartCase(t *testing.T) {
+	tests := []struct {
+		pattern string
+		text    string
+		want    bool
+	}{
+		{"golang", "Golang is fun", true},
+		{"Golang", "golang is fun", false},
+		{"Golang", "Golang is fun", true},
+		{"a.b", "axb", false},
+		{`\vgo+`, "GOOO", true},
+		{`\v\Sfoo`, "XFOO", true},
+		{`\vFoo\d`, "foo1", false},
+	}
+
+	for _, tt := range tests {
+		re, err := CompileSearch(tt.pattern)
+		if err != nil {
+			t.Fatalf("CompileSearch(%q): %v", tt.pattern, err)
+		}
+		if got := re.MatchString(tt.text); got != tt.want {
+			t.Errorf("CompileSearch(%q).MatchString(%q) = %v, want %v", tt.pattern, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestFindMatchesIgnoresANSI(t *testing.T) {
+	lines := []string{
+		"plain text here",
+		"\x1b[1mbold\x1b[0m and \x1b[38;5;42mtext\x1b[0m",
+	}
+
+	re, _ := CompileSearch("text")
+	matches := findMatches(lines, re)
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %d", len(matches))
+	}
+
+	want := searchMatch{line: 1, start: 9, end: 13}
+	if matches[1] != want {
+		t.Errorf("second match = %+v, want %+v", matches[1], want)
+	}
+}
+
+func TestHighlightLinePreservesText(t *testing.T) {
+	line := "\x1b[1mbold\x1b[0m and \x1b[38;5;42mtext\x1b[0m tail"
+
+	re, _ := CompileSearch("and")
+	matches := findMatches([]string{line}, re)
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %d", len(matches))
+	}
+
+	out := highlightLine(line, matches, &matches[0])
+	if got, want := ansi.Strip(out), ansi.Strip(line); got != want {
+		t.Errorf("highlighted text = %q, want %q", got, want)
+	}
+	if !strings.Contains(out, "\x1b[38;5;42m") {
+		t.Error("styling after the match should be preserved")
+	}
+}
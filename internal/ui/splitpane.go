@@ -10,8 +10,8 @@ type SplitDirection int
 
 const (
 	SplitNone       SplitDirection = iota
-	SplitVertical                          // side by side
-	SplitHorizontal                        // top and bottom
+	SplitVertical                  // side by side
+	SplitHorizontal                // top and bottom
 )
 
 // SplitPane manages a split view with two content areas.
@@ -62,7 +62,28 @@ func (sp *SplitPane) Toggle() {
 	}
 }
 
-// FirstPaneDimensions returns the width and height for the first pane.
+// SetRatio sets the proportion of the first pane, clamped to 0.1-0.9.
+func (sp *SplitPane) SetRatio(r float64) {
+	if r < 0.1 {
+		r = 0.1
+	}
+	if r > 0.9 {
+		r = 0.9
+	}
+	sp.Ratio = r
+}
+
+// firstPaneSize returns the outer size (including border) of the first pane
+// along the split axis.
+func (sp *SplitPane) firstPaneSize() int {
+	if sp.Direction == SplitHorizontal {
+		return int(float64(sp.height) * sp.Ratio)
+	}
+	return int(float64(sp.width) * sp.Ratio)
+}
+
+// FirstPaneDimensions returns the content width and height for the first pane.
+// When split, each pane is drawn inside a one-cell border.
 func (sp *SplitPane) FirstPaneDimensions() (int, int) {
 	if !sp.IsSplit() {
 		return sp.width, sp.height
@@ -70,17 +91,15 @@ func (sp *SplitPane) FirstPaneDimensions() (int, int) {
 
 	switch sp.Direction {
 	case SplitVertical:
-		w := int(float64(sp.width) * sp.Ratio) - 1 // -1 for border
-		return w, sp.height
+		return clampSize(sp.firstPaneSize() - 2), clampSize(sp.height - 2)
 	case SplitHorizontal:
-		h := int(float64(sp.height) * sp.Ratio) - 1
-		return sp.width, h
+		return clampSize(sp.width - 2), clampSize(sp.firstPaneSize() - 2)
 	default:
 		return sp.width, sp.height
 	}
 }
 
-// SecondPaneDimensions returns the width and height for the second pane.
+// SecondPaneDimensions returns the content width and height for the second pane.
 func (sp *SplitPane) SecondPaneDimensions() (int, int) {
 	if !sp.IsSplit() {
 		return 0, 0
@@ -88,74 +107,59 @@ func (sp *SplitPane) SecondPaneDimensions() (int, int) {
 
 	switch sp.Direction {
 	case SplitVertical:
-		w := sp.width - int(float64(sp.width)*sp.Ratio) - 1
-		return w, sp.height
+		return clampSize(sp.width - sp.firstPaneSize() - 2), clampSize(sp.height - 2)
 	case SplitHorizontal:
-		h := sp.height - int(float64(sp.height)*sp.Ratio) - 1
-		return sp.width, h
+		return clampSize(sp.width - 2), clampSize(sp.height - sp.firstPaneSize() - 2)
 	default:
 		return 0, 0
 	}
 }
 
 // RenderSplit renders two content strings in a split layout.
+// The focused pane's border is drawn in the theme's focus color.
 func (sp *SplitPane) RenderSplit(first, second string) string {
 	if !sp.IsSplit() {
 		return first
 	}
 
-	t := theme.Current
+	w1, h1 := sp.FirstPaneDimensions()
+	w2, h2 := sp.SecondPaneDimensions()
 
-	borderStyle := lipgloss.NewStyle().
-		Foreground(t.Border)
+	firstPane := sp.renderPane(first, w1, h1, sp.Active == 0)
+	secondPane := sp.renderPane(second, w2, h2, sp.Active == 1)
 
-	switch sp.Direction {
-	case SplitVertical:
-		w1, _ := sp.FirstPaneDimensions()
-		w2, _ := sp.SecondPaneDimensions()
+	if sp.Direction == SplitHorizontal {
+		return lipgloss.JoinVertical(lipgloss.Left, firstPane, secondPane)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, firstPane, secondPane)
+}
 
-		leftStyle := lipgloss.NewStyle().
-			Width(w1).
-			Height(sp.height)
+// renderPane draws content clipped to w x h inside a border.
+func (sp *SplitPane) renderPane(content string, w, h int, focused bool) string {
+	t := theme.Current
 
-		rightStyle := lipgloss.NewStyle().
-			Width(w2).
-			Height(sp.height)
+	borderColor := t.Border
+	if focused {
+		borderColor = t.BorderFocus
+	}
 
-		divider := borderStyle.Render("│")
+	clipped := lipgloss.NewStyle().
+		MaxWidth(w).
+		MaxHeight(h).
+		Render(content)
 
-		return lipgloss.JoinHorizontal(
-			lipgloss.Top,
-			leftStyle.Render(first),
-			divider,
-			rightStyle.Render(second),
-		)
+	paneStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Width(w).
+		Height(h)
 
-	case SplitHorizontal:
-		_, h1 := sp.FirstPaneDimensions()
-		_, h2 := sp.SecondPaneDimensions()
-
-		topStyle := lipgloss.NewStyle().
-			Width(sp.width).
-			Height(h1)
-
-		bottomStyle := lipgloss.NewStyle().
-			Width(sp.width).
-			Height(h2)
-
-		dividerStr := ""
-		for i := 0; i < sp.width; i++ {
-			dividerStr += "─"
-		}
-		divider := borderStyle.Render(dividerStr)
-
-		return lipgloss.JoinVertical(
-			lipgloss.Left,
-			topStyle.Render(first),
-			divider,
-			bottomStyle.Render(second),
-		)
-	}
+	return paneStyle.Render(clipped)
+}
 
-	return first
+func clampSize(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
 }
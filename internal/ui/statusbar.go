@@ -17,6 +17,8 @@ type StatusBar struct {
 	linkCount  int
 	width      int
 	message    string // temporary status message
+	searchInfo string // e.g. "match 3/17"
+	offline    string // e.g. "OFFLINE (cached 3h ago)"; empty when online
 }
 
 // NewStatusBar creates a new status bar.
@@ -66,6 +68,18 @@ func (s *StatusBar) SetMessage(msg string) {
 	s.message = msg
 }
 
+// SetSearchInfo sets the search match indicator (e.g. "match 3/17").
+// An empty string hides it.
+func (s *StatusBar) SetSearchInfo(info string) {
+	s.searchInfo = info
+}
+
+// SetOffline sets the offline indicator (e.g. "OFFLINE (cached 3h ago)").
+// An empty string hides it.
+func (s *StatusBar) SetOffline(label string) {
+	s.offline = label
+}
+
 // View renders the status bar.
 func (s *StatusBar) View() string {
 	t := theme.Current
@@ -87,7 +101,7 @@ func (s *StatusBar) View() string {
 		modeStyle = modeStyle.
 			Foreground(t.Background).
 			Background(t.Accent)
-	case "FOLLOW":
+	case "FOLLOW", "FIELD":
 		modeStyle = modeStyle.
 			Foreground(t.Background).
 			Background(t.Link)
@@ -122,6 +136,8 @@ func (s *StatusBar) View() string {
 		modeIcon = "⌘ "
 	case "FOLLOW":
 		modeIcon = "🔗 "
+	case "FIELD":
+		modeIcon = "✎ "
 	case "SEARCH":
 		modeIcon = "🔍 "
 	case "HISTORY":
@@ -131,6 +147,15 @@ func (s *StatusBar) View() string {
 	}
 	mode = modeStyle.Render(modeIcon + s.mode)
 
+	if s.offline != "" {
+		offlineStyle := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(t.Background).
+			Background(t.Error).
+			Padding(0, 1)
+		mode += offlineStyle.Render(s.offline)
+	}
+
 	barStyle := lipgloss.NewStyle().
 		Foreground(t.Text).
 		Background(t.Surface)
@@ -165,6 +190,14 @@ func (s *StatusBar) View() string {
 		Background(t.Surface).
 		Padding(0, 1)
 
+	if s.searchInfo != "" {
+		searchStyle := lipgloss.NewStyle().
+			Foreground(t.Warning).
+			Background(t.Surface).
+			Padding(0, 1)
+		right += searchStyle.Render("🔍 " + s.searchInfo)
+	}
+
 	if s.linkCount > 0 {
 		right += rightStyle.Render(fmt.Sprintf("🔗 %d links", s.linkCount))
 	}
@@ -67,6 +67,22 @@ func (tb *TabBar) NewTab() int {
 	return tb.active
 }
 
+// NewBackgroundTab adds a tab after the current one without switching to it,
+// and returns the new tab's ID.
+func (tb *TabBar) NewBackgroundTab() int {
+	tb.nextID++
+	tab := Tab{
+		ID:    tb.nextID,
+		Title: "New Tab",
+	}
+	insertAt := tb.active + 1
+	if insertAt > len(tb.tabs) {
+		insertAt = len(tb.tabs)
+	}
+	tb.tabs = append(tb.tabs[:insertAt], append([]Tab{tab}, tb.tabs[insertAt:]...)...)
+	return tab.ID
+}
+
 // CloseTab closes the tab at the given index.
 func (tb *TabBar) CloseTab(idx int) bool {
 	if len(tb.tabs) <= 1 {
@@ -136,6 +152,49 @@ func (tb *TabBar) SetActiveURL(url string) {
 	}
 }
 
+// Tab returns the tab with the given ID, or nil if it does not exist.
+func (tb *TabBar) Tab(id int) *Tab {
+	for i := range tb.tabs {
+		if tb.tabs[i].ID == id {
+			return &tb.tabs[i]
+		}
+	}
+	return nil
+}
+
+// SetActive switches to the tab with the given ID. Returns false if not found.
+func (tb *TabBar) SetActive(id int) bool {
+	for i := range tb.tabs {
+		if tb.tabs[i].ID == id {
+			tb.active = i
+			return true
+		}
+	}
+	return false
+}
+
+// SetTitle sets the title of the tab with the given ID.
+func (tb *TabBar) SetTitle(id int, title string) {
+	if tab := tb.Tab(id); tab != nil {
+		if len(title) > 30 {
+			title = title[:27] + "..."
+		}
+		tab.Title = title
+	}
+}
+
+// SetURL sets the URL of the tab with the given ID.
+func (tb *TabBar) SetURL(id int, url string) {
+	if tab := tb.Tab(id); tab != nil {
+		tab.URL = url
+	}
+}
+
+// Tabs returns a copy of all tabs in display order.
+func (tb *TabBar) Tabs() []Tab {
+	return append([]Tab(nil), tb.tabs...)
+}
+
 // Count returns the number of tabs.
 func (tb *TabBar) Count() int {
 	return len(tb.tabs)
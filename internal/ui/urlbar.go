@@ -1,17 +1,31 @@
 package ui
 
 import (
+	"strings"
+
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/vidyasagar/tsurf/internal/storage"
 	"github.com/vidyasagar/tsurf/internal/theme"
 )
 
+// MaxSuggestions is the number of rows in the URL bar's suggestion dropdown.
+const MaxSuggestions = 8
+
 // URLBar is the URL input bar at the top of the browser.
 type URLBar struct {
-	input    textinput.Model
-	active   bool
-	width    int
+	input  textinput.Model
+	active bool
+	width  int
+
+	// Dropdown of history and bookmark matches for the typed text. Tab and
+	// Shift+Tab put the selected suggestion in the input; -1 is the text
+	// as typed.
+	suggestions []storage.Suggestion
+	selected    int
+	typed       string
 }
 
 // NewURLBar creates a new URL bar.
@@ -22,7 +36,8 @@ func NewURLBar() URLBar {
 	ti.Width = 60
 
 	return URLBar{
-		input: ti,
+		input:    ti,
+		selected: -1,
 	}
 }
 
@@ -35,6 +50,7 @@ func (u *URLBar) SetWidth(w int) {
 // Focus activates the URL bar for input.
 func (u *URLBar) Focus() tea.Cmd {
 	u.active = true
+	u.typed = u.input.Value()
 	return u.input.Focus()
 }
 
@@ -42,6 +58,7 @@ func (u *URLBar) Focus() tea.Cmd {
 func (u *URLBar) Blur() {
 	u.active = false
 	u.input.Blur()
+	u.CloseSuggestions()
 }
 
 // IsActive reports whether the URL bar is focused.
@@ -62,6 +79,56 @@ func (u *URLBar) SetValue(s string) {
 // Reset clears the URL bar.
 func (u *URLBar) Reset() {
 	u.input.Reset()
+	u.typed = ""
+	u.CloseSuggestions()
+}
+
+// Typed returns the text typed by the user, without the suggestion selected
+// with Tab.
+func (u *URLBar) Typed() string {
+	return u.typed
+}
+
+// SetSuggestions fills the dropdown, with nothing selected.
+func (u *URLBar) SetSuggestions(s []storage.Suggestion) {
+	if len(s) > MaxSuggestions {
+		s = s[:MaxSuggestions]
+	}
+	u.suggestions = s
+	u.selected = -1
+}
+
+// CloseSuggestions hides the dropdown, keeping the input as it is. Returns
+// false if it wasn't open.
+func (u *URLBar) CloseSuggestions() bool {
+	if len(u.suggestions) == 0 {
+		return false
+	}
+	u.suggestions = nil
+	u.selected = -1
+	return true
+}
+
+// Cycle selects the next (dir > 0) or previous suggestion, wrapping through
+// the typed text.
+func (u *URLBar) Cycle(dir int) {
+	n := len(u.suggestions)
+	if n == 0 {
+		return
+	}
+	// Positions run from -1 (typed text) to n-1.
+	u.selected = (u.selected+1+dir+n+1)%(n+1) - 1
+	if u.selected < 0 {
+		u.input.SetValue(u.typed)
+	} else {
+		u.input.SetValue(u.suggestions[u.selected].URL)
+	}
+	u.input.CursorEnd()
+}
+
+// Height returns the number of lines the URL bar takes up.
+func (u *URLBar) Height() int {
+	return 3 + len(u.suggestions)
 }
 
 // Update handles messages for the URL bar.
@@ -69,8 +136,24 @@ func (u *URLBar) Update(msg tea.Msg) (*URLBar, tea.Cmd) {
 	if !u.active {
 		return u, nil
 	}
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.Type {
+		case tea.KeyTab, tea.KeyDown:
+			u.Cycle(1)
+			return u, nil
+		case tea.KeyShiftTab, tea.KeyUp:
+			u.Cycle(-1)
+			return u, nil
+		}
+	}
+
 	var cmd tea.Cmd
 	u.input, cmd = u.input.Update(msg)
+	// Editing a selected suggestion makes it the typed text.
+	if v := u.input.Value(); v != u.typed {
+		u.typed = v
+		u.selected = -1
+	}
 	return u, cmd
 }
 
@@ -103,5 +186,52 @@ func (u *URLBar) View() string {
 
 	content := promptStyle.Render(" ") + " " + u.input.View()
 
-	return barStyle.Render(content)
+	bar := barStyle.Render(content)
+	if len(u.suggestions) == 0 {
+		return bar
+	}
+	return bar + "\n" + u.suggestionsView()
+}
+
+// suggestionsView renders the dropdown: one row per page with its title,
+// URL and a star for bookmarks.
+func (u *URLBar) suggestionsView() string {
+	t := theme.Current
+
+	rowStyle := lipgloss.NewStyle().
+		Background(t.Surface).
+		Width(u.width).
+		PaddingLeft(2)
+	titleStyle := lipgloss.NewStyle().Foreground(t.Text)
+	urlStyle := lipgloss.NewStyle().Foreground(t.Link)
+	starStyle := lipgloss.NewStyle().Foreground(t.Warning)
+	selectedStyle := rowStyle.
+		Background(t.Primary).
+		Foreground(t.Background).
+		Bold(true)
+
+	var rows []string
+	for i, s := range u.suggestions {
+		mark := "  "
+		if s.Bookmarked {
+			mark = "★ "
+		}
+		title := strings.TrimSpace(s.Title)
+		if i == u.selected {
+			line := mark + s.URL
+			if title != "" {
+				line = mark + title + "  " + s.URL
+			}
+			rows = append(rows, selectedStyle.Render(ansi.Truncate(line, u.width-2, "…")))
+			continue
+		}
+
+		line := starStyle.Render(mark)
+		if title != "" {
+			line += titleStyle.Render(title) + "  "
+		}
+		line += urlStyle.Render(s.URL)
+		rows = append(rows, rowStyle.Render(ansi.Truncate(line, u.width-2, "…")))
+	}
+	return strings.Join(rows, "\n")
 }
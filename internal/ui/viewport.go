@@ -2,11 +2,13 @@ package ui
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/vidyasagar/tsurf/internal/browser"
 	"github.com/vidyasagar/tsurf/internal/theme"
 )
 
@@ -14,9 +16,21 @@ import (
 type PageViewport struct {
 	viewport   viewport.Model
 	ready      bool
-	searchTerm string
 	totalLines int
 	contentSet bool
+	content    string   // unhighlighted content
+	lines      []string // content split into lines
+
+	// Search state.
+	searchTerm   string
+	searchRe     *regexp.Regexp
+	matches      []searchMatch
+	currentMatch int
+	searchOrigin int // YOffset when the search prompt was opened
+
+	// Link hint state.
+	hints     []Hint
+	hintTyped string
 }
 
 // NewPageViewport creates a new viewport (dimensions set on first WindowSizeMsg).
@@ -42,8 +56,14 @@ func (pv *PageViewport) SetContent(content string) {
 	if !pv.ready {
 		return
 	}
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	pv.content = content
+	pv.lines = strings.Split(content, "\n")
+	pv.matches = nil
+	pv.currentMatch = 0
+	pv.hints = nil
 	pv.viewport.SetContent(content)
-	pv.totalLines = strings.Count(content, "\n") + 1
+	pv.totalLines = len(pv.lines)
 	pv.contentSet = true
 	pv.viewport.GotoTop()
 }
@@ -132,6 +152,41 @@ func (pv *PageViewport) GotoBottom() {
 	}
 }
 
+// YOffset returns the index of the first visible line.
+func (pv *PageViewport) YOffset() int {
+	if !pv.ready {
+		return 0
+	}
+	return pv.viewport.YOffset
+}
+
+// SetYOffset scrolls so that line n is the first visible line.
+func (pv *PageViewport) SetYOffset(n int) {
+	if pv.ready {
+		pv.viewport.SetYOffset(n)
+	}
+}
+
+// SetScrollPercent scrolls to a fraction (0 to 1) of the scrollable height,
+// the inverse of ScrollPercent.
+func (pv *PageViewport) SetScrollPercent(pct float64) {
+	if !pv.ready {
+		return
+	}
+	scrollable := pv.viewport.TotalLineCount() - pv.viewport.Height
+	pv.viewport.SetYOffset(int(float64(scrollable) * pct))
+}
+
+// Content returns the content last passed to SetContent.
+func (pv *PageViewport) Content() string {
+	return pv.content
+}
+
+// HasContent reports whether any content has been set.
+func (pv *PageViewport) HasContent() bool {
+	return pv.contentSet
+}
+
 // Ready reports whether the viewport has been initialized.
 func (pv *PageViewport) Ready() bool {
 	return pv.ready
@@ -153,6 +208,265 @@ func (pv *PageViewport) Height() int {
 	return pv.viewport.Height
 }
 
+// StartSearch remembers the scroll position so an aborted search can restore it.
+func (pv *PageViewport) StartSearch() {
+	if pv.ready {
+		pv.searchOrigin = pv.viewport.YOffset
+	}
+}
+
+// Search highlights every match of pattern and scrolls to the first match at
+// or below the position where the search started. An empty pattern clears the
+// highlighting.
+func (pv *PageViewport) Search(pattern string) error {
+	if !pv.ready || !pv.contentSet {
+		return nil
+	}
+
+	pv.searchTerm = pattern
+	pv.searchRe = nil
+	pv.matches = nil
+	pv.currentMatch = 0
+
+	if pattern == "" {
+		pv.refreshContent()
+		pv.viewport.SetYOffset(pv.searchOrigin)
+		return nil
+	}
+
+	re, err := CompileSearch(pattern)
+	if err != nil {
+		pv.refreshContent()
+		return err
+	}
+	pv.searchRe = re
+	pv.matches = findMatches(pv.lines, re)
+
+	for i, mt := range pv.matches {
+		if mt.line >= pv.searchOrigin {
+			pv.currentMatch = i
+			break
+		}
+	}
+
+	pv.refreshContent()
+	if len(pv.matches) > 0 {
+		pv.scrollToMatch()
+	} else {
+		pv.viewport.SetYOffset(pv.searchOrigin)
+	}
+	return nil
+}
+
+// CancelSearch clears the highlighting and restores the pre-search position.
+func (pv *PageViewport) CancelSearch() {
+	pv.ClearSearch()
+	if pv.ready {
+		pv.viewport.SetYOffset(pv.searchOrigin)
+	}
+}
+
+// ClearSearch removes match highlighting but keeps the last pattern for n/N.
+func (pv *PageViewport) ClearSearch() {
+	if pv.matches == nil {
+		return
+	}
+	pv.matches = nil
+	pv.currentMatch = 0
+	pv.refreshContent()
+}
+
+// NextMatch moves to the next match, wrapping at the end.
+// Returns false if there are no matches; wrapped reports a wrap-around.
+func (pv *PageViewport) NextMatch() (ok, wrapped bool) {
+	return pv.stepMatch(1)
+}
+
+// PrevMatch moves to the previous match, wrapping at the start.
+func (pv *PageViewport) PrevMatch() (ok, wrapped bool) {
+	return pv.stepMatch(-1)
+}
+
+// MatchInfo returns the 1-based current match and the total match count.
+func (pv *PageViewport) MatchInfo() (current, total int) {
+	if len(pv.matches) == 0 {
+		return 0, 0
+	}
+	return pv.currentMatch + 1, len(pv.matches)
+}
+
+// SearchTerm returns the last search pattern.
+func (pv *PageViewport) SearchTerm() string {
+	return pv.searchTerm
+}
+
+func (pv *PageViewport) stepMatch(dir int) (ok, wrapped bool) {
+	if !pv.ready || !pv.contentSet {
+		return false, false
+	}
+
+	// Re-apply the last pattern if highlighting was cleared (e.g. :noh).
+	if len(pv.matches) == 0 && pv.searchRe != nil {
+		pv.matches = findMatches(pv.lines, pv.searchRe)
+		if len(pv.matches) == 0 {
+			return false, false
+		}
+		pv.currentMatch = pv.firstMatchFrom(pv.viewport.YOffset, dir)
+		pv.refreshContent()
+		pv.scrollToMatch()
+		return true, false
+	}
+	if len(pv.matches) == 0 {
+		return false, false
+	}
+
+	next := pv.currentMatch + dir
+	switch {
+	case next >= len(pv.matches):
+		next = 0
+		wrapped = true
+	case next < 0:
+		next = len(pv.matches) - 1
+		wrapped = true
+	}
+	pv.currentMatch = next
+	pv.refreshContent()
+	pv.scrollToMatch()
+	return true, wrapped
+}
+
+// firstMatchFrom returns the index of the first match at or after line when
+// searching forward, or at or before line when searching backward.
+func (pv *PageViewport) firstMatchFrom(line, dir int) int {
+	if dir < 0 {
+		for i := len(pv.matches) - 1; i >= 0; i-- {
+			if pv.matches[i].line <= line {
+				return i
+			}
+		}
+		return len(pv.matches) - 1
+	}
+	for i, mt := range pv.matches {
+		if mt.line >= line {
+			return i
+		}
+	}
+	return 0
+}
+
+// scrollToMatch brings the current match into view if it is off-screen.
+func (pv *PageViewport) scrollToMatch() {
+	line := pv.matches[pv.currentMatch].line
+	top := pv.viewport.YOffset
+	if line >= top && line < top+pv.viewport.Height {
+		return
+	}
+	pv.viewport.SetYOffset(line - pv.viewport.Height/3)
+}
+
+// ShowHints labels the link references visible in the viewport for which
+// valid(N) is true and overlays the labels on them. refs gives where the
+// references are when the page recorded them; otherwise they are looked for
+// in the text. Returns the hints.
+func (pv *PageViewport) ShowHints(refs []browser.LinkRef, valid func(int) bool) []Hint {
+	if !pv.ready || !pv.contentSet {
+		return nil
+	}
+
+	var all []Hint
+	if refs != nil {
+		all = refHints(refs, valid)
+	} else {
+		all = findLinkRefs(pv.lines, valid)
+	}
+	top := pv.viewport.YOffset
+	var hints []Hint
+	for _, h := range all {
+		if h.line >= top && h.line < top+pv.viewport.Height {
+			hints = append(hints, h)
+		}
+	}
+	for i, label := range HintLabels(len(hints)) {
+		hints[i].Label = label
+	}
+
+	pv.hints = hints
+	pv.hintTyped = ""
+	pv.refreshContent()
+	return hints
+}
+
+// FilterHints shows only the hints whose labels start with typed, and
+// returns them.
+func (pv *PageViewport) FilterHints(typed string) []Hint {
+	var matching []Hint
+	for _, h := range pv.hints {
+		if strings.HasPrefix(h.Label, typed) {
+			matching = append(matching, h)
+		}
+	}
+	pv.hintTyped = typed
+	pv.refreshContent()
+	return matching
+}
+
+// ClearHints removes the link hint overlay.
+func (pv *PageViewport) ClearHints() {
+	if pv.hints == nil {
+		return
+	}
+	pv.hints = nil
+	pv.hintTyped = ""
+	pv.refreshContent()
+}
+
+// refreshContent re-renders the content with the current match highlighting
+// and link hints without changing the scroll position.
+func (pv *PageViewport) refreshContent() {
+	if len(pv.matches) == 0 && len(pv.hints) == 0 {
+		pv.viewport.SetContent(pv.content)
+		return
+	}
+
+	lines := make([]string, len(pv.lines))
+	copy(lines, pv.lines)
+	pv.highlightMatches(lines)
+	pv.overlayHints(lines)
+
+	pv.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// highlightMatches applies search highlighting to lines.
+func (pv *PageViewport) highlightMatches(lines []string) {
+	if len(pv.matches) == 0 {
+		return
+	}
+
+	current := pv.matches[pv.currentMatch]
+	for i := 0; i < len(pv.matches); {
+		j := i
+		for j < len(pv.matches) && pv.matches[j].line == pv.matches[i].line {
+			j++
+		}
+		line := pv.matches[i].line
+		lines[line] = highlightLine(pv.lines[line], pv.matches[i:j], &current)
+		i = j
+	}
+}
+
+// overlayHints draws the link hints matching the typed prefix onto lines.
+func (pv *PageViewport) overlayHints(lines []string) {
+	byLine := make(map[int][]Hint)
+	for _, h := range pv.hints {
+		if strings.HasPrefix(h.Label, pv.hintTyped) {
+			byLine[h.line] = append(byLine[h.line], h)
+		}
+	}
+	for line, hints := range byLine {
+		lines[line] = overlayHints(lines[line], hints, pv.hintTyped)
+	}
+}
+
 func (pv *PageViewport) renderWelcome() string {
 	t := theme.Current
 